@@ -6,7 +6,9 @@
 package main
 
 import (
+	"errors"
 	"log"
+	"os"
 
 	"github.com/Pro100x3mal/gophkeeper/internal/client/app"
 	"github.com/Pro100x3mal/gophkeeper/internal/client/config"
@@ -32,13 +34,16 @@ func main() {
 		log.Fatalf("failed to initialize application: %v", err)
 	}
 
-	defer func() {
-		cerr := application.Close()
-		if cerr != nil {
-			log.Printf("failed to save cache: %v", cerr)
+	err = application.Run()
+	if cerr := application.Close(); cerr != nil {
+		log.Printf("failed to save cache: %v", cerr)
+	}
+	if err != nil {
+		// The run command passes on the exit code of its child, which has reported its own failure.
+		var childErr *app.ChildExitError
+		if !errors.As(err, &childErr) {
+			log.Printf("application failed: %v", err)
 		}
-	}()
-	if err = application.Run(); err != nil {
-		log.Fatalf("application failed: %v", err)
+		os.Exit(app.ExitCode(err))
 	}
 }
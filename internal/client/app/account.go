@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// accountOutput is the output schema of the account password and delete commands.
+type accountOutput struct {
+	Action string `json:"action"`
+}
+
+func (a *App) cmdAccount() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage your account",
+	}
+	cmd.AddCommand(a.cmdAccountPassword())
+	cmd.AddCommand(a.cmdAccountRename())
+	cmd.AddCommand(a.cmdAccountDelete())
+	return cmd
+}
+
+func (a *App) cmdAccountPassword() *cobra.Command {
+	var oldPassword, newPassword string
+	cmd := &cobra.Command{
+		Use:   "password",
+		Short: "Change password and log out other sessions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, err := a.api.ChangePassword(oldPassword, newPassword)
+			if err != nil {
+				return fmt.Errorf("failed to change password: %w", err)
+			}
+			a.cache.SetToken(token)
+			a.api.SetToken(token)
+			return a.render(cmd.OutOrStdout(), accountOutput{Action: "password"}, func(w io.Writer) {
+				fmt.Fprintln(w, "Password changed, other sessions are logged out")
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&oldPassword, "old", "", "Current password")
+	cmd.Flags().StringVar(&newPassword, "new", "", "New password")
+	_ = cmd.MarkFlagRequired("old")
+	_ = cmd.MarkFlagRequired("new")
+	return cmd
+}
+
+func (a *App) cmdAccountRename() *cobra.Command {
+	var username string
+	cmd := &cobra.Command{
+		Use:   "rename",
+		Short: "Change username",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.api.ChangeUsername(username)
+			if err != nil {
+				return fmt.Errorf("failed to change username: %w", err)
+			}
+			return a.render(cmd.OutOrStdout(), userOutput{Username: user.Username}, func(w io.Writer) {
+				fmt.Fprintf(w, "Username changed to %s\n", user.Username)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&username, "username", "", "New username")
+	_ = cmd.MarkFlagRequired("username")
+	return cmd
+}
+
+func (a *App) cmdAccountDelete() *cobra.Command {
+	var password string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete your account with all items",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("%w: deleting the account removes all items, pass --yes to confirm", errUsage)
+			}
+			if err := a.api.DeleteAccount(password); err != nil {
+				return fmt.Errorf("failed to delete account: %w", err)
+			}
+			a.cache.SetToken("")
+			a.api.SetToken("")
+			clear(a.cache.ItemsList())
+			return a.render(cmd.OutOrStdout(), accountOutput{Action: "delete"}, func(w io.Writer) {
+				fmt.Fprintln(w, "Account deleted")
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&password, "password", "", "Current password")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm the deletion")
+	_ = cmd.MarkFlagRequired("password")
+	return cmd
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdAccountPassword(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("ChangePassword", "old", "new").Return("new-token", nil)
+	mockAPI.On("SetToken", "new-token").Return()
+	mockCache.On("SetToken", "new-token").Return()
+
+	var out bytes.Buffer
+	cmd := app.cmdAccount()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"password", "--old", "old", "--new", "new"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Password changed")
+	mockAPI.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestCmdAccountPassword_WrongPassword(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("ChangePassword", "bad", "new").Return("", &services.APIError{StatusCode: 403, Message: "invalid password"})
+
+	cmd := app.cmdAccount()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"password", "--old", "bad", "--new", "new"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, ExitAuth, ExitCode(err))
+	mockCache.AssertNotCalled(t, "SetToken", mock.Anything)
+}
+
+func TestCmdAccountRename(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("ChangeUsername", "newname").Return(&models.User{Username: "newname"}, nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdAccount()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"rename", "--username", "newname"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Username changed to newname")
+}
+
+func TestCmdAccountRename_Taken(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("ChangeUsername", "taken").Return(nil, errors.New("status 409"))
+
+	cmd := app.cmdAccount()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"rename", "--username", "taken"})
+
+	assert.Error(t, cmd.Execute())
+}
+
+func TestCmdAccountDelete(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	items := map[string]models.Item{"id": {Title: "cached"}}
+	mockAPI.On("DeleteAccount", "password").Return(nil)
+	mockAPI.On("SetToken", "").Return()
+	mockCache.On("SetToken", "").Return()
+	mockCache.On("ItemsList").Return(items)
+
+	var out bytes.Buffer
+	cmd := app.cmdAccount()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"delete", "--password", "password", "--yes"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Account deleted")
+	assert.Empty(t, items, "cached items of the deleted account are removed")
+	mockCache.AssertExpectations(t)
+}
+
+func TestCmdAccountDelete_RequiresConfirmation(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	cmd := app.cmdAccount()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"delete", "--password", "password"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	mockAPI.AssertNotCalled(t, "DeleteAccount", mock.Anything)
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// errUserNotFound is returned when no user has the username given to an admin command.
+var errUserNotFound = errors.New("user not found")
+
+// adminUserOutput is the output schema of the admin users command.
+type adminUserOutput struct {
+	ID         uuid.UUID    `json:"id"`
+	Username   string       `json:"username"`
+	Role       models.Role  `json:"role"`
+	Disabled   bool         `json:"disabled"`
+	DisabledAt *time.Time   `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Usage      models.Usage `json:"usage"`
+}
+
+// adminActionOutput is the output schema of the admin commands changing a user.
+type adminActionOutput struct {
+	ID     uuid.UUID `json:"id"`
+	Action string    `json:"action"`
+}
+
+func (a *App) cmdAdmin() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Manage users (requires the admin role)",
+	}
+	cmd.AddCommand(a.cmdAdminUsers())
+	cmd.AddCommand(a.cmdAdminAction("disable", "Disable a user and log them out", "disabled", func(id uuid.UUID) error {
+		return a.api.SetUserDisabled(id, true)
+	}))
+	cmd.AddCommand(a.cmdAdminAction("enable", "Enable a disabled user", "enabled", func(id uuid.UUID) error {
+		return a.api.SetUserDisabled(id, false)
+	}))
+	cmd.AddCommand(a.cmdAdminAction("logout", "Revoke all tokens of a user", "logged out", a.api.LogoutUser))
+	cmd.AddCommand(a.cmdAdminDelete())
+	return cmd
+}
+
+func (a *App) cmdAdminUsers() *cobra.Command {
+	return &cobra.Command{
+		Use:   "users",
+		Short: "List users with their storage usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := a.api.ListUsers()
+			if err != nil {
+				return fmt.Errorf("failed to list users: %w", err)
+			}
+
+			res := make([]adminUserOutput, 0, len(users))
+			for _, u := range users {
+				res = append(res, adminUserOutput{
+					ID:         u.ID,
+					Username:   u.Username,
+					Role:       u.Role,
+					Disabled:   u.DisabledAt != nil,
+					DisabledAt: u.DisabledAt,
+					CreatedAt:  u.CreatedAt,
+					Usage:      u.Usage,
+				})
+			}
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				for _, u := range res {
+					status := "active"
+					if u.Disabled {
+						status = "disabled"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d items\t%s\n",
+						u.ID, u.Username, u.Role, status, u.Usage.Items, formatBytes(u.Usage.Bytes))
+				}
+			})
+		},
+	}
+}
+
+// cmdAdminAction creates a command applying an action to the user selected with --user.
+func (a *App) cmdAdminAction(use, short, done string, action func(id uuid.UUID) error) *cobra.Command {
+	var user string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := a.resolveUser(user)
+			if err != nil {
+				return err
+			}
+			if err = action(id); err != nil {
+				return fmt.Errorf("failed to %s user: %w", use, err)
+			}
+			return a.render(cmd.OutOrStdout(), adminActionOutput{ID: id, Action: use}, func(w io.Writer) {
+				fmt.Fprintf(w, "User %s: %s\n", done, id)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "User ID or username")
+	_ = cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+func (a *App) cmdAdminDelete() *cobra.Command {
+	var user string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a user with all their items",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("%w: deleting a user removes all their items, pass --yes to confirm", errUsage)
+			}
+			id, err := a.resolveUser(user)
+			if err != nil {
+				return err
+			}
+			if err = a.api.DeleteUser(id); err != nil {
+				return fmt.Errorf("failed to delete user: %w", err)
+			}
+			return a.render(cmd.OutOrStdout(), adminActionOutput{ID: id, Action: "delete"}, func(w io.Writer) {
+				fmt.Fprintf(w, "User deleted: %s\n", id)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "User ID or username")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm the deletion")
+	_ = cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+// resolveUser returns the ID of a user given by ID or username.
+// Usernames are looked up in the user list of the admin API.
+func (a *App) resolveUser(user string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(user); err == nil {
+		return id, nil
+	}
+
+	users, err := a.api.ListUsers()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, u := range users {
+		if u.Username == user {
+			return u.ID, nil
+		}
+	}
+	return uuid.Nil, fmt.Errorf("%w: %s", errUserNotFound, user)
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testUsers() []*models.UserInfo {
+	return []*models.UserInfo{
+		{User: models.User{ID: uuid.New(), Username: "alice", Role: models.RoleAdmin}, Usage: models.Usage{Items: 2, Bytes: 2048}},
+		{User: models.User{ID: uuid.New(), Username: "bob", Role: models.RoleUser}},
+	}
+}
+
+func TestCmdAdminUsers(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	users := testUsers()
+	mockAPI.On("ListUsers").Return(users, nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"users"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), users[0].ID.String()+"\talice\tadmin\tactive\t2 items\t2.0 KiB")
+	assert.Contains(t, out.String(), "bob\tuser\tactive")
+}
+
+func TestCmdAdminUsers_JSON(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	app.output = outputJSON
+	mockAPI.On("ListUsers").Return(testUsers(), nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"users"})
+
+	require.NoError(t, cmd.Execute())
+	var res []adminUserOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+	require.Len(t, res, 2)
+	assert.Equal(t, models.RoleAdmin, res[0].Role)
+	assert.False(t, res[1].Disabled)
+}
+
+func TestCmdAdminDisable_ByUsername(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	users := testUsers()
+	mockAPI.On("ListUsers").Return(users, nil)
+	mockAPI.On("SetUserDisabled", users[1].ID, true).Return(nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"disable", "--user", "bob"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "User disabled: "+users[1].ID.String())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdAdminEnable_ByID(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	id := uuid.New()
+	mockAPI.On("SetUserDisabled", id, false).Return(nil)
+
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"enable", "--user", id.String()})
+
+	require.NoError(t, cmd.Execute())
+	mockAPI.AssertNotCalled(t, "ListUsers")
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdAdminLogout_Forbidden(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	id := uuid.New()
+	mockAPI.On("LogoutUser", id).Return(&services.APIError{StatusCode: 403})
+
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"logout", "--user", id.String()})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, ExitAuth, ExitCode(err))
+}
+
+func TestCmdAdminAction_UnknownUser(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	mockAPI.On("ListUsers").Return(testUsers(), nil)
+
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"logout", "--user", "carol"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, ExitNotFound, ExitCode(err))
+}
+
+func TestCmdAdminDelete(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	id := uuid.New()
+	mockAPI.On("DeleteUser", id).Return(nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"delete", "--user", id.String(), "--yes"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "User deleted: "+id.String())
+}
+
+func TestCmdAdminDelete_RequiresConfirmation(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	cmd := app.cmdAdmin()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"delete", "--user", uuid.NewString()})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	mockAPI.AssertNotCalled(t, "DeleteUser", mock.Anything)
+}
@@ -9,10 +9,15 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
 	"github.com/Pro100x3mal/gophkeeper/internal/client/config"
 	"github.com/Pro100x3mal/gophkeeper/internal/client/repositories"
 	"github.com/Pro100x3mal/gophkeeper/internal/client/services"
@@ -42,15 +47,34 @@ type ApiService interface {
 	UpdateItem(id uuid.UUID, req *models.UpdateItemRequest) (*models.Item, error)
 	GetItem(id uuid.UUID) (*models.Item, *string, error)
 	ListItems() ([]*models.Item, error)
+	ListDue(days, olderThan int) ([]models.DueItem, error)
 	DeleteItem(id uuid.UUID) error
+	ExecuteBatch(req *models.BatchRequest) ([]models.BatchResult, error)
+	ListTrash() ([]*models.Item, error)
+	RestoreItem(id uuid.UUID) (*models.Item, error)
+	PurgeItem(id uuid.UUID) error
+	EmptyTrash() (int64, error)
+	GetUsage() (*models.UsageReport, error)
+	ChangePassword(oldPassword, newPassword string) (string, error)
+	ChangeUsername(username string) (*models.User, error)
+	DeleteAccount(password string) error
+	ListUsers() ([]*models.UserInfo, error)
+	SetUserDisabled(id uuid.UUID, disabled bool) error
+	LogoutUser(id uuid.UUID) error
+	DeleteUser(id uuid.UUID) error
 }
 
 // App represents the main client application with its dependencies.
 type App struct {
-	config *config.Config
-	logger *zap.Logger
-	api    ApiService
-	cache  CacheRepository
+	config    *config.Config
+	logger    *zap.Logger
+	api       ApiService
+	cache     CacheRepository
+	clipboard clipboard.Clipboard
+	// output is the format of command results selected with --output.
+	output string
+	// field selects a single field of command results with --field.
+	field string
 }
 
 // NewApp creates and initializes a new client application instance.
@@ -71,12 +95,19 @@ func NewApp(cfg *config.Config) (*App, error) {
 	client.SetTimeout(10 * time.Second)
 	api := services.NewAPIClient(client, cfg.ServerAddr)
 	api.SetToken(cache.GetToken())
+	log.Named("client").Debug("Tracing requests", zap.String("trace_id", api.TraceID()))
+
+	cb, err := clipboard.New(cfg.ClipboardBackend, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clipboard: %w", err)
+	}
 
 	return &App{
-		config: cfg,
-		logger: log.Named("client"),
-		api:    api,
-		cache:  cache,
+		config:    cfg,
+		logger:    log.Named("client"),
+		api:       api,
+		cache:     cache,
+		clipboard: cb,
 	}, nil
 }
 
@@ -96,21 +127,73 @@ func (a *App) Run() error {
 	root := &cobra.Command{
 		Use:   "gophkeeper",
 		Short: "Gophkeeper client",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(outputFormats, a.output) {
+				return fmt.Errorf("%w: %s, expected one of %s", errUnknownOutput, a.output, strings.Join(outputFormats, "|"))
+			}
+			return nil
+		},
 	}
+	root.PersistentFlags().StringVarP(&a.output, "output", "o", outputTable, "Output format ("+strings.Join(outputFormats, "|")+")")
+	root.PersistentFlags().StringVar(&a.field, "field", "", "Print only this field of the result, e.g. id or password")
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	})
 
 	root.AddCommand(a.cmdVersion())
 	root.AddCommand(a.cmdRegister())
 	root.AddCommand(a.cmdLogin())
-
+	root.AddCommand(a.cmdCreate())
+	root.AddCommand(a.cmdUpdate())
+	root.AddCommand(a.cmdGet())
+	root.AddCommand(a.cmdList())
+	root.AddCommand(a.cmdDelete())
+	root.AddCommand(a.cmdDue())
+	root.AddCommand(a.cmdImport())
+	root.AddCommand(a.cmdGenerate())
+	root.AddCommand(a.cmdHealthReport())
+	root.AddCommand(a.cmdOTP())
+	root.AddCommand(a.cmdTUI())
+	root.AddCommand(a.cmdRun())
+	root.AddCommand(a.cmdRender())
+	root.AddCommand(a.cmdSSHAgent())
+	root.AddCommand(a.cmdGitCredential())
+	root.AddCommand(a.cmdTrash())
+	root.AddCommand(a.cmdRestore())
+	root.AddCommand(a.cmdPurge())
+	root.AddCommand(a.cmdUsage())
+	root.AddCommand(a.cmdAccount())
+	root.AddCommand(a.cmdAdmin())
+
+	// The flags of the configuration are already parsed, cobra only sees the command.
+	args := a.config.Args
+	if filepath.Base(os.Args[0]) == gitCredentialHelper {
+		args = append([]string{"git-credential"}, args...)
+	}
+	root.SetArgs(args)
 	return root.Execute()
 }
 
+// versionOutput is the output schema of the version command.
+type versionOutput struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"build_date"`
+}
+
+// userOutput is the output schema of the register and login commands.
+type userOutput struct {
+	Username string `json:"username"`
+}
+
 func (a *App) cmdVersion() *cobra.Command {
 	return &cobra.Command{
 		Use:   "version",
 		Short: "Show build info",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("Version: %s, Build: %s\n", a.config.BuildVersion, a.config.BuildDate)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := versionOutput{Version: a.config.BuildVersion, BuildDate: a.config.BuildDate}
+			return a.render(cmd.OutOrStdout(), out, func(w io.Writer) {
+				fmt.Fprintf(w, "Version: %s, Build: %s\n", out.Version, out.BuildDate)
+			})
 		},
 	}
 }
@@ -127,7 +210,9 @@ func (a *App) cmdRegister() *cobra.Command {
 			}
 			a.cache.SetToken(token)
 			a.api.SetToken(token)
-			return nil
+			return a.render(cmd.OutOrStdout(), userOutput{Username: username}, func(w io.Writer) {
+				fmt.Fprintf(w, "Registered as %s\n", username)
+			})
 		},
 	}
 
@@ -150,7 +235,9 @@ func (a *App) cmdLogin() *cobra.Command {
 			}
 			a.cache.SetToken(token)
 			a.api.SetToken(token)
-			return nil
+			return a.render(cmd.OutOrStdout(), userOutput{Username: username}, func(w io.Writer) {
+				fmt.Fprintf(w, "Logged in as %s\n", username)
+			})
 		},
 	}
 
@@ -162,73 +249,109 @@ func (a *App) cmdLogin() *cobra.Command {
 }
 
 func (a *App) cmdCreate() *cobra.Command {
-	var typ, title, meta, filePath, data string
+	var typ, title, meta, filePath, data, expires, username, url string
+	var generate bool
+	var gen generatorFlags
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create new item",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var dataBase64 string
+			var rawData []byte
 
 			// Check that only one of --file or --data is provided
 			if filePath != "" && data != "" {
 				return errors.New("cannot use both --file and --data flags")
 			}
 
+			if generate {
+				if models.ItemType(typ) != models.ItemTypeCredential {
+					return errors.New("--generate is only supported for credential items")
+				}
+				if filePath != "" || data != "" {
+					return errors.New("cannot use --generate with --file or --data")
+				}
+				payload, err := generateCredential(&gen, username, url)
+				if err != nil {
+					return err
+				}
+				data = payload
+			}
+
 			switch {
 			case filePath != "":
-				rawData, err := os.ReadFile(filePath)
-				if err != nil {
+				var err error
+				if rawData, err = os.ReadFile(filePath); err != nil {
 					return fmt.Errorf("failed to read file: %w", err)
 				}
-				dataBase64 = base64.StdEncoding.EncodeToString(rawData)
 			case data != "":
-				dataBase64 = base64.StdEncoding.EncodeToString([]byte(data))
+				rawData = []byte(data)
 			}
 
 			if typ == "" {
 				return errors.New("type is required")
 			}
 			switch models.ItemType(typ) {
-			case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard:
+			case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard, models.ItemTypeSSHKey:
 			default:
 				return fmt.Errorf("unsupported type: %s", typ)
 			}
 
+			expiresAt, err := parseExpiry(expires)
+			if err != nil {
+				return err
+			}
+
+			switch models.ItemType(typ) {
+			case models.ItemTypeCredential:
+				a.warnPassword(cmd.ErrOrStderr(), rawData, title)
+			case models.ItemTypeSSHKey:
+				if rawData, err = sshKeyData(rawData); err != nil {
+					return err
+				}
+			}
+
 			req := &models.CreateItemRequest{
 				Type:       models.ItemType(typ),
 				Title:      title,
 				Metadata:   meta,
-				DataBase64: dataBase64,
+				DataBase64: base64.StdEncoding.EncodeToString(rawData),
+				ExpiresAt:  expiresAt,
 			}
 			item, err := a.api.CreateItem(req)
 			if err != nil {
 				return fmt.Errorf("failed to create item: %w", err)
 			}
-			fmt.Printf("Item created: %s\n", item.ID.String())
 			a.cache.ItemsList()[item.ID.String()] = *item
-			return nil
+			return a.render(cmd.OutOrStdout(), newItemOutput(*item), func(w io.Writer) {
+				fmt.Fprintf(w, "Item created: %s\n", item.ID)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&typ, "type", "", "Item type (credential|text|binary|card)")
+	cmd.Flags().StringVar(&typ, "type", "", "Item type (credential|text|binary|card|ssh-key)")
 	cmd.Flags().StringVar(&title, "title", "", "Item title")
 	cmd.Flags().StringVar(&meta, "meta", "", "Item metadata (plain text)")
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to file with item data")
 	cmd.Flags().StringVar(&data, "data", "", "Raw text data (alternative to --file)")
+	cmd.Flags().StringVar(&expires, "expires", "", "Expiration date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().BoolVar(&generate, "generate", false, "Generate the password of a credential item")
+	cmd.Flags().StringVar(&username, "username", "", "Username of the generated credential")
+	cmd.Flags().StringVar(&url, "url", "", "URL of the generated credential")
+	gen.register(cmd)
 	_ = cmd.MarkFlagRequired("type")
 	_ = cmd.MarkFlagRequired("title")
 	return cmd
 }
 
 func (a *App) cmdUpdate() *cobra.Command {
-	var rawID, typ, title, meta, filePath, data string
+	var rawID, typ, title, meta, filePath, data, expires string
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update existing item",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := parseID(rawID)
+			id, err := a.resolveItem(rawID)
 			if err != nil {
-				return fmt.Errorf("failed to parse item ID: %w", err)
+				return err
 			}
 
 			// Check that only one of --file or --data is provided
@@ -240,7 +363,7 @@ func (a *App) cmdUpdate() *cobra.Command {
 
 			if typ != "" {
 				switch models.ItemType(typ) {
-				case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard:
+				case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard, models.ItemTypeSSHKey:
 					t := models.ItemType(typ)
 					req.Type = &t
 				default:
@@ -256,86 +379,144 @@ func (a *App) cmdUpdate() *cobra.Command {
 				req.Metadata = &meta
 			}
 
+			var rawData []byte
 			switch {
 			case filePath != "":
-				rawData, err := os.ReadFile(filePath)
-				if err != nil {
+				if rawData, err = os.ReadFile(filePath); err != nil {
 					return fmt.Errorf("failed to read file: %w", err)
 				}
-				dataBase64 := base64.StdEncoding.EncodeToString(rawData)
-				req.DataBase64 = &dataBase64
 			case data != "":
-				dataBase64 := base64.StdEncoding.EncodeToString([]byte(data))
-				req.DataBase64 = &dataBase64
+				rawData = []byte(data)
 			}
 
-			if req.Type == nil && req.Title == nil && req.Metadata == nil && req.DataBase64 == nil {
+			if req.ExpiresAt, err = parseExpiry(expires); err != nil {
+				return err
+			}
+
+			if req.Type == nil && req.Title == nil && req.Metadata == nil && rawData == nil && req.ExpiresAt == nil {
 				return errors.New("nothing to update")
 			}
 
+			if rawData != nil {
+				// Fall back to the cached item for the type and title when they are not updated.
+				cached := a.cache.ItemsList()[id.String()]
+				itemType, itemTitle := cached.Type, cached.Title
+				if req.Type != nil {
+					itemType = *req.Type
+				}
+				if req.Title != nil {
+					itemTitle = *req.Title
+				}
+				switch itemType {
+				case models.ItemTypeCredential:
+					a.warnPassword(cmd.ErrOrStderr(), rawData, itemTitle)
+				case models.ItemTypeSSHKey:
+					if rawData, err = sshKeyData(rawData); err != nil {
+						return err
+					}
+				}
+				dataBase64 := base64.StdEncoding.EncodeToString(rawData)
+				req.DataBase64 = &dataBase64
+			}
+
 			item, err := a.api.UpdateItem(id, req)
 			if err != nil {
 				return fmt.Errorf("failed to update item: %w", err)
 			}
-			fmt.Printf("Item updated: %s\n", item.ID)
 			a.cache.ItemsList()[item.ID.String()] = *item
-			return nil
+			return a.render(cmd.OutOrStdout(), newItemOutput(*item), func(w io.Writer) {
+				fmt.Fprintf(w, "Item updated: %s\n", item.ID)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&rawID, "id", "", "Item ID")
-	cmd.Flags().StringVar(&typ, "type", "", "Item type (credential|text|binary|card)")
+	cmd.Flags().StringVar(&rawID, "id", "", itemRefUsage)
+	cmd.Flags().StringVar(&typ, "type", "", "Item type (credential|text|binary|card|ssh-key)")
 	cmd.Flags().StringVar(&title, "title", "", "Item title")
 	cmd.Flags().StringVar(&meta, "meta", "", "Item metadata (plain text)")
 	cmd.Flags().StringVar(&filePath, "file", "", "Path to file with item data")
 	cmd.Flags().StringVar(&data, "data", "", "Raw text data (alternative to --file)")
+	cmd.Flags().StringVar(&expires, "expires", "", "Expiration date (YYYY-MM-DD or RFC 3339)")
 	_ = cmd.MarkFlagRequired("id")
 	return cmd
 }
 
 func (a *App) cmdGet() *cobra.Command {
-	var rawID, outPath string
+	var rawID, outPath, field string
+	var reveal bool
+	var timeout time.Duration
 	cmd := &cobra.Command{
 		Use:   "get",
-		Short: "Get item by ID",
+		Short: "Get item by ID, title or folder/title",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := parseID(rawID)
+			id, err := a.resolveItem(rawID)
 			if err != nil {
-				return fmt.Errorf("failed to parse item ID: %w", err)
+				return err
+			}
+			if field != "" && outPath != "" {
+				return errors.New("cannot use both --copy and --out flags")
 			}
 
+			out := cmd.OutOrStdout()
 			item, data, err := a.api.GetItem(id)
 			if err != nil {
-				a.logger.Warn("Failed to get item from server, using cache", zap.Error(err))
-				if cachedItem, ok := a.cache.ItemsList()[id.String()]; ok {
-					fmt.Printf("%+v\nData: <not cached>\n", cachedItem)
-					return nil
+				cachedItem, ok := a.cache.ItemsList()[id.String()]
+				if !ok || field != "" || !errors.Is(err, services.ErrUnavailable) {
+					return fmt.Errorf("failed to get item: %w", err)
 				}
-				return fmt.Errorf("failed to get item: %w", err)
+				a.logger.Warn("Failed to get item from server, using cache", zap.Error(err))
+				res := newItemOutput(cachedItem)
+				return a.render(out, res, func(w io.Writer) {
+					res.printTable(w)
+					fmt.Fprintln(w, "Data: <not cached>")
+				})
 			}
-			fmt.Printf("%+v\n", *item)
 
+			var rawData []byte
 			if data != nil && *data != "" {
-				rawData, err := base64.StdEncoding.DecodeString(*data)
-				if err != nil {
+				if rawData, err = base64.StdEncoding.DecodeString(*data); err != nil {
 					return fmt.Errorf("failed to decode base64 data: %w", err)
 				}
+			}
 
-				if outPath != "" {
-					if err = os.WriteFile(outPath, rawData, 0644); err != nil {
-						return fmt.Errorf("failed to write data to file: %w", err)
-					}
-					fmt.Printf("Data saved to file: %s\n", outPath)
-				} else {
-					fmt.Printf("Data:\n%s\n", rawData)
+			if field != "" {
+				if !cmd.Flags().Changed("timeout") {
+					timeout = a.config.ClipboardTimeout
 				}
+				return a.copyField(cmd, item, rawData, field, timeout)
 			}
-			return nil
+
+			res := newItemOutput(*item)
+			if outPath != "" && len(rawData) > 0 {
+				if err = os.WriteFile(outPath, rawData, 0644); err != nil {
+					return fmt.Errorf("failed to write data to file: %w", err)
+				}
+				return a.render(out, res, func(w io.Writer) {
+					res.printTable(w)
+					fmt.Fprintf(w, "Data saved to file: %s\n", outPath)
+				})
+			}
+
+			// A field selected with --field is printed as is, like a field copied with --copy.
+			if !reveal && a.field == "" {
+				rawData = maskPayload(item.Type, rawData)
+			}
+			res = res.withData(rawData)
+			return a.render(out, res, func(w io.Writer) {
+				res.printTable(w)
+				if len(rawData) > 0 {
+					fmt.Fprintf(w, "Data:\n%s\n", rawData)
+				}
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&rawID, "id", "", "Item ID")
+	cmd.Flags().StringVar(&rawID, "id", "", itemRefUsage)
 	cmd.Flags().StringVar(&outPath, "out", "", "Path to save item data")
+	cmd.Flags().StringVar(&field, "copy", "", "Copy a field to the clipboard instead of printing (password|username|url|otp|number|holder|expiry|cvv|text)")
+	cmd.Flags().Lookup("copy").NoOptDefVal = fieldDefault
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Print passwords, card numbers and other secrets unmasked")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Clear the clipboard after this duration, 0 keeps it (defaults to CLIPBOARD_TIMEOUT)")
 	_ = cmd.MarkFlagRequired("id")
 	return cmd
 }
@@ -347,58 +528,134 @@ func (a *App) cmdList() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			items, err := a.api.ListItems()
 			if err != nil {
+				if !errors.Is(err, services.ErrUnavailable) {
+					return fmt.Errorf("failed to list items: %w", err)
+				}
 				a.logger.Warn("Failed to get items from server, using cache", zap.Error(err))
-				cachedItems := make([]models.Item, 0, len(a.cache.ItemsList()))
+				items = make([]*models.Item, 0, len(a.cache.ItemsList()))
 				for _, item := range a.cache.ItemsList() {
-					cachedItems = append(cachedItems, item)
-				}
-				sort.Slice(cachedItems, func(i, j int) bool {
-					return cachedItems[i].UpdatedAt.After(cachedItems[j].UpdatedAt)
-				})
-				for _, cached := range cachedItems {
-					fmt.Printf("%s\t%s\t%s\n", cached.ID, cached.Type, cached.Title)
+					items = append(items, &item)
 				}
-				return nil
 			}
 
-			sort.Slice(items, func(i, j int) bool {
-				if items[i] == nil || items[j] == nil {
-					return false
+			res := newItemOutputs(items)
+			sort.Slice(res, func(i, j int) bool {
+				return res[i].UpdatedAt.After(res[j].UpdatedAt)
+			})
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				for _, item := range res {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", item.ID, item.Type, item.Title)
 				}
-				return items[i].UpdatedAt.After(items[j].UpdatedAt)
 			})
-
-			for _, item := range items {
-				fmt.Printf("%s\t%s\t%s\n", item.ID, item.Type, item.Title)
-			}
-			return nil
 		},
 	}
 }
 
 func (a *App) cmdDelete() *cobra.Command {
-	var rawID string
+	var rawIDs []string
+	var atomic bool
 	cmd := &cobra.Command{
 		Use:   "delete",
-		Short: "Delete item by ID",
+		Short: "Move items to the trash",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := parseID(rawID)
+			ids, err := a.resolveItems(rawIDs)
 			if err != nil {
-				return fmt.Errorf("invalid ID format: %w", err)
+				return err
+			}
+
+			var res deleteOutput
+			if len(ids) == 1 {
+				if err = a.api.DeleteItem(ids[0]); err != nil {
+					return fmt.Errorf("failed to delete item: %w", err)
+				}
+				delete(a.cache.ItemsList(), ids[0].String())
+				res = deleteOutput{Deleted: ids, Failed: []deleteFailure{}}
+			} else {
+				res, err = a.deleteItems(ids, atomic)
 			}
-			if err = a.api.DeleteItem(id); err != nil {
-				return fmt.Errorf("failed to delete item: %w", err)
+
+			if renderErr := a.render(cmd.OutOrStdout(), res, res.printTable); renderErr != nil {
+				return renderErr
 			}
-			delete(a.cache.ItemsList(), id.String())
-			return nil
+			return err
 		},
 	}
 
-	cmd.Flags().StringVar(&rawID, "id", "", "Item ID")
+	cmd.Flags().StringSliceVar(&rawIDs, "id", nil, itemRefUsage+" (repeat or separate with commas to delete several items)")
+	cmd.Flags().BoolVar(&atomic, "atomic", false, "Delete either all items or none of them")
 	_ = cmd.MarkFlagRequired("id")
 	return cmd
 }
 
+// deleteOutput is the output schema of the delete command.
+type deleteOutput struct {
+	Deleted []uuid.UUID     `json:"deleted"`
+	Failed  []deleteFailure `json:"failed"`
+}
+
+// deleteFailure describes an item that could not be deleted.
+type deleteFailure struct {
+	ID    uuid.UUID `json:"id"`
+	Error string    `json:"error"`
+}
+
+func (o deleteOutput) printTable(w io.Writer) {
+	for _, f := range o.Failed {
+		fmt.Fprintf(w, "Failed to delete %s: %s\n", f.ID, f.Error)
+	}
+	for _, id := range o.Deleted {
+		fmt.Fprintf(w, "Item deleted: %s\n", id)
+	}
+}
+
+// deleteItems removes several items with a single batch request.
+// Returns the deleted and failed items and an error if any of the deletions failed.
+func (a *App) deleteItems(ids []uuid.UUID, atomic bool) (deleteOutput, error) {
+	req := &models.BatchRequest{Atomic: atomic, Operations: make([]models.BatchOperation, len(ids))}
+	for i := range ids {
+		req.Operations[i] = models.BatchOperation{Op: models.BatchOpDelete, ID: &ids[i]}
+	}
+
+	out := deleteOutput{Deleted: []uuid.UUID{}, Failed: []deleteFailure{}}
+	results, err := a.api.ExecuteBatch(req)
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(ids) {
+			continue
+		}
+		id := ids[res.Index]
+		if res.Error != "" {
+			out.Failed = append(out.Failed, deleteFailure{ID: id, Error: res.Error})
+			continue
+		}
+		out.Deleted = append(out.Deleted, id)
+		delete(a.cache.ItemsList(), id.String())
+	}
+	if err != nil {
+		return out, fmt.Errorf("failed to delete items: %w", err)
+	}
+	if len(out.Failed) > 0 {
+		return out, errors.New("failed to delete some items")
+	}
+	return out, nil
+}
+
+// parseExpiry parses an expiration date given as YYYY-MM-DD or RFC 3339.
+// A date without time expires at the start of that day in local time.
+// Returns nil for an empty string.
+func parseExpiry(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, raw, time.Local); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration date %q, expected YYYY-MM-DD or RFC 3339", raw)
+	}
+	return &t, nil
+}
+
 func parseID(raw string) (uuid.UUID, error) {
 	id, err := uuid.Parse(raw)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
 	"github.com/Pro100x3mal/gophkeeper/internal/client/config"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
@@ -67,11 +68,102 @@ func (m *MockApiService) ListItems() ([]*models.Item, error) {
 	return args.Get(0).([]*models.Item), args.Error(1)
 }
 
+func (m *MockApiService) ListDue(days, olderThan int) ([]models.DueItem, error) {
+	args := m.Called(days, olderThan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DueItem), args.Error(1)
+}
+
 func (m *MockApiService) DeleteItem(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockApiService) ExecuteBatch(req *models.BatchRequest) ([]models.BatchResult, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BatchResult), args.Error(1)
+}
+
+func (m *MockApiService) ListTrash() ([]*models.Item, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Item), args.Error(1)
+}
+
+func (m *MockApiService) RestoreItem(id uuid.UUID) (*models.Item, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockApiService) PurgeItem(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockApiService) EmptyTrash() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockApiService) GetUsage() (*models.UsageReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UsageReport), args.Error(1)
+}
+
+func (m *MockApiService) ChangePassword(oldPassword, newPassword string) (string, error) {
+	args := m.Called(oldPassword, newPassword)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockApiService) ChangeUsername(username string) (*models.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockApiService) DeleteAccount(password string) error {
+	args := m.Called(password)
+	return args.Error(0)
+}
+
+func (m *MockApiService) ListUsers() ([]*models.UserInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UserInfo), args.Error(1)
+}
+
+func (m *MockApiService) SetUserDisabled(id uuid.UUID, disabled bool) error {
+	args := m.Called(id, disabled)
+	return args.Error(0)
+}
+
+func (m *MockApiService) LogoutUser(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockApiService) DeleteUser(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 // MockCacheRepository is a mock implementation of CacheRepository interface
 type MockCacheRepository struct {
 	mock.Mock
@@ -171,9 +263,10 @@ func createTestAppWithMocks(mockAPI *MockApiService, mockCache *MockCacheReposit
 			BuildVersion: "test",
 			BuildDate:    "test",
 		},
-		logger: logger,
-		api:    mockAPI,
-		cache:  mockCache,
+		logger:    logger,
+		api:       mockAPI,
+		cache:     mockCache,
+		clipboard: &clipboard.Fake{},
 	}
 }
 
@@ -507,6 +600,37 @@ func TestCmdDelete(t *testing.T) {
 	mockAPI.AssertExpectations(t)
 }
 
+func TestCmdDelete_Multiple(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	firstID, secondID := uuid.New(), uuid.New()
+	cache := map[string]models.Item{
+		firstID.String():  {ID: firstID},
+		secondID.String(): {ID: secondID},
+	}
+
+	mockAPI.On("ExecuteBatch", mock.MatchedBy(func(req *models.BatchRequest) bool {
+		return req.Atomic && len(req.Operations) == 2 &&
+			req.Operations[0].Op == models.BatchOpDelete && *req.Operations[0].ID == firstID &&
+			*req.Operations[1].ID == secondID
+	})).Return([]models.BatchResult{
+		{Index: 0, Op: models.BatchOpDelete, Status: 204},
+		{Index: 1, Op: models.BatchOpDelete, Status: 404, Error: "item not found"},
+	}, nil)
+	mockCache.On("ItemsList").Return(cache)
+
+	cmd := app.cmdDelete()
+	cmd.SetArgs([]string{"--id", firstID.String() + "," + secondID.String(), "--atomic"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.NotContains(t, cache, firstID.String())
+	assert.Contains(t, cache, secondID.String())
+	mockAPI.AssertExpectations(t)
+}
+
 func TestCmdVersion(t *testing.T) {
 	app := createTestApp()
 	app.config.BuildVersion = "1.0.0"
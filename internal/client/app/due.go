@@ -0,0 +1,78 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+)
+
+// defaultDueDays is the default expiration window of the due command.
+const defaultDueDays = 30
+
+// dueOutput is the output schema of a due item.
+type dueOutput struct {
+	itemOutput
+	Reason models.DueReason `json:"reason"`
+}
+
+func (a *App) cmdDue() *cobra.Command {
+	var days, olderThan int
+	cmd := &cobra.Command{
+		Use:   "due",
+		Short: "List items that are expired, expire soon or need rotation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 0 || olderThan < 0 {
+				return errors.New("number of days cannot be negative")
+			}
+
+			items, err := a.api.ListDue(days, olderThan)
+			if err != nil {
+				return fmt.Errorf("failed to list due items: %w", err)
+			}
+
+			res := make([]dueOutput, 0, len(items))
+			for _, due := range items {
+				if due.Item != nil {
+					res = append(res, dueOutput{itemOutput: newItemOutput(*due.Item), Reason: due.Reason})
+				}
+			}
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				printDueItems(w, items, time.Now())
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&days, "days", defaultDueDays, "Report items expiring within this many days")
+	cmd.Flags().IntVar(&olderThan, "older-than", 0, "Also report items not updated for this many days")
+	return cmd
+}
+
+// printDueItems writes one line per due item with the reason and the relevant date.
+func printDueItems(w io.Writer, items []models.DueItem, now time.Time) {
+	if len(items) == 0 {
+		fmt.Fprintln(w, "Nothing is due")
+		return
+	}
+
+	for _, due := range items {
+		item := due.Item
+		if item == nil {
+			continue
+		}
+
+		var when string
+		switch {
+		case due.Reason == models.DueReasonExpired && item.ExpiresAt != nil:
+			when = "expired " + item.ExpiresAt.Local().Format(time.DateOnly)
+		case due.Reason == models.DueReasonExpiring && item.ExpiresAt != nil:
+			when = fmt.Sprintf("expires %s (in %d days)", item.ExpiresAt.Local().Format(time.DateOnly), int(item.ExpiresAt.Sub(now).Hours()/24))
+		default:
+			when = fmt.Sprintf("last updated %s (%d days ago)", item.UpdatedAt.Local().Format(time.DateOnly), int(now.Sub(item.UpdatedAt).Hours()/24))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.ID, item.Type, item.Title, due.Reason, when)
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdDue(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("ListDue", 7, 90).Return([]models.DueItem{}, nil)
+
+	cmd := app.cmdDue()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--days", "7", "--older-than", "90"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Nothing is due")
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdDue_NegativeDays(t *testing.T) {
+	app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+	cmd := app.cmdDue()
+	cmd.SetArgs([]string{"--days", "-1"})
+
+	assert.Error(t, cmd.Execute())
+}
+
+func TestPrintDueItems(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.Local)
+	expired := now.Add(-24 * time.Hour)
+	expiring := now.Add(10 * 24 * time.Hour)
+
+	items := []models.DueItem{
+		{Item: &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Old card", ExpiresAt: &expired}, Reason: models.DueReasonExpired},
+		{Item: &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "New card", ExpiresAt: &expiring}, Reason: models.DueReasonExpiring},
+		{Item: &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail", UpdatedAt: now.Add(-100 * 24 * time.Hour)}, Reason: models.DueReasonStale},
+	}
+
+	var out bytes.Buffer
+	printDueItems(&out, items, now)
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(lines[0]), "expired 2026-02-28")
+	assert.Contains(t, string(lines[1]), "in 10 days")
+	assert.Contains(t, string(lines[2]), "100 days ago")
+}
+
+func TestParseExpiry(t *testing.T) {
+	got, err := parseExpiry("")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = parseExpiry("2027-05-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2027, time.May, 1, 0, 0, 0, 0, time.Local), *got)
+
+	got, err = parseExpiry("2027-05-01T10:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2027, time.May, 1, 10, 0, 0, 0, time.UTC), got.UTC())
+
+	_, err = parseExpiry("05/27")
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/services"
+)
+
+// Exit codes of the client. Scripts can rely on them to tell failures apart.
+const (
+	// ExitOK means the command succeeded.
+	ExitOK = 0
+	// ExitError means the command failed for a reason without a dedicated code.
+	ExitError = 1
+	// ExitUsage means the command line was invalid.
+	ExitUsage = 2
+	// ExitNotFound means the item, the user or another resource does not exist.
+	ExitNotFound = 3
+	// ExitAuth means the user is not logged in, the session expired or access was denied.
+	ExitAuth = 4
+	// ExitNetwork means the server could not be reached or is unavailable.
+	ExitNetwork = 5
+)
+
+// errUsage marks invalid flags and arguments.
+var errUsage = errors.New("invalid usage")
+
+// ExitCode returns the process exit code for the error returned by Run.
+// The run command passes the exit code of its child process on unchanged.
+func ExitCode(err error) int {
+	var childErr *ChildExitError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &childErr):
+		return childErr.Code
+	case errors.Is(err, errUsage), errors.Is(err, errUnknownOutput):
+		return ExitUsage
+	case errors.Is(err, services.ErrNotFound), errors.Is(err, errItemNotFound), errors.Is(err, errUserNotFound):
+		return ExitNotFound
+	case errors.Is(err, services.ErrUnauthorized):
+		return ExitAuth
+	case errors.Is(err, services.ErrUnavailable):
+		return ExitNetwork
+	default:
+		return ExitError
+	}
+}
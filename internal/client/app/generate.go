@@ -0,0 +1,126 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/passgen"
+	"github.com/spf13/cobra"
+)
+
+// generatorFlags holds the command-line options of the password generator.
+type generatorFlags struct {
+	length           int
+	noLower          bool
+	noUpper          bool
+	noDigits         bool
+	noSymbols        bool
+	exclude          string
+	excludeAmbiguous bool
+	passphrase       bool
+	words            int
+	separator        string
+	capitalize       bool
+	number           bool
+}
+
+// register adds the generator options to the flags of a command.
+func (g *generatorFlags) register(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	defaults := passgen.DefaultOptions()
+	phrase := passgen.DefaultPassphraseOptions()
+
+	fs.IntVar(&g.length, "length", defaults.Length, "Password length")
+	fs.BoolVar(&g.noLower, "no-lower", false, "Exclude lowercase letters")
+	fs.BoolVar(&g.noUpper, "no-upper", false, "Exclude uppercase letters")
+	fs.BoolVar(&g.noDigits, "no-digits", false, "Exclude digits")
+	fs.BoolVar(&g.noSymbols, "no-symbols", false, "Exclude symbols")
+	fs.StringVar(&g.exclude, "exclude", "", "Characters to exclude")
+	fs.BoolVar(&g.excludeAmbiguous, "exclude-ambiguous", false, "Exclude ambiguous characters like 0, O, 1 and l")
+	fs.BoolVar(&g.passphrase, "passphrase", false, "Generate a passphrase of random words instead of a password")
+	fs.IntVar(&g.words, "words", phrase.Words, "Number of words in the passphrase")
+	fs.StringVar(&g.separator, "separator", phrase.Separator, "Separator between passphrase words")
+	fs.BoolVar(&g.capitalize, "capitalize", false, "Capitalize passphrase words")
+	fs.BoolVar(&g.number, "number", false, "Append a digit to one of the passphrase words")
+}
+
+// generate creates a secret according to the flags.
+// Returns the secret and its estimated entropy in bits.
+func (g *generatorFlags) generate() (string, float64, error) {
+	if g.passphrase {
+		opts := passgen.PassphraseOptions{
+			Words:      g.words,
+			Separator:  g.separator,
+			Capitalize: g.capitalize,
+			Number:     g.number,
+		}
+		secret, err := passgen.Passphrase(opts)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to generate passphrase: %w", err)
+		}
+		return secret, passgen.PassphraseEntropy(opts), nil
+	}
+
+	opts := passgen.Options{
+		Length:           g.length,
+		Lower:            !g.noLower,
+		Upper:            !g.noUpper,
+		Digits:           !g.noDigits,
+		Symbols:          !g.noSymbols,
+		ExcludeAmbiguous: g.excludeAmbiguous,
+		Exclude:          g.exclude,
+	}
+	secret, err := passgen.Password(opts)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return secret, passgen.PasswordEntropy(opts), nil
+}
+
+// generateOutput is the output schema of the generate command.
+type generateOutput struct {
+	Secret      string  `json:"secret"`
+	EntropyBits float64 `json:"entropy_bits"`
+}
+
+func (a *App) cmdGenerate() *cobra.Command {
+	var gen generatorFlags
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a random password or passphrase",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			secret, entropy, err := gen.generate()
+			if err != nil {
+				return err
+			}
+			res := generateOutput{Secret: secret, EntropyBits: math.Round(entropy)}
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				fmt.Fprintln(w, secret)
+				fmt.Fprintf(cmd.ErrOrStderr(), "Entropy: %.0f bits\n", entropy)
+			})
+		},
+	}
+
+	gen.register(cmd)
+	return cmd
+}
+
+// generateCredential returns a JSON credential payload with a generated password.
+func generateCredential(gen *generatorFlags, username, url string) (string, error) {
+	secret, _, err := gen.generate()
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(models.CredentialPayload{
+		Username: username,
+		Password: secret,
+		URL:      url,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential: %w", err)
+	}
+	return string(payload), nil
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdGenerate_Password(t *testing.T) {
+	app := createTestApp()
+
+	cmd := app.cmdGenerate()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"--length", "32", "--no-symbols"})
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+
+	password := strings.TrimSpace(out.String())
+	assert.Len(t, password, 32)
+	assert.False(t, strings.ContainsAny(password, "!@#$%^&*"))
+	assert.Contains(t, errOut.String(), "Entropy:")
+}
+
+func TestCmdGenerate_Passphrase(t *testing.T) {
+	app := createTestApp()
+
+	cmd := app.cmdGenerate()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--passphrase", "--words", "5", "--separator", " "})
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(out.String()), 5)
+}
+
+func TestCmdGenerate_InvalidLength(t *testing.T) {
+	app := createTestApp()
+
+	cmd := app.cmdGenerate()
+	cmd.SetArgs([]string{"--length", "2"})
+
+	assert.Error(t, cmd.Execute())
+}
+
+func TestCmdCreate_Generate(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	var payload models.CredentialPayload
+	mockAPI.On("CreateItem", mock.MatchedBy(func(req *models.CreateItemRequest) bool {
+		raw, err := base64.StdEncoding.DecodeString(req.DataBase64)
+		if err != nil {
+			return false
+		}
+		return json.Unmarshal(raw, &payload) == nil && req.Type == models.ItemTypeCredential
+	})).Return(&models.Item{ID: uuid.New()}, nil)
+	mockCache.On("ItemsList").Return(make(map[string]models.Item))
+
+	cmd := app.cmdCreate()
+	cmd.SetArgs([]string{"--type", "credential", "--title", "Mail", "--generate",
+		"--username", "alice", "--url", "https://mail.example.com", "--length", "24"})
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "alice", payload.Username)
+	assert.Equal(t, "https://mail.example.com", payload.URL)
+	assert.Len(t, payload.Password, 24)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdCreate_GenerateRequiresCredential(t *testing.T) {
+	app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+	cmd := app.cmdCreate()
+	cmd.SetArgs([]string{"--type", "text", "--title", "Note", "--generate"})
+	assert.Error(t, cmd.Execute())
+
+	cmd = app.cmdCreate()
+	cmd.SetArgs([]string{"--type", "credential", "--title", "Mail", "--generate", "--data", "x"})
+	assert.Error(t, cmd.Execute())
+}
@@ -0,0 +1,287 @@
+package app
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// gitCredentialHelper is the executable name git looks up for credential.helper=gophkeeper.
+// Invoked under this name, the client runs the git-credential command.
+const gitCredentialHelper = "git-credential-gophkeeper"
+
+// gitCredentialSource marks items created by the git-credential command.
+const gitCredentialSource = "git-credential"
+
+// gitCredential is a credential description of the git credential helper protocol.
+type gitCredential struct {
+	protocol string
+	host     string
+	path     string
+	username string
+	password string
+}
+
+// readGitCredential reads key=value lines until an empty line or the end of input.
+// Unknown keys are ignored, as the protocol requires.
+func readGitCredential(r io.Reader) (gitCredential, error) {
+	var c gitCredential
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "protocol":
+			c.protocol = value
+		case "host":
+			c.host = value
+		case "path":
+			c.path = value
+		case "username":
+			c.username = value
+		case "password":
+			c.password = value
+		case "url":
+			u, err := url.Parse(value)
+			if err != nil {
+				return c, fmt.Errorf("invalid url: %w", err)
+			}
+			c.protocol, c.host, c.path = u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				c.username = u.User.Username()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return c, fmt.Errorf("failed to read credential: %w", err)
+	}
+	return c, nil
+}
+
+// url returns the URL the credential is for.
+func (c gitCredential) url() string {
+	u := url.URL{Scheme: c.protocol, Host: c.host, Path: c.path}
+	if c.path != "" {
+		u.Path = "/" + c.path
+	}
+	return u.String()
+}
+
+// trimRepoPath normalizes a repository path for comparison.
+func trimRepoPath(p string) string {
+	return strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+}
+
+// matchGitURL reports whether an item URL covers the credential and how specific the match is.
+// An item URL without scheme is an https URL, the scheme must equal the protocol sent by Git,
+// so an https token is never sent over plain http. An item URL with a path only matches
+// requests for that path or below it, so credentials can be scoped to an organization or repository.
+func matchGitURL(raw string, c gitCredential) (int, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || c.host == "" {
+		return 0, false
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return 0, false
+	}
+	if u.Scheme != c.protocol {
+		return 0, false
+	}
+	if !strings.EqualFold(u.Host, c.host) {
+		return 0, false
+	}
+
+	itemPath, reqPath := trimRepoPath(u.Path), trimRepoPath(c.path)
+	switch {
+	case itemPath == "":
+		return 0, true
+	case reqPath == itemPath, strings.HasPrefix(reqPath, itemPath+"/"):
+		return len(itemPath), true
+	default:
+		return 0, false
+	}
+}
+
+// findGitCredential returns the most specific credential item for the request.
+// If the request names a user, the item must have that username.
+func (a *App) findGitCredential(c gitCredential) (*models.Item, *models.CredentialPayload, error) {
+	items, err := a.api.ListItems()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	type candidate struct {
+		item  *models.Item
+		score int
+	}
+	var candidates []candidate
+	for _, item := range items {
+		if item == nil || item.Type != models.ItemTypeCredential {
+			continue
+		}
+		if score, ok := matchGitURL(models.ParseItemMetadata(item.Metadata).URL, c); ok {
+			candidates = append(candidates, candidate{item: item, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].item.UpdatedAt.After(candidates[j].item.UpdatedAt)
+	})
+
+	for _, cand := range candidates {
+		item, data, err := a.api.GetItem(cand.item.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get item %s: %w", cand.item.ID, err)
+		}
+		if item == nil || data == nil {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(*data)
+		if err != nil {
+			continue
+		}
+		var payload models.CredentialPayload
+		if json.Unmarshal(raw, &payload) != nil {
+			continue
+		}
+		if c.username != "" && payload.Username != c.username {
+			continue
+		}
+		return item, &payload, nil
+	}
+	return nil, nil, nil
+}
+
+func (a *App) cmdGitCredential() *cobra.Command {
+	return &cobra.Command{
+		Use:   "git-credential get|store|erase",
+		Short: "Git credential helper backed by credential items",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := readGitCredential(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			switch args[0] {
+			case "get":
+				return a.gitCredentialGet(cmd.OutOrStdout(), c)
+			case "store":
+				return a.gitCredentialStore(c)
+			case "erase":
+				return a.gitCredentialErase(c)
+			default:
+				// Helpers must ignore operations they do not know.
+				return nil
+			}
+		},
+	}
+}
+
+// gitCredentialGet writes the username and password of the matching item.
+// Nothing is written if no item matches, so git falls back to other helpers or a prompt.
+func (a *App) gitCredentialGet(w io.Writer, c gitCredential) error {
+	_, payload, err := a.findGitCredential(c)
+	if err != nil || payload == nil {
+		return err
+	}
+	// The protocol is line based, values with line breaks cannot be passed.
+	if strings.ContainsAny(payload.Username+payload.Password, "\n\x00") {
+		return fmt.Errorf("credential for %s contains a line break", c.host)
+	}
+	if payload.Username != "" {
+		fmt.Fprintf(w, "username=%s\n", payload.Username)
+	}
+	fmt.Fprintf(w, "password=%s\n", payload.Password)
+	return nil
+}
+
+// gitCredentialStore saves a credential git has used successfully.
+// A matching item is updated if the password changed, otherwise a new item is created.
+func (a *App) gitCredentialStore(c gitCredential) error {
+	if c.host == "" || c.username == "" || c.password == "" {
+		return nil
+	}
+
+	item, payload, err := a.findGitCredential(c)
+	if err != nil {
+		return err
+	}
+
+	if item != nil {
+		if payload.Password == c.password {
+			return nil
+		}
+		payload.Password = c.password
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode credential: %w", err)
+		}
+		dataBase64 := base64.StdEncoding.EncodeToString(data)
+		updated, err := a.api.UpdateItem(item.ID, &models.UpdateItemRequest{DataBase64: &dataBase64})
+		if err != nil {
+			return fmt.Errorf("failed to update item: %w", err)
+		}
+		a.cache.ItemsList()[updated.ID.String()] = *updated
+		return nil
+	}
+
+	data, err := json.Marshal(models.CredentialPayload{Username: c.username, Password: c.password, URL: c.url()})
+	if err != nil {
+		return fmt.Errorf("failed to encode credential: %w", err)
+	}
+	title := c.host
+	if c.path != "" {
+		title += "/" + trimRepoPath(c.path)
+	}
+	created, err := a.api.CreateItem(&models.CreateItemRequest{
+		Type:       models.ItemTypeCredential,
+		Title:      title,
+		Metadata:   models.ItemMetadata{URL: c.url(), Source: gitCredentialSource}.String(),
+		DataBase64: base64.StdEncoding.EncodeToString(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create item: %w", err)
+	}
+	a.cache.ItemsList()[created.ID.String()] = *created
+	return nil
+}
+
+// gitCredentialErase moves the matching item to the trash after git reported the credential as rejected.
+// The item is kept if its password differs from the rejected one, i.e. it was already rotated.
+func (a *App) gitCredentialErase(c gitCredential) error {
+	if c.host == "" {
+		return nil
+	}
+
+	item, payload, err := a.findGitCredential(c)
+	if err != nil || item == nil {
+		return err
+	}
+	if c.password != "" && payload.Password != c.password {
+		return nil
+	}
+	_, err = a.deleteItems([]uuid.UUID{item.ID}, false)
+	return err
+}
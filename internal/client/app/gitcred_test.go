@@ -0,0 +1,185 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGitCredential(t *testing.T) {
+	c, err := readGitCredential(strings.NewReader("protocol=https\nhost=github.com\npath=org/repo.git\nusername=alice\ncapability[]=authtype\n\nignored=1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, gitCredential{protocol: "https", host: "github.com", path: "org/repo.git", username: "alice"}, c)
+	assert.Equal(t, "https://github.com/org/repo.git", c.url())
+
+	c, err = readGitCredential(strings.NewReader("url=https://bob@gitlab.example.com:8443/team/app\n"))
+	require.NoError(t, err)
+	assert.Equal(t, gitCredential{protocol: "https", host: "gitlab.example.com:8443", path: "team/app", username: "bob"}, c)
+}
+
+func TestMatchGitURL(t *testing.T) {
+	req := gitCredential{protocol: "https", host: "github.com", path: "org/repo.git"}
+	tests := []struct {
+		url   string
+		score int
+		ok    bool
+	}{
+		{"https://github.com", 0, true},
+		{"github.com", 0, true},
+		{"HTTPS://GitHub.com/", 0, true},
+		{"https://github.com/org", 3, true},
+		{"https://github.com/org/repo", 8, true},
+		{"https://github.com/org/repo.git", 8, true},
+		{"https://github.com/other", 0, false},
+		{"https://github.com/org/repository", 0, false},
+		{"http://github.com", 0, false},
+		{"github.com/org", 3, true},
+		{"https://gitlab.com", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			score, ok := matchGitURL(tt.url, req)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.score, score)
+		})
+	}
+
+	_, ok := matchGitURL("https://github.com/org", gitCredential{protocol: "https", host: "github.com"})
+	assert.False(t, ok, "path-scoped items need a path in the request")
+
+	_, ok = matchGitURL("github.com", gitCredential{protocol: "http", host: "github.com"})
+	assert.False(t, ok, "items without scheme are https only")
+}
+
+// gitItem returns a credential item with the URL in its metadata and registers its data with the mock.
+func gitItem(mockAPI *MockApiService, title, url, username, password string, updated time.Time) *models.Item {
+	item := &models.Item{
+		ID:        uuid.New(),
+		Type:      models.ItemTypeCredential,
+		Title:     title,
+		Metadata:  models.ItemMetadata{URL: url}.String(),
+		UpdatedAt: updated,
+	}
+	data, _ := json.Marshal(models.CredentialPayload{Username: username, Password: password})
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(string(data)), nil).Maybe()
+	return item
+}
+
+func runGitCredential(t *testing.T, app *App, op, input string) string {
+	t.Helper()
+	cmd := app.cmdGitCredential()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetArgs([]string{op})
+	require.NoError(t, cmd.Execute())
+	return out.String()
+}
+
+func TestCmdGitCredential_Get(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	now := time.Now()
+	host := gitItem(mockAPI, "GitHub", "https://github.com", "alice", "host-token", now)
+	org := gitItem(mockAPI, "GitHub org", "https://github.com/org", "alice", "org-token", now.Add(-time.Hour))
+	bob := gitItem(mockAPI, "GitHub bob", "github.com", "bob", "bob-token", now.Add(-2*time.Hour))
+	other := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	mockAPI.On("ListItems").Return([]*models.Item{host, org, bob, other}, nil)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"host", "protocol=https\nhost=github.com\n\n", "username=alice\npassword=host-token\n"},
+		{"path scoped", "protocol=https\nhost=github.com\npath=org/repo.git\n\n", "username=alice\npassword=org-token\n"},
+		{"username", "protocol=https\nhost=github.com\nusername=bob\n\n", "username=bob\npassword=bob-token\n"},
+		{"no match", "protocol=https\nhost=gitlab.com\n\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, runGitCredential(t, app, "get", tt.input))
+		})
+	}
+}
+
+func TestCmdGitCredential_Store(t *testing.T) {
+	t.Run("creates item", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		mockCache := new(MockCacheRepository)
+		app := createTestAppWithMocks(mockAPI, mockCache)
+		mockCache.On("ItemsList").Return(map[string]models.Item{})
+		mockAPI.On("ListItems").Return([]*models.Item{}, nil)
+		mockAPI.On("CreateItem", mock.MatchedBy(func(req *models.CreateItemRequest) bool {
+			raw, _ := base64.StdEncoding.DecodeString(req.DataBase64)
+			var payload models.CredentialPayload
+			meta := models.ParseItemMetadata(req.Metadata)
+			return json.Unmarshal(raw, &payload) == nil && payload.Username == "alice" && payload.Password == "token" &&
+				req.Type == models.ItemTypeCredential && req.Title == "github.com" &&
+				meta.URL == "https://github.com" && meta.Source == gitCredentialSource
+		})).Return(&models.Item{ID: uuid.New()}, nil)
+
+		runGitCredential(t, app, "store", "protocol=https\nhost=github.com\nusername=alice\npassword=token\n")
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("updates changed password", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		mockCache := new(MockCacheRepository)
+		app := createTestAppWithMocks(mockAPI, mockCache)
+		mockCache.On("ItemsList").Return(map[string]models.Item{})
+		item := gitItem(mockAPI, "GitHub", "https://github.com", "alice", "old", time.Now())
+		mockAPI.On("ListItems").Return([]*models.Item{item}, nil)
+		mockAPI.On("UpdateItem", item.ID, mock.MatchedBy(func(req *models.UpdateItemRequest) bool {
+			raw, _ := base64.StdEncoding.DecodeString(*req.DataBase64)
+			return strings.Contains(string(raw), `"password":"new"`) && strings.Contains(string(raw), `"username":"alice"`)
+		})).Return(item, nil)
+
+		runGitCredential(t, app, "store", "protocol=https\nhost=github.com\nusername=alice\npassword=new\n")
+		mockAPI.AssertExpectations(t)
+	})
+
+	t.Run("keeps unchanged password", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+		item := gitItem(mockAPI, "GitHub", "https://github.com", "alice", "same", time.Now())
+		mockAPI.On("ListItems").Return([]*models.Item{item}, nil)
+
+		runGitCredential(t, app, "store", "protocol=https\nhost=github.com\nusername=alice\npassword=same\n")
+		mockAPI.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+		mockAPI.AssertNotCalled(t, "CreateItem", mock.Anything)
+	})
+}
+
+func TestCmdGitCredential_Erase(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+	item := gitItem(mockAPI, "GitHub", "https://github.com", "alice", "token", time.Now())
+	mockAPI.On("ListItems").Return([]*models.Item{item}, nil)
+
+	// A rotated password is not erased.
+	runGitCredential(t, app, "erase", "protocol=https\nhost=github.com\nusername=alice\npassword=stale\n")
+	mockAPI.AssertNotCalled(t, "ExecuteBatch", mock.Anything)
+
+	mockAPI.On("ExecuteBatch", mock.MatchedBy(func(req *models.BatchRequest) bool {
+		return len(req.Operations) == 1 && *req.Operations[0].ID == item.ID
+	})).Return([]models.BatchResult{{Index: 0}}, nil)
+	runGitCredential(t, app, "erase", "protocol=https\nhost=github.com\nusername=alice\npassword=token\n")
+	mockAPI.AssertExpectations(t)
+
+	assert.Empty(t, runGitCredential(t, app, "unknown", ""))
+}
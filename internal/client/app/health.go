@@ -0,0 +1,280 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/breach"
+	"github.com/Pro100x3mal/gophkeeper/pkg/strength"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// credentialPassword extracts the password from a JSON credential payload.
+// Returns false if the data is not a credential with a password.
+func credentialPassword(data []byte) (models.CredentialPayload, bool) {
+	var payload models.CredentialPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Password == "" {
+		return payload, false
+	}
+	return payload, true
+}
+
+// openBreachCorpus opens the breach corpus at path.
+// Returns nil without an error when no path is configured.
+func openBreachCorpus(path string) (*breach.FileCorpus, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return breach.OpenFile(path)
+}
+
+// warnPassword prints warnings about a weak or breached credential password.
+// The checks never block saving the item.
+func (a *App) warnPassword(w io.Writer, data []byte, title string) {
+	payload, ok := credentialPassword(data)
+	if !ok {
+		return
+	}
+
+	res := strength.Estimate(payload.Password, payload.Username, title)
+	if res.Weak() {
+		fmt.Fprintf(w, "Warning: weak password (score %d/4)", res.Score)
+		if res.Warning != "" {
+			fmt.Fprintf(w, ": %s", res.Warning)
+		}
+		fmt.Fprintln(w)
+		for _, s := range res.Suggestions {
+			fmt.Fprintf(w, "  - %s\n", s)
+		}
+	}
+
+	corpus, err := openBreachCorpus(a.config.BreachCorpusPath)
+	if err != nil {
+		a.logger.Warn("Breach check skipped", zap.Error(err))
+		return
+	}
+	if corpus == nil {
+		return
+	}
+	defer corpus.Close()
+
+	count, err := breach.Check(corpus, payload.Password)
+	if err != nil {
+		a.logger.Warn("Breach check failed", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		fmt.Fprintf(w, "Warning: password appears %d times in known data breaches\n", count)
+	}
+}
+
+// healthEntry is a credential analyzed by the health report.
+type healthEntry struct {
+	item     *models.Item
+	result   strength.Result
+	breached int
+}
+
+// healthReport groups credentials with password problems.
+type healthReport struct {
+	checked  int
+	weak     []healthEntry
+	reused   [][]healthEntry
+	breached []healthEntry
+}
+
+// healthOutput is the output schema of the health-report command.
+// Breached is empty when the breach check was skipped.
+type healthOutput struct {
+	Checked       int                   `json:"checked"`
+	Weak          []healthEntryOutput   `json:"weak"`
+	Reused        [][]healthEntryOutput `json:"reused"`
+	Breached      []healthEntryOutput   `json:"breached"`
+	BreachChecked bool                  `json:"breach_checked"`
+}
+
+// healthEntryOutput describes a credential in the health report.
+type healthEntryOutput struct {
+	ID            uuid.UUID `json:"id"`
+	Title         string    `json:"title"`
+	Score         int       `json:"score"`
+	Warning       string    `json:"warning,omitempty"`
+	BreachedCount int       `json:"breached_count,omitempty"`
+}
+
+func (e healthEntry) output() healthEntryOutput {
+	return healthEntryOutput{
+		ID:            e.item.ID,
+		Title:         e.item.Title,
+		Score:         int(e.result.Score),
+		Warning:       e.result.Warning,
+		BreachedCount: e.breached,
+	}
+}
+
+func (r *healthReport) output(breachChecked bool) healthOutput {
+	entries := func(list []healthEntry) []healthEntryOutput {
+		out := make([]healthEntryOutput, 0, len(list))
+		for _, e := range list {
+			out = append(out, e.output())
+		}
+		return out
+	}
+
+	out := healthOutput{
+		Checked:       r.checked,
+		Weak:          entries(r.weak),
+		Reused:        make([][]healthEntryOutput, 0, len(r.reused)),
+		Breached:      entries(r.breached),
+		BreachChecked: breachChecked,
+	}
+	for _, group := range r.reused {
+		out.Reused = append(out.Reused, entries(group))
+	}
+	return out
+}
+
+func (a *App) cmdHealthReport() *cobra.Command {
+	var corpusPath string
+	cmd := &cobra.Command{
+		Use:   "health-report",
+		Short: "Report weak, reused and breached passwords",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("corpus") {
+				corpusPath = a.config.BreachCorpusPath
+			}
+			corpus, err := openBreachCorpus(corpusPath)
+			if err != nil {
+				return err
+			}
+			var c breach.Corpus
+			if corpus != nil {
+				defer corpus.Close()
+				c = corpus
+			}
+
+			report, err := a.buildHealthReport(c)
+			if err != nil {
+				return err
+			}
+			return a.render(cmd.OutOrStdout(), report.output(c != nil), func(w io.Writer) {
+				printHealthReport(w, report, c != nil)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&corpusPath, "corpus", "", "Path to the breached password hashes file (overrides BREACH_CORPUS)")
+	return cmd
+}
+
+// buildHealthReport downloads every credential and analyzes its password.
+// Breach checks are skipped when corpus is nil.
+func (a *App) buildHealthReport(corpus breach.Corpus) (*healthReport, error) {
+	items, err := a.api.ListItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	report := &healthReport{}
+	byPassword := make(map[string][]healthEntry)
+	for _, item := range items {
+		if item == nil || item.Type != models.ItemTypeCredential {
+			continue
+		}
+
+		_, data, err := a.api.GetItem(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get item %s: %w", item.ID, err)
+		}
+		if data == nil {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(*data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode item %s: %w", item.ID, err)
+		}
+		payload, ok := credentialPassword(raw)
+		if !ok {
+			continue
+		}
+
+		entry := healthEntry{
+			item:   item,
+			result: strength.Estimate(payload.Password, payload.Username, item.Title),
+		}
+		if corpus != nil {
+			if entry.breached, err = breach.Check(corpus, payload.Password); err != nil {
+				return nil, fmt.Errorf("failed to check item %s: %w", item.ID, err)
+			}
+		}
+
+		report.checked++
+		if entry.result.Weak() {
+			report.weak = append(report.weak, entry)
+		}
+		if entry.breached > 0 {
+			report.breached = append(report.breached, entry)
+		}
+		byPassword[payload.Password] = append(byPassword[payload.Password], entry)
+	}
+
+	for _, group := range byPassword {
+		if len(group) > 1 {
+			report.reused = append(report.reused, group)
+		}
+	}
+	sort.Slice(report.reused, func(i, j int) bool {
+		return report.reused[i][0].item.Title < report.reused[j][0].item.Title
+	})
+	return report, nil
+}
+
+// printHealthReport writes the report sections followed by a summary.
+func printHealthReport(w io.Writer, report *healthReport, breachChecked bool) {
+	if len(report.weak) > 0 {
+		fmt.Fprintln(w, "Weak passwords:")
+		for _, e := range report.weak {
+			fmt.Fprintf(w, "  %s\t%s\tscore %d/4", e.item.ID, e.item.Title, e.result.Score)
+			if e.result.Warning != "" {
+				fmt.Fprintf(w, "\t%s", e.result.Warning)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if len(report.reused) > 0 {
+		fmt.Fprintln(w, "Reused passwords:")
+		for _, group := range report.reused {
+			titles := make([]string, len(group))
+			for i, e := range group {
+				titles[i] = fmt.Sprintf("%s (%s)", e.item.Title, e.item.ID)
+			}
+			fmt.Fprintf(w, "  %s\n", strings.Join(titles, ", "))
+		}
+	}
+
+	if len(report.breached) > 0 {
+		fmt.Fprintln(w, "Breached passwords:")
+		for _, e := range report.breached {
+			fmt.Fprintf(w, "  %s\t%s\tseen %d times\n", e.item.ID, e.item.Title, e.breached)
+		}
+	}
+
+	reused := 0
+	for _, group := range report.reused {
+		reused += len(group)
+	}
+	breachedSummary := "breach check skipped"
+	if breachChecked {
+		breachedSummary = fmt.Sprintf("%d breached", len(report.breached))
+	}
+	fmt.Fprintf(w, "Checked %d credentials: %d weak, %d reused, %s\n",
+		report.checked, len(report.weak), reused, breachedSummary)
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/breach"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// writeBreachCorpus writes a corpus file containing the given passwords.
+func writeBreachCorpus(t *testing.T, passwords ...string) string {
+	t.Helper()
+
+	var content string
+	for _, p := range passwords {
+		prefix, suffix := breach.HashPrefix(p)
+		content += prefix + suffix + ":42\n"
+	}
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func credentialData(username, password string) *string {
+	data := base64.StdEncoding.EncodeToString([]byte(`{"username":"` + username + `","password":"` + password + `"}`))
+	return &data
+}
+
+func TestWarnPassword(t *testing.T) {
+	t.Run("weak and breached", func(t *testing.T) {
+		app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+		app.config.BreachCorpusPath = writeBreachCorpus(t, "password")
+
+		var out bytes.Buffer
+		app.warnPassword(&out, []byte(`{"username":"alice","password":"password"}`), "Mail")
+
+		assert.Contains(t, out.String(), "Warning: weak password (score 0/4): This is a very common password")
+		assert.Contains(t, out.String(), "appears 42 times in known data breaches")
+	})
+
+	t.Run("strong password", func(t *testing.T) {
+		app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+		app.config.BreachCorpusPath = writeBreachCorpus(t, "password")
+
+		var out bytes.Buffer
+		app.warnPassword(&out, []byte(`{"username":"alice","password":"vK7#pq2!Lm9@xR4z"}`), "Mail")
+
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("not a credential", func(t *testing.T) {
+		app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+		var out bytes.Buffer
+		app.warnPassword(&out, []byte("plain text"), "Notes")
+
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestCmdCreate_WeakPasswordWarning(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	mockAPI.On("CreateItem", mock.Anything).Return(item, nil)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+
+	cmd := app.cmdCreate()
+	errOut := &bytes.Buffer{}
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"--type", "credential", "--title", "Mail", "--data", `{"username":"alice","password":"qwerty"}`})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, errOut.String(), "Warning: weak password")
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdHealthReport(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	weak := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Forum"}
+	reusedA := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Bank"}
+	reusedB := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Shop"}
+	note := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+
+	mockAPI.On("ListItems").Return([]*models.Item{weak, reusedA, reusedB, note}, nil)
+	mockAPI.On("GetItem", weak.ID).Return(weak, credentialData("bob", "letmein"), nil)
+	mockAPI.On("GetItem", reusedA.ID).Return(reusedA, credentialData("bob", "vK7#pq2!Lm9@xR4z"), nil)
+	mockAPI.On("GetItem", reusedB.ID).Return(reusedB, credentialData("bob", "vK7#pq2!Lm9@xR4z"), nil)
+
+	cmd := app.cmdHealthReport()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--corpus", writeBreachCorpus(t, "letmein")})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Weak passwords:")
+	assert.Contains(t, out.String(), "Forum")
+	assert.Contains(t, out.String(), "Reused passwords:\n  Bank ("+reusedA.ID.String()+"), Shop (")
+	assert.Contains(t, out.String(), "Breached passwords:")
+	assert.Contains(t, out.String(), "seen 42 times")
+	assert.Contains(t, out.String(), "Checked 3 credentials: 1 weak, 2 reused, 1 breached")
+	mockAPI.AssertExpectations(t)
+	mockAPI.AssertNotCalled(t, "GetItem", note.ID)
+}
+
+func TestCmdHealthReport_NoCorpus(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("ListItems").Return([]*models.Item{}, nil)
+
+	cmd := app.cmdHealthReport()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Checked 0 credentials: 0 weak, 0 reused, breach check skipped\n", out.String())
+}
+
+func TestCmdHealthReport_MissingCorpus(t *testing.T) {
+	app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+	cmd := app.cmdHealthReport()
+	cmd.SetArgs([]string{"--corpus", filepath.Join(t.TempDir(), "missing.txt")})
+
+	assert.Error(t, cmd.Execute())
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/importers"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// defaultImportBatchSize is the number of items uploaded per batch during import.
+const defaultImportBatchSize = 50
+
+// importOutput is the output schema of the import command.
+type importOutput struct {
+	Items    []importItemOutput `json:"items"`
+	Skipped  []importSkipOutput `json:"skipped"`
+	Total    int                `json:"total"`
+	Imported int                `json:"imported"`
+	Failed   int                `json:"failed"`
+	DryRun   bool               `json:"dry_run"`
+}
+
+// importItemOutput describes an item found in the export.
+type importItemOutput struct {
+	Type   models.ItemType `json:"type"`
+	Folder string          `json:"folder,omitempty"`
+	Title  string          `json:"title"`
+}
+
+// importSkipOutput describes an export entry that was not imported.
+type importSkipOutput struct {
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+func newImportOutput(res *importers.Result, dryRun bool) importOutput {
+	out := importOutput{
+		Items:   make([]importItemOutput, 0, len(res.Items)),
+		Skipped: make([]importSkipOutput, 0, len(res.Skipped)),
+		Total:   len(res.Items),
+		DryRun:  dryRun,
+	}
+	for _, item := range res.Items {
+		folder := models.ParseItemMetadata(item.Metadata).Folder
+		out.Items = append(out.Items, importItemOutput{Type: item.Type, Folder: folder, Title: item.Title})
+	}
+	for _, s := range res.Skipped {
+		out.Skipped = append(out.Skipped, importSkipOutput{Title: s.Title, Reason: s.Reason})
+	}
+	return out
+}
+
+func (a *App) cmdImport() *cobra.Command {
+	var format, filePath string
+	var dryRun bool
+	var batchSize int
+
+	formats := make([]string, 0, len(importers.Formats))
+	for _, f := range importers.Formats {
+		formats = append(formats, string(f))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import items from another password manager",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSize <= 0 {
+				return errors.New("batch size must be positive")
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to open export file: %w", err)
+			}
+			defer f.Close()
+
+			res, err := importers.Parse(importers.Format(format), f)
+			if err != nil {
+				return fmt.Errorf("failed to parse export: %w", err)
+			}
+
+			out := newImportOutput(res, dryRun)
+			if !dryRun {
+				out.Imported, out.Failed = a.uploadItems(res.Items, batchSize)
+			}
+			err = a.render(cmd.OutOrStdout(), out, func(w io.Writer) {
+				printImportReport(w, res)
+				if !dryRun {
+					fmt.Fprintf(w, "Imported %d of %d items\n", out.Imported, len(res.Items))
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if out.Failed > 0 {
+				return fmt.Errorf("failed to import %d items", out.Failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Export format ("+strings.Join(formats, "|")+")")
+	cmd.Flags().StringVar(&filePath, "file", "", "Path to the export file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only report what would be imported")
+	cmd.Flags().IntVar(&batchSize, "batch-size", defaultImportBatchSize, "Number of items uploaded per batch")
+	_ = cmd.MarkFlagRequired("format")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// uploadItems creates the items on the server with one batch request per batchSize items.
+// Batches are not atomic: failed items are logged and counted without aborting the rest.
+// Returns the number of created and failed items.
+func (a *App) uploadItems(items []*models.CreateItemRequest, batchSize int) (int, int) {
+	var created, failed int
+	for start := 0; start < len(items); start += batchSize {
+		end := min(start+batchSize, len(items))
+		chunk := items[start:end]
+
+		req := &models.BatchRequest{Operations: make([]models.BatchOperation, len(chunk))}
+		for i, item := range chunk {
+			req.Operations[i] = models.BatchOperation{Op: models.BatchOpCreate, Create: item}
+		}
+
+		results, err := a.api.ExecuteBatch(req)
+		if err != nil {
+			a.logger.Warn("Failed to upload import batch", zap.Int("size", len(chunk)), zap.Error(err))
+			failed += len(chunk)
+			continue
+		}
+
+		for _, res := range results {
+			if res.Error != "" || res.Item == nil {
+				title := ""
+				if res.Index >= 0 && res.Index < len(chunk) {
+					title = chunk[res.Index].Title
+				}
+				a.logger.Warn("Failed to import item", zap.String("title", title), zap.String("error", res.Error))
+				failed++
+				continue
+			}
+			a.cache.ItemsList()[res.Item.ID.String()] = *res.Item
+			created++
+		}
+		a.logger.Info("Import batch uploaded", zap.Int("uploaded", end), zap.Int("total", len(items)))
+	}
+	return created, failed
+}
+
+// printImportReport writes the items and skipped entries of an import result.
+func printImportReport(w io.Writer, res *importers.Result) {
+	for _, item := range res.Items {
+		folder := models.ParseItemMetadata(item.Metadata).Folder
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.Type, folder, item.Title)
+	}
+
+	counts := res.CountByType()
+	types := make([]string, 0, len(counts))
+	for typ := range counts {
+		types = append(types, string(typ))
+	}
+	sort.Strings(types)
+
+	fmt.Fprintf(w, "Total: %d items\n", len(res.Items))
+	for _, typ := range types {
+		fmt.Fprintf(w, "  %s: %d\n", typ, counts[models.ItemType(typ)])
+	}
+
+	if len(res.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped: %d entries\n", len(res.Skipped))
+		for _, s := range res.Skipped {
+			fmt.Fprintf(w, "  %s: %s\n", s.Title, s.Reason)
+		}
+	}
+}
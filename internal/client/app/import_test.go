@@ -0,0 +1,90 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testBitwardenCSV = "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n" +
+	",,login,First,,,0,,alice,pw1,\n" +
+	",,login,Second,,,0,,bob,pw2,\n" +
+	",,note,Third,text,,0,,,,\n"
+
+func writeExport(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestCmdImport_DryRun(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	cmd := app.cmdImport()
+	cmd.SetArgs([]string{"--format", "bitwarden-csv", "--file", writeExport(t, testBitwardenCSV), "--dry-run"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	mockAPI.AssertNotCalled(t, "ExecuteBatch", mock.Anything)
+}
+
+func TestCmdImport_Upload(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	cache := make(map[string]models.Item)
+	mockCache.On("ItemsList").Return(cache)
+	mockAPI.On("ExecuteBatch", mock.MatchedBy(func(req *models.BatchRequest) bool {
+		return len(req.Operations) == 2 && req.Operations[0].Create.Title == "First"
+	})).Return([]models.BatchResult{
+		{Index: 0, Op: models.BatchOpCreate, Status: 201, Item: &models.Item{ID: uuid.New()}},
+		{Index: 1, Op: models.BatchOpCreate, Status: 500, Error: "Internal Server Error"},
+	}, nil).Once()
+	mockAPI.On("ExecuteBatch", mock.MatchedBy(func(req *models.BatchRequest) bool {
+		return len(req.Operations) == 1 && req.Operations[0].Create.Title == "Third"
+	})).Return([]models.BatchResult{
+		{Index: 0, Op: models.BatchOpCreate, Status: 201, Item: &models.Item{ID: uuid.New()}},
+	}, nil).Once()
+
+	cmd := app.cmdImport()
+	cmd.SetArgs([]string{"--format", "bitwarden-csv", "--file", writeExport(t, testBitwardenCSV), "--batch-size", "2"})
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, "failed to import 1 items")
+	assert.Len(t, cache, 2)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdImport_BatchError(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("ExecuteBatch", mock.Anything).Return(nil, assert.AnError)
+
+	cmd := app.cmdImport()
+	cmd.SetArgs([]string{"--format", "bitwarden-csv", "--file", writeExport(t, testBitwardenCSV)})
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, "failed to import 3 items")
+}
+
+func TestCmdImport_UnsupportedFormat(t *testing.T) {
+	app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+	cmd := app.cmdImport()
+	cmd.SetArgs([]string{"--format", "lastpass", "--file", writeExport(t, testBitwardenCSV)})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/otp"
+	"github.com/spf13/cobra"
+)
+
+// otpOutput is the output schema of the otp command.
+// ValidFor is set for TOTP keys and Counter for HOTP keys.
+type otpOutput struct {
+	Code     string   `json:"code"`
+	Type     otp.Type `json:"type"`
+	ValidFor *int     `json:"valid_for_seconds,omitempty"`
+	Counter  *uint64  `json:"counter,omitempty"`
+}
+
+func (a *App) cmdOTP() *cobra.Command {
+	return &cobra.Command{
+		Use:   "otp <item>",
+		Short: "Show the current one-time password of a credential",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := a.resolveItem(args[0])
+			if err != nil {
+				return err
+			}
+
+			item, data, err := a.api.GetItem(id)
+			if err != nil {
+				return fmt.Errorf("failed to get item: %w", err)
+			}
+			if item == nil || item.Type != models.ItemTypeCredential {
+				return errors.New("one-time passwords are only supported for credential items")
+			}
+			if data == nil {
+				return errors.New("item has no data")
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(*data)
+			if err != nil {
+				return fmt.Errorf("failed to decode base64 data: %w", err)
+			}
+			var payload models.CredentialPayload
+			if err = json.Unmarshal(raw, &payload); err != nil {
+				return fmt.Errorf("failed to decode credential: %w", err)
+			}
+			code, key, remaining, err := a.otpCode(item, payload)
+			if err != nil {
+				return err
+			}
+
+			res := otpOutput{Code: code, Type: key.Type}
+			if key.Type == otp.TypeHOTP {
+				counter := key.Counter - 1
+				res.Counter = &counter
+			} else {
+				seconds := int(remaining / time.Second)
+				res.ValidFor = &seconds
+			}
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				fmt.Fprintln(w, code)
+				if res.Counter != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Counter: %d\n", *res.Counter)
+				} else {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Valid for %ds\n", *res.ValidFor)
+				}
+			})
+		},
+	}
+}
+
+// otpCode computes the current one-time password of a credential.
+// For HOTP keys the stored counter is advanced before the code is returned,
+// so that a code is never reused; the returned key holds the new counter.
+func (a *App) otpCode(item *models.Item, payload models.CredentialPayload) (string, *otp.Key, time.Duration, error) {
+	if payload.OTP == "" {
+		return "", nil, 0, errors.New("credential has no OTP secret")
+	}
+	key, err := otp.Parse(payload.OTP)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to parse OTP secret: %w", err)
+	}
+	code, remaining, err := key.Code(time.Now())
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to compute OTP code: %w", err)
+	}
+	if key.Type == otp.TypeHOTP {
+		if err = a.advanceHOTP(item, payload, key); err != nil {
+			return "", nil, 0, err
+		}
+	}
+	return code, key, remaining, nil
+}
+
+// advanceHOTP stores the credential with the HOTP counter incremented.
+func (a *App) advanceHOTP(item *models.Item, payload models.CredentialPayload, key *otp.Key) error {
+	key.Counter++
+	payload.OTP = key.URI()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode credential: %w", err)
+	}
+
+	dataBase64 := base64.StdEncoding.EncodeToString(raw)
+	updated, err := a.api.UpdateItem(item.ID, &models.UpdateItemRequest{DataBase64: &dataBase64})
+	if err != nil {
+		return fmt.Errorf("failed to advance HOTP counter: %w", err)
+	}
+	a.cache.ItemsList()[updated.ID.String()] = *updated
+	return nil
+}
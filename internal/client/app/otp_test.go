@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/otp"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 encoding of the RFC 4226 test secret "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func otpCredential(t *testing.T, secret string) *string {
+	t.Helper()
+	raw, err := json.Marshal(models.CredentialPayload{Username: "alice", Password: "secret", OTP: secret})
+	require.NoError(t, err)
+	data := base64.StdEncoding.EncodeToString(raw)
+	return &data
+}
+
+func TestCmdOTP_TOTP(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	mockAPI.On("GetItem", item.ID).Return(item, otpCredential(t, "otpauth://totp/Mail:alice?secret="+rfcSecret+"&digits=8"), nil)
+
+	cmd := app.cmdOTP()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{item.ID.String()})
+
+	require.NoError(t, cmd.Execute())
+	assert.Regexp(t, `^\d{8}\n$`, out.String())
+	assert.Regexp(t, `^Valid for \d+s\n$`, errOut.String())
+	mockAPI.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestCmdOTP_HOTPAdvancesCounter(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Bank"}
+	mockAPI.On("GetItem", item.ID).Return(item, otpCredential(t, "otpauth://hotp/Bank?secret="+rfcSecret+"&counter=1"), nil)
+	mockAPI.On("UpdateItem", item.ID, mock.MatchedBy(func(req *models.UpdateItemRequest) bool {
+		raw, err := base64.StdEncoding.DecodeString(*req.DataBase64)
+		if err != nil {
+			return false
+		}
+		var payload models.CredentialPayload
+		if json.Unmarshal(raw, &payload) != nil || payload.Password != "secret" {
+			return false
+		}
+		key, err := otp.Parse(payload.OTP)
+		return err == nil && key.Counter == 2
+	})).Return(item, nil)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+
+	cmd := app.cmdOTP()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{item.ID.String()})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "287082\n", out.String())
+	assert.Equal(t, "Counter: 1\n", errOut.String())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdOTP_HOTPUpdateFails(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Bank"}
+	mockAPI.On("GetItem", item.ID).Return(item, otpCredential(t, "otpauth://hotp/Bank?secret="+rfcSecret+"&counter=1"), nil)
+	mockAPI.On("UpdateItem", item.ID, mock.Anything).Return(nil, errors.New("offline"))
+
+	cmd := app.cmdOTP()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{item.ID.String()})
+
+	assert.Error(t, cmd.Execute())
+	assert.NotContains(t, out.String(), "287082")
+}
+
+func TestCmdOTP_Errors(t *testing.T) {
+	credential := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "No OTP"}
+	text := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	invalid := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Invalid"}
+
+	mockAPI := new(MockApiService)
+	mockAPI.On("GetItem", credential.ID).Return(credential, otpCredential(t, ""), nil)
+	mockAPI.On("GetItem", text.ID).Return(text, otpCredential(t, rfcSecret), nil)
+	mockAPI.On("GetItem", invalid.ID).Return(invalid, otpCredential(t, "not base32!"), nil)
+	mockAPI.On("ListItems").Return([]*models.Item{credential, text, invalid}, nil)
+	mockCache := new(MockCacheRepository)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"no arguments", []string{}, "accepts 1 arg"},
+		{"unknown item", []string{"nope"}, "item not found"},
+		{"no secret", []string{credential.ID.String()}, "credential has no OTP secret"},
+		{"not a credential", []string{text.ID.String()}, "only supported for credential items"},
+		{"invalid secret", []string{invalid.ID.String()}, "failed to parse OTP secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := app.cmdOTP()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
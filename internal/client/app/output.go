@@ -0,0 +1,285 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selected with --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputEnv   = "env"
+)
+
+// outputFormats lists the supported output formats.
+var outputFormats = []string{outputTable, outputJSON, outputYAML, outputEnv}
+
+// errUnknownOutput is returned for an unsupported --output value.
+var errUnknownOutput = errors.New("unknown output format")
+
+// itemOutput is the output schema of an item.
+// Data is only set by commands that decrypt the item.
+type itemOutput struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       models.ItemType `json:"type"`
+	Title      string          `json:"title"`
+	Folder     string          `json:"folder,omitempty"`
+	URL        string          `json:"url,omitempty"`
+	Notes      string          `json:"notes,omitempty"`
+	Source     string          `json:"source,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	DeletedAt  *time.Time      `json:"deleted_at,omitempty"`
+	Data       any             `json:"data,omitempty"`
+	DataBase64 string          `json:"data_base64,omitempty"`
+}
+
+// newItemOutput converts an item to its output schema.
+func newItemOutput(item models.Item) itemOutput {
+	meta := models.ParseItemMetadata(item.Metadata)
+	return itemOutput{
+		ID:        item.ID,
+		Type:      item.Type,
+		Title:     item.Title,
+		Folder:    meta.Folder,
+		URL:       meta.URL,
+		Notes:     meta.Notes,
+		Source:    meta.Source,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+		ExpiresAt: item.ExpiresAt,
+		DeletedAt: item.DeletedAt,
+	}
+}
+
+// newItemOutputs converts a list of items to their output schema, skipping nil items.
+// Always returns a non-nil slice so that an empty list is rendered as [].
+func newItemOutputs(items []*models.Item) []itemOutput {
+	out := make([]itemOutput, 0, len(items))
+	for _, item := range items {
+		if item != nil {
+			out = append(out, newItemOutput(*item))
+		}
+	}
+	return out
+}
+
+// withData sets the decrypted data of the item.
+// JSON objects of credentials, cards and SSH keys are kept structured, binary data is base64-encoded.
+func (o itemOutput) withData(data []byte) itemOutput {
+	if len(data) == 0 {
+		return o
+	}
+	switch o.Type {
+	case models.ItemTypeBinary:
+		o.DataBase64 = base64.StdEncoding.EncodeToString(data)
+	case models.ItemTypeCredential, models.ItemTypeCard, models.ItemTypeSSHKey:
+		var fields map[string]any
+		if json.Unmarshal(data, &fields) == nil {
+			o.Data = fields
+		} else {
+			o.Data = string(data)
+		}
+	default:
+		o.Data = string(data)
+	}
+	return o
+}
+
+// printTable writes an item as aligned "Key: value" lines.
+func (o itemOutput) printTable(w io.Writer) {
+	fmt.Fprintf(w, "ID:       %s\n", o.ID)
+	fmt.Fprintf(w, "Type:     %s\n", o.Type)
+	fmt.Fprintf(w, "Title:    %s\n", o.Title)
+	if o.Folder != "" {
+		fmt.Fprintf(w, "Folder:   %s\n", o.Folder)
+	}
+	if o.URL != "" {
+		fmt.Fprintf(w, "URL:      %s\n", o.URL)
+	}
+	if o.Notes != "" {
+		fmt.Fprintf(w, "Notes:    %s\n", o.Notes)
+	}
+	if o.Source != "" {
+		fmt.Fprintf(w, "Source:   %s\n", o.Source)
+	}
+	fmt.Fprintf(w, "Created:  %s\n", o.CreatedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(w, "Updated:  %s\n", o.UpdatedAt.Local().Format(time.DateTime))
+	if o.ExpiresAt != nil {
+		fmt.Fprintf(w, "Expires:  %s\n", o.ExpiresAt.Local().Format(time.DateTime))
+	}
+	if o.DeletedAt != nil {
+		fmt.Fprintf(w, "Deleted:  %s\n", o.DeletedAt.Local().Format(time.DateTime))
+	}
+}
+
+// render writes a command result in the selected output format.
+// The table format is written by the command itself with printTable.
+// When a field is selected only its value is printed, one line per list element.
+func (a *App) render(w io.Writer, v any, printTable func(w io.Writer)) error {
+	if a.field != "" {
+		return writeField(w, v, a.field)
+	}
+
+	switch a.output {
+	case "", outputTable:
+		printTable(w)
+		return nil
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err = enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	case outputEnv:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		prefix := ""
+		if _, ok := generic.([]any); ok {
+			prefix = "ITEMS"
+		}
+		lines := flattenEnv(prefix, generic, nil)
+		sort.Strings(lines)
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s, expected one of %s", errUnknownOutput, a.output, strings.Join(outputFormats, "|"))
+	}
+}
+
+// toGeneric converts a value to maps, slices and scalars through its JSON encoding.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	var generic any
+	if err = json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return generic, nil
+}
+
+// writeField prints the value of a field of the result.
+// Fields are looked up at the top level first and in the item data second.
+func writeField(w io.Writer, v any, field string) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+
+	values, ok := generic.([]any)
+	if !ok {
+		value, found := lookupField(generic, field)
+		if !found {
+			return fmt.Errorf("%w: %s", errNoSuchField, field)
+		}
+		fmt.Fprintln(w, formatScalar(value))
+		return nil
+	}
+	for _, elem := range values {
+		value, _ := lookupField(elem, field)
+		fmt.Fprintln(w, formatScalar(value))
+	}
+	return nil
+}
+
+// lookupField returns a field of an object or of its data object.
+func lookupField(v any, field string) (any, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	if value, ok := obj[field]; ok {
+		return value, true
+	}
+	if data, ok := obj["data"].(map[string]any); ok {
+		value, ok := data[field]
+		return value, ok
+	}
+	return nil, false
+}
+
+// formatScalar formats a value for plain output. Objects and lists are printed as JSON.
+func formatScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+var envUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// flattenEnv converts a value to KEY=value lines.
+// Nested keys are joined with underscores and list elements are numbered.
+func flattenEnv(prefix string, v any, lines []string) []string {
+	join := func(key string) string {
+		key = strings.ToUpper(envUnsafe.ReplaceAllString(key, "_"))
+		if prefix == "" {
+			return key
+		}
+		return prefix + "_" + key
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			lines = flattenEnv(join(key), value, lines)
+		}
+	case []any:
+		for i, value := range v {
+			lines = flattenEnv(join(strconv.Itoa(i)), value, lines)
+		}
+	default:
+		lines = append(lines, prefix+"="+shellQuote(formatScalar(v)))
+	}
+	return lines
+}
+
+// shellQuote quotes a value for POSIX shells unless it only contains safe characters.
+func shellQuote(s string) string {
+	safe := s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./-_", r))
+	}) < 0
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	item := itemOutput{
+		ID:        id,
+		Type:      models.ItemTypeCredential,
+		Title:     "Mail",
+		Folder:    "work",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}.withData([]byte(`{"username":"alice","password":"it's secret"}`))
+
+	tests := []struct {
+		format   string
+		field    string
+		expected string
+	}{
+		{outputTable, "", "table\n"},
+		{outputYAML, "", `created_at: "2026-01-02T03:04:05Z"
+data:
+  password: it's secret
+  username: alice
+folder: work
+id: 123e4567-e89b-12d3-a456-426614174000
+title: Mail
+type: credential
+updated_at: "2026-01-02T03:04:05Z"
+`},
+		{outputEnv, "", `CREATED_AT=2026-01-02T03:04:05Z
+DATA_PASSWORD='it'\''s secret'
+DATA_USERNAME=alice
+FOLDER=work
+ID=123e4567-e89b-12d3-a456-426614174000
+TITLE=Mail
+TYPE=credential
+UPDATED_AT=2026-01-02T03:04:05Z
+`},
+		{outputJSON, "title", "Mail\n"},
+		{outputJSON, "password", "it's secret\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+tt.field, func(t *testing.T) {
+			app := createTestApp()
+			app.output, app.field = tt.format, tt.field
+			var out bytes.Buffer
+			require.NoError(t, app.render(&out, item, func(w io.Writer) { fmt.Fprintln(w, "table") }))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestRender_JSON(t *testing.T) {
+	app := createTestApp()
+	app.output = outputJSON
+	items := []itemOutput{
+		{ID: uuid.New(), Type: models.ItemTypeText, Title: "First"},
+		{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Second"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, app.render(&out, items, nil))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, "First", decoded[0]["title"])
+	assert.Equal(t, "card", decoded[1]["type"])
+	assert.NotContains(t, decoded[0], "data")
+}
+
+func TestRender_ListFieldsAndEnv(t *testing.T) {
+	app := createTestApp()
+	items := []itemOutput{{Title: "First"}, {Title: "Second"}}
+
+	app.field = "title"
+	var out bytes.Buffer
+	require.NoError(t, app.render(&out, items, nil))
+	assert.Equal(t, "First\nSecond\n", out.String())
+
+	app.field, app.output = "", outputEnv
+	out.Reset()
+	require.NoError(t, app.render(&out, items, nil))
+	assert.Contains(t, out.String(), "ITEMS_0_TITLE=First\n")
+	assert.Contains(t, out.String(), "ITEMS_1_TITLE=Second\n")
+}
+
+func TestRender_Errors(t *testing.T) {
+	app := createTestApp()
+	app.field = "missing"
+	assert.ErrorIs(t, app.render(&bytes.Buffer{}, itemOutput{}, nil), errNoSuchField)
+
+	app.field, app.output = "", "xml"
+	assert.ErrorIs(t, app.render(&bytes.Buffer{}, itemOutput{}, nil), errUnknownOutput)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"usage", fmt.Errorf("%w: unknown flag", errUsage), ExitUsage},
+		{"unknown output", errUnknownOutput, ExitUsage},
+		{"item reference", fmt.Errorf("%w: Mail", errItemNotFound), ExitNotFound},
+		{"server not found", fmt.Errorf("failed to get item: %w", &services.APIError{StatusCode: 404}), ExitNotFound},
+		{"unauthorized", fmt.Errorf("failed to list items: %w", &services.APIError{StatusCode: 401}), ExitAuth},
+		{"network", fmt.Errorf("failed: %w", services.ErrUnavailable), ExitNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestCmdGet_JSON(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	app.output = outputJSON
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail", Metadata: `{"folder":"work"}`}
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(testCredentialJSON), nil)
+
+	cmd := app.cmdGet()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--id", item.ID.String()})
+	require.NoError(t, cmd.Execute())
+
+	var decoded struct {
+		ID     uuid.UUID         `json:"id"`
+		Folder string            `json:"folder"`
+		Data   map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, item.ID, decoded.ID)
+	assert.Equal(t, "work", decoded.Folder)
+	assert.Equal(t, "alice", decoded.Data["username"])
+	assert.Equal(t, maskedValue, decoded.Data["password"])
+}
+
+func TestCmdGet_Field(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	app.field = "password"
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(testCredentialJSON), nil)
+
+	cmd := app.cmdGet()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--id", item.ID.String()})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "hunter2\n", out.String())
+}
+
+func TestCmdGet_NotFoundDoesNotUseCache(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	item := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Gone"}
+	mockCache.On("ItemsList").Return(map[string]models.Item{item.ID.String(): item})
+	mockAPI.On("GetItem", item.ID).Return(nil, nil, &services.APIError{StatusCode: 404})
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	cmd := app.cmdGet()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", item.ID.String()})
+	err := cmd.Execute()
+	assert.Equal(t, ExitNotFound, ExitCode(err))
+}
+
+func TestCmdList_OfflineUsesCache(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	item := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Cached"}
+	mockCache.On("ItemsList").Return(map[string]models.Item{item.ID.String(): item})
+	mockAPI.On("ListItems").Return(nil, fmt.Errorf("failed to list items: %w", services.ErrUnavailable))
+	app := createTestAppWithMocks(mockAPI, mockCache)
+	app.field = "id"
+
+	cmd := app.cmdList()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, item.ID.String()+"\n", out.String())
+}
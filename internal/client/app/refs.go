@@ -0,0 +1,115 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// minIDPrefixLength is the shortest UUID prefix accepted as an item reference.
+const minIDPrefixLength = 4
+
+// itemRefUsage describes the item reference flags.
+const itemRefUsage = "Item ID, unique ID prefix, title or folder/title"
+
+var (
+	// errItemNotFound is returned when a reference matches no item.
+	errItemNotFound = errors.New("item not found")
+	// errAmbiguousRef is returned when a reference matches several items.
+	errAmbiguousRef = errors.New("reference matches several items")
+)
+
+// resolveItem returns the ID of the item a reference points to.
+// A reference is a full UUID, a unique UUID prefix of at least four characters,
+// an exact title or a folder/title path. It is resolved against the local cache
+// first and against the server list when the cache has no unique match.
+func (a *App) resolveItem(ref string) (uuid.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return uuid.Nil, errors.New("item reference is empty")
+	}
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+
+	cached := make([]models.Item, 0, len(a.cache.ItemsList()))
+	for _, item := range a.cache.ItemsList() {
+		cached = append(cached, item)
+	}
+	id, err := matchItemRef(ref, cached)
+	if err == nil {
+		return id, nil
+	}
+
+	items, listErr := a.api.ListItems()
+	if listErr != nil {
+		a.logger.Warn("Failed to get items from server, using cache", zap.Error(listErr))
+		return uuid.Nil, err
+	}
+	fresh := make([]models.Item, 0, len(items))
+	for _, item := range items {
+		if item != nil {
+			fresh = append(fresh, *item)
+		}
+	}
+	return matchItemRef(ref, fresh)
+}
+
+// resolveItems resolves several references, see resolveItem.
+func (a *App) resolveItems(refs []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		id, err := a.resolveItem(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// matchItemRef finds the single item matching a reference.
+// An item matches if its ID starts with the reference, its title equals the
+// reference or its folder and title joined with a slash equal the reference.
+func matchItemRef(ref string, items []models.Item) (uuid.UUID, error) {
+	prefix := ""
+	if len(ref) >= minIDPrefixLength {
+		prefix = strings.ToLower(ref)
+	}
+
+	var matches []models.Item
+	for _, item := range items {
+		if (prefix != "" && strings.HasPrefix(item.ID.String(), prefix)) ||
+			item.Title == ref || itemPath(item) == ref {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("%w: %s", errItemNotFound, ref)
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return itemPath(matches[i]) < itemPath(matches[j]) })
+	var candidates strings.Builder
+	for _, item := range matches {
+		fmt.Fprintf(&candidates, "\n  %s\t%s\t%s", item.ID, item.Type, itemPath(item))
+	}
+	return uuid.Nil, fmt.Errorf("%w: %q, use the ID or a longer reference:%s", errAmbiguousRef, ref, candidates.String())
+}
+
+// itemPath returns the folder/title path of an item, or the title for items without a folder.
+func itemPath(item models.Item) string {
+	folder := models.ParseItemMetadata(item.Metadata).Folder
+	if folder == "" {
+		return item.Title
+	}
+	return strings.TrimSuffix(folder, "/") + "/" + item.Title
+}
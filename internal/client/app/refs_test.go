@@ -0,0 +1,141 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchItemRef(t *testing.T) {
+	mail := models.Item{ID: uuid.MustParse("1a2b3c4d-0000-4000-8000-000000000001"), Type: models.ItemTypeCredential, Title: "Mail"}
+	workMail := models.Item{ID: uuid.MustParse("1a2b9999-0000-4000-8000-000000000002"), Type: models.ItemTypeCredential,
+		Title: "Mail", Metadata: `{"folder":"work"}`}
+	visa := models.Item{ID: uuid.MustParse("f00dbabe-0000-4000-8000-000000000003"), Type: models.ItemTypeCard, Title: "Visa"}
+	items := []models.Item{mail, workMail, visa}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    uuid.UUID
+		wantErr error
+	}{
+		{"unique prefix", "1a2b3c", mail.ID, nil},
+		{"upper case prefix", "F00DBABE", visa.ID, nil},
+		{"title", "Visa", visa.ID, nil},
+		{"folder and title", "work/Mail", workMail.ID, nil},
+		{"ambiguous prefix", "1a2b", uuid.Nil, errAmbiguousRef},
+		{"ambiguous title", "Mail", uuid.Nil, errAmbiguousRef},
+		{"prefix too short", "f00", uuid.Nil, errItemNotFound},
+		{"title is case sensitive", "visa", uuid.Nil, errItemNotFound},
+		{"unknown", "Bank", uuid.Nil, errItemNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := matchItemRef(tt.ref, items)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+}
+
+func TestMatchItemRef_ListsCandidates(t *testing.T) {
+	mail := models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	workMail := models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail", Metadata: `{"folder":"work"}`}
+
+	_, err := matchItemRef("Mail", []models.Item{workMail, mail})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), mail.ID.String()+"\tcredential\tMail")
+	assert.Contains(t, err.Error(), workMail.ID.String()+"\tcredential\twork/Mail")
+}
+
+func TestResolveItem(t *testing.T) {
+	cached := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Cached"}
+	remote := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Remote"}
+
+	t.Run("full UUID", func(t *testing.T) {
+		app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+		id, err := app.resolveItem(remote.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, remote.ID, id)
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		mockCache := new(MockCacheRepository)
+		mockCache.On("ItemsList").Return(map[string]models.Item{cached.ID.String(): cached})
+		app := createTestAppWithMocks(mockAPI, mockCache)
+
+		id, err := app.resolveItem("Cached")
+		require.NoError(t, err)
+		assert.Equal(t, cached.ID, id)
+		mockAPI.AssertNotCalled(t, "ListItems")
+	})
+
+	t.Run("server fallback", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		mockCache := new(MockCacheRepository)
+		mockCache.On("ItemsList").Return(map[string]models.Item{cached.ID.String(): cached})
+		mockAPI.On("ListItems").Return([]*models.Item{remote}, nil)
+		app := createTestAppWithMocks(mockAPI, mockCache)
+
+		id, err := app.resolveItem("Remote")
+		require.NoError(t, err)
+		assert.Equal(t, remote.ID, id)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		mockCache := new(MockCacheRepository)
+		mockCache.On("ItemsList").Return(map[string]models.Item{})
+		mockAPI.On("ListItems").Return(nil, errors.New("connection refused"))
+		app := createTestAppWithMocks(mockAPI, mockCache)
+
+		_, err := app.resolveItem("Remote")
+		assert.ErrorIs(t, err, errItemNotFound)
+	})
+}
+
+func TestCmdGet_ByTitle(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note", Metadata: `{"folder":"personal"}`}
+	mockCache.On("ItemsList").Return(map[string]models.Item{item.ID.String(): *item})
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData("hello"), nil)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	cmd := app.cmdGet()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--id", "personal/Note"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "hello")
+}
+
+func TestCmdDelete_AmbiguousRef(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	first := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	second := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	mockCache.On("ItemsList").Return(map[string]models.Item{first.ID.String(): first, second.ID.String(): second})
+	mockAPI.On("ListItems").Return([]*models.Item{&first, &second}, nil)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	cmd := app.cmdDelete()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", "Note"})
+	err := cmd.Execute()
+	assert.ErrorIs(t, err, errAmbiguousRef)
+	mockAPI.AssertNotCalled(t, "DeleteItem", mock.Anything)
+}
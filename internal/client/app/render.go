@@ -0,0 +1,200 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is how often the render command checks the referenced items for changes.
+const defaultWatchInterval = 30 * time.Second
+
+// renderedTemplate is the result of a template rendering.
+type renderedTemplate struct {
+	content []byte
+	// versions holds the update time of every item the template referenced.
+	versions map[uuid.UUID]time.Time
+}
+
+// renderTemplate executes a template with the secret function resolving items through the server.
+func (a *App) renderTemplate(path string) (*renderedTemplate, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	fetcher := newSecretFetcher(a)
+	funcs := template.FuncMap{
+		// secret returns a field of an item, the default field if none is given.
+		"secret": func(ref string, field ...string) (string, error) {
+			if len(field) > 1 {
+				return "", errors.New("secret takes an item and at most one field")
+			}
+			name := fieldDefault
+			if len(field) == 1 {
+				name = field[0]
+			}
+			value, err := fetcher.value(ref, name)
+			return string(value), err
+		},
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, nil); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	res := &renderedTemplate{content: buf.Bytes(), versions: make(map[uuid.UUID]time.Time, len(fetcher.items))}
+	for _, fetched := range fetcher.items {
+		res.versions[fetched.item.ID] = fetched.item.UpdatedAt
+	}
+	return res, nil
+}
+
+// writeFileAtomic replaces a file by renaming a temporary file created next to it,
+// so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// itemVersions returns the current update time of the given items, items that no longer exist are omitted.
+func (a *App) itemVersions(ids map[uuid.UUID]time.Time) (map[uuid.UUID]time.Time, error) {
+	items, err := a.api.ListItems()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uuid.UUID]time.Time, len(ids))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if _, ok := ids[item.ID]; ok {
+			res[item.ID] = item.UpdatedAt
+		}
+	}
+	return res, nil
+}
+
+func (a *App) cmdRender() *cobra.Command {
+	var tmplPath, outPath, mode string
+	var watch bool
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "render --template FILE --out FILE",
+		Short: "Render a template with secrets into a file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tmplPath == "" || outPath == "" {
+				return fmt.Errorf("%w: --template and --out are required", errUsage)
+			}
+			perm, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil || perm > 0o777 {
+				return fmt.Errorf("%w: invalid file mode %q", errUsage, mode)
+			}
+			if watch && interval <= 0 {
+				return fmt.Errorf("%w: watch interval must be positive", errUsage)
+			}
+
+			rendered, err := a.renderTemplate(tmplPath)
+			if err != nil {
+				return err
+			}
+			if err = writeFileAtomic(outPath, rendered.content, os.FileMode(perm)); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Rendered %s\n", outPath)
+			if !watch {
+				return nil
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return a.watchTemplate(ctx, cmd, tmplPath, outPath, os.FileMode(perm), interval, rendered.versions)
+		},
+	}
+
+	cmd.Flags().StringVar(&tmplPath, "template", "", "Path to the template")
+	cmd.Flags().StringVar(&outPath, "out", "", "Path to the rendered file")
+	cmd.Flags().StringVar(&mode, "mode", "0600", "File mode of the rendered file")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep running and render again when a referenced item changes")
+	cmd.Flags().DurationVar(&interval, "interval", defaultWatchInterval, "How often to check for changes in watch mode")
+	return cmd
+}
+
+// watchTemplate polls the referenced items and renders the template again when one of them changes.
+// Failures are reported and the previous file is kept until a rendering succeeds.
+func (a *App) watchTemplate(ctx context.Context, cmd *cobra.Command, tmplPath, outPath string, perm os.FileMode, interval time.Duration, versions map[uuid.UUID]time.Time) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	seen := versions
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := a.itemVersions(versions)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Failed to check items: %v\n", err)
+			continue
+		}
+		if maps.EqualFunc(current, seen, time.Time.Equal) {
+			continue
+		}
+		// Remember what was seen so a failing rendering is not retried until the items change again.
+		seen = current
+
+		rendered, err := a.renderTemplate(tmplPath)
+		if err == nil {
+			err = writeFileAtomic(outPath, rendered.content, perm)
+		}
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Failed to render %s: %v\n", outPath, err)
+			continue
+		}
+		versions, seen = rendered.versions, rendered.versions
+		fmt.Fprintf(cmd.ErrOrStderr(), "Rendered %s\n", outPath)
+	}
+}
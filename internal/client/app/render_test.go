@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, text string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "config.tmpl")
+	require.NoError(t, os.WriteFile(tmpl, []byte(text), 0600))
+	return tmpl, filepath.Join(dir, "config.ini")
+}
+
+func TestCmdRender(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "DB"}
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(testCredentialJSON), nil).Once()
+
+	tmpl, out := writeTemplate(t, `user={{ secret "`+item.ID.String()+`" "username" }}
+password={{ secret "`+item.ID.String()+`" }}
+`)
+	cmd := app.cmdRender()
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--template", tmpl, "--out", out})
+	require.NoError(t, cmd.Execute())
+
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "user=alice\npassword=hunter2\n", string(content))
+	info, err := os.Stat(out)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdRender_Errors(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "DB"}
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(testCredentialJSON), nil)
+
+	tests := []struct {
+		name string
+		text string
+		args []string
+	}{
+		{"unknown field", `{{ secret "` + item.ID.String() + `" "cvv" }}`, nil},
+		{"too many fields", `{{ secret "` + item.ID.String() + `" "username" "password" }}`, nil},
+		{"syntax", `{{ secret `, nil},
+		{"mode", `plain`, []string{"--mode", "999"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, out := writeTemplate(t, tt.text)
+			require.NoError(t, os.WriteFile(out, []byte("previous"), 0600))
+
+			cmd := app.cmdRender()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"--template", tmpl, "--out", out}, tt.args...))
+			require.Error(t, cmd.Execute())
+
+			content, err := os.ReadFile(out)
+			require.NoError(t, err)
+			assert.Equal(t, "previous", string(content), "a failed rendering must keep the previous file")
+		})
+	}
+}
+
+func TestCmdRender_Watch(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	v1 := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Motd", UpdatedAt: created}
+	v2 := &models.Item{ID: v1.ID, Type: models.ItemTypeText, Title: "Motd", UpdatedAt: created.Add(time.Hour)}
+	mockAPI.On("GetItem", v1.ID).Return(v1, encodedData("first"), nil).Once()
+	mockAPI.On("GetItem", v1.ID).Return(v2, encodedData("second"), nil).Once()
+	mockAPI.On("ListItems").Return([]*models.Item{v1}, nil).Once()
+	mockAPI.On("ListItems").Return([]*models.Item{v2}, nil)
+
+	tmpl, out := writeTemplate(t, `{{ secret "`+v1.ID.String()+`" }}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd := app.cmdRender()
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--template", tmpl, "--out", out, "--watch", "--interval", "10ms"})
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(out)
+		return err == nil && string(content) == "second"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	mockAPI.AssertExpectations(t)
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+)
+
+// secretSpec is a NAME=REF#FIELD mapping of the run command.
+type secretSpec struct {
+	name  string
+	ref   string
+	field string
+}
+
+// parseSecretSpec parses NAME=REF#FIELD. The field defaults to the default field of the item.
+func parseSecretSpec(raw string) (secretSpec, error) {
+	name, target, ok := strings.Cut(raw, "=")
+	if !ok || name == "" || target == "" {
+		return secretSpec{}, fmt.Errorf("%w: invalid secret mapping %q, expected NAME=ITEM#FIELD", errUsage, raw)
+	}
+	if strings.ContainsAny(name, " \t\n\x00") {
+		return secretSpec{}, fmt.Errorf("%w: invalid environment variable name %q", errUsage, name)
+	}
+
+	spec := secretSpec{name: name, ref: target, field: fieldDefault}
+	if i := strings.LastIndex(target, "#"); i >= 0 {
+		spec.ref, spec.field = target[:i], target[i+1:]
+		if spec.ref == "" || spec.field == "" {
+			return secretSpec{}, fmt.Errorf("%w: invalid secret mapping %q, expected NAME=ITEM#FIELD", errUsage, raw)
+		}
+	}
+	return spec, nil
+}
+
+// ChildExitError carries the exit code of a child process that did not succeed.
+type ChildExitError struct {
+	Code int
+}
+
+func (e *ChildExitError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.Code)
+}
+
+// secretFetcher decrypts items referenced by the run and render commands.
+// Every item is downloaded once per fetcher.
+type secretFetcher struct {
+	app   *App
+	items map[string]fetchedItem
+}
+
+// fetchedItem is a decrypted item.
+type fetchedItem struct {
+	item *models.Item
+	data []byte
+}
+
+func newSecretFetcher(a *App) *secretFetcher {
+	return &secretFetcher{app: a, items: make(map[string]fetchedItem)}
+}
+
+// fetch resolves and decrypts the item a reference points to.
+func (f *secretFetcher) fetch(ref string) (fetchedItem, error) {
+	if fetched, ok := f.items[ref]; ok {
+		return fetched, nil
+	}
+
+	id, err := f.app.resolveItem(ref)
+	if err != nil {
+		return fetchedItem{}, err
+	}
+	item, data, err := f.app.api.GetItem(id)
+	if err != nil {
+		return fetchedItem{}, fmt.Errorf("failed to get item %q: %w", ref, err)
+	}
+	if item == nil {
+		return fetchedItem{}, fmt.Errorf("%w: %s", errItemNotFound, ref)
+	}
+
+	fetched := fetchedItem{item: item}
+	if data != nil && *data != "" {
+		if fetched.data, err = base64.StdEncoding.DecodeString(*data); err != nil {
+			return fetchedItem{}, fmt.Errorf("failed to decode data of %q: %w", ref, err)
+		}
+	}
+	f.items[ref] = fetched
+	return fetched, nil
+}
+
+// value returns a field of a referenced item.
+// The default field of binary items is their whole data.
+func (f *secretFetcher) value(ref, field string) ([]byte, error) {
+	fetched, err := f.fetch(ref)
+	if err != nil {
+		return nil, err
+	}
+	if fetched.item.Type == models.ItemTypeBinary && field == fieldDefault {
+		return fetched.data, nil
+	}
+	value, err := f.app.fieldValue(fetched.item, fetched.data, field)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of %q: %w", field, ref, err)
+	}
+	return []byte(value), nil
+}
+
+func (a *App) cmdRun() *cobra.Command {
+	var envSpecs, fileSpecs []string
+	cmd := &cobra.Command{
+		Use:   "run [--env NAME=ITEM#FIELD]... [--file NAME=ITEM#FIELD]... -- COMMAND [ARGS]...",
+		Short: "Run a command with secrets injected into its environment",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var envs, files []secretSpec
+			for _, raw := range envSpecs {
+				spec, err := parseSecretSpec(raw)
+				if err != nil {
+					return err
+				}
+				envs = append(envs, spec)
+			}
+			for _, raw := range fileSpecs {
+				spec, err := parseSecretSpec(raw)
+				if err != nil {
+					return err
+				}
+				files = append(files, spec)
+			}
+			if len(envs) == 0 && len(files) == 0 {
+				return fmt.Errorf("%w: at least one --env or --file mapping is required", errUsage)
+			}
+
+			fetcher := newSecretFetcher(a)
+			env := os.Environ()
+			for _, spec := range envs {
+				value, err := fetcher.value(spec.ref, spec.field)
+				if err != nil {
+					return err
+				}
+				env = append(env, spec.name+"="+string(value))
+			}
+
+			if len(files) > 0 {
+				dir, err := os.MkdirTemp("", "gophkeeper-run-")
+				if err != nil {
+					return fmt.Errorf("failed to create directory for secret files: %w", err)
+				}
+				defer os.RemoveAll(dir)
+
+				for i, spec := range files {
+					value, err := fetcher.value(spec.ref, spec.field)
+					if err != nil {
+						return err
+					}
+					path := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(spec.name)))
+					if err = os.WriteFile(path, value, 0600); err != nil {
+						return fmt.Errorf("failed to write secret file: %w", err)
+					}
+					env = append(env, spec.name+"="+path)
+				}
+			}
+
+			err := runChild(cmd, args, env)
+			var exitErr *ChildExitError
+			if errors.As(err, &exitErr) {
+				// The child has reported its own failure, only the exit code is passed on.
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&envSpecs, "env", nil, "Set an environment variable to a field of an item (NAME=ITEM#FIELD, repeatable)")
+	cmd.Flags().StringArrayVar(&fileSpecs, "file", nil, "Write a field of an item to a temporary file and set an environment variable to its path (NAME=ITEM#FIELD, repeatable)")
+	return cmd
+}
+
+// runChild runs a command with the environment, forwarding signals to it.
+// Returns *ChildExitError if the command exits with a non-zero status.
+func runChild(cmd *cobra.Command, args, env []string) error {
+	child := exec.Command(args[0], args[1:]...)
+	child.Env = env
+	child.Stdin = cmd.InOrStdin()
+	child.Stdout = cmd.OutOrStdout()
+	child.Stderr = cmd.ErrOrStderr()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+	defer signal.Stop(signals)
+
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- child.Wait() }()
+	for {
+		select {
+		case sig := <-signals:
+			_ = child.Process.Signal(sig)
+		case err := <-done:
+			if err == nil {
+				return nil
+			}
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return fmt.Errorf("failed to run %s: %w", args[0], err)
+			}
+			code := exitErr.ExitCode()
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+				code = 128 + int(status.Signal())
+			}
+			return &ChildExitError{Code: code}
+		}
+	}
+}
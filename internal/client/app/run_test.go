@@ -0,0 +1,128 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretSpec(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    secretSpec
+		wantErr bool
+	}{
+		{"DB_PASS=prod/db#password", secretSpec{name: "DB_PASS", ref: "prod/db", field: fieldPassword}, false},
+		{"TOKEN=api", secretSpec{name: "TOKEN", ref: "api", field: fieldDefault}, false},
+		{"X=a#b#otp", secretSpec{name: "X", ref: "a#b", field: fieldOTP}, false},
+		{"NOVALUE", secretSpec{}, true},
+		{"=item", secretSpec{}, true},
+		{"X=item#", secretSpec{}, true},
+		{"BAD NAME=item", secretSpec{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			spec, err := parseSecretSpec(tt.raw)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errUsage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec)
+		})
+	}
+}
+
+func runTestItem(t *testing.T, mockAPI *MockApiService) *models.Item {
+	t.Helper()
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "DB"}
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(testCredentialJSON), nil).Once()
+	return item
+}
+
+func TestCmdRun_Env(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	item := runTestItem(t, mockAPI)
+
+	cmd := app.cmdRun()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{
+		"--env", "DB_USER=" + item.ID.String() + "#username",
+		"--env", "DB_PASS=" + item.ID.String(),
+		"--", "/bin/sh", "-c", `printf '%s:%s' "$DB_USER" "$DB_PASS"`,
+	})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "alice:hunter2", out.String())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdRun_File(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	item := runTestItem(t, mockAPI)
+
+	cmd := app.cmdRun()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{
+		"--file", "PASS_FILE=" + item.ID.String() + "#password",
+		"--", "/bin/sh", "-c", `echo "$PASS_FILE"; stat -c %a "$PASS_FILE"; cat "$PASS_FILE"`,
+	})
+	require.NoError(t, cmd.Execute())
+
+	lines := strings.SplitN(out.String(), "\n", 3)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "600", lines[1])
+	assert.Equal(t, "hunter2", lines[2])
+	_, err := os.Stat(lines[0])
+	assert.True(t, os.IsNotExist(err), "secret file must be removed after the command exits")
+}
+
+func TestCmdRun_ExitCode(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	item := runTestItem(t, mockAPI)
+
+	cmd := app.cmdRun()
+	cmd.SetOut(&bytes.Buffer{})
+	errOut := &bytes.Buffer{}
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"--env", "X=" + item.ID.String(), "--", "/bin/sh", "-c", "exit 7"})
+	err := cmd.Execute()
+	var childErr *ChildExitError
+	require.ErrorAs(t, err, &childErr)
+	assert.Equal(t, 7, childErr.Code)
+	assert.Equal(t, 7, ExitCode(err))
+	assert.Empty(t, errOut.String())
+}
+
+func TestCmdRun_Errors(t *testing.T) {
+	t.Run("no mappings", func(t *testing.T) {
+		cmd := createTestApp().cmdRun()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--", "/bin/true"})
+		assert.Equal(t, ExitUsage, ExitCode(cmd.Execute()))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		mockAPI := new(MockApiService)
+		app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+		item := runTestItem(t, mockAPI)
+
+		cmd := app.cmdRun()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs([]string{"--env", "X=" + item.ID.String() + "#cvv", "--", "/bin/true"})
+		assert.ErrorIs(t, cmd.Execute(), errNoSuchField)
+	})
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+)
+
+// maskedValue replaces secrets in output. Its length does not depend on the secret.
+const maskedValue = "********"
+
+// Fields that can be copied with get --copy.
+const (
+	fieldDefault    = "default"
+	fieldUsername   = "username"
+	fieldPassword   = "password"
+	fieldURL        = "url"
+	fieldOTP        = "otp"
+	fieldNumber     = "number"
+	fieldHolder     = "holder"
+	fieldExpiry     = "expiry"
+	fieldCVV        = "cvv"
+	fieldText       = "text"
+	fieldPrivateKey = "private_key"
+	fieldPublicKey  = "public_key"
+	fieldPassphrase = "passphrase"
+)
+
+// errNoSuchField is returned when the selected field is empty or not valid for the item type.
+var errNoSuchField = errors.New("field is not available for this item")
+
+// maskPayload returns the item data with secret fields masked.
+// Credential and card data that cannot be parsed is hidden completely.
+// Other item types are returned unchanged.
+func maskPayload(typ models.ItemType, data []byte) []byte {
+	var masked any
+	switch typ {
+	case models.ItemTypeCredential:
+		var p models.CredentialPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return []byte("<hidden, use --reveal>")
+		}
+		p.Password = maskString(p.Password)
+		p.OTP = maskString(p.OTP)
+		masked = p
+	case models.ItemTypeCard:
+		var p models.CardPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return []byte("<hidden, use --reveal>")
+		}
+		p.Number = maskCardNumber(p.Number)
+		p.CVV = maskString(p.CVV)
+		masked = p
+	case models.ItemTypeSSHKey:
+		var p models.SSHKeyPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return []byte("<hidden, use --reveal>")
+		}
+		p.PrivateKey = maskString(p.PrivateKey)
+		p.Passphrase = maskString(p.Passphrase)
+		masked = p
+	default:
+		return data
+	}
+
+	out, err := json.Marshal(masked)
+	if err != nil {
+		return []byte("<hidden, use --reveal>")
+	}
+	return out
+}
+
+// maskString masks a non-empty secret.
+func maskString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return maskedValue
+}
+
+// maskCardNumber keeps the last four digits of a card number.
+func maskCardNumber(number string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	if len(digits) < 8 {
+		return maskString(number)
+	}
+	return "**** " + digits[len(digits)-4:]
+}
+
+// selectField returns the value of a field of the item data.
+// The default field is the password of credentials, the number of cards, the private key of SSH keys
+// and the whole text of text items.
+// The otp field of credentials is returned as the stored secret.
+func selectField(typ models.ItemType, data []byte, field string) (string, error) {
+	var value string
+	switch typ {
+	case models.ItemTypeCredential:
+		var p models.CredentialPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return "", fmt.Errorf("failed to decode credential: %w", err)
+		}
+		switch field {
+		case fieldDefault, fieldPassword:
+			value = p.Password
+		case fieldUsername:
+			value = p.Username
+		case fieldURL:
+			value = p.URL
+		case fieldOTP:
+			value = p.OTP
+		default:
+			return "", fmt.Errorf("%w: %s", errNoSuchField, field)
+		}
+	case models.ItemTypeCard:
+		var p models.CardPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return "", fmt.Errorf("failed to decode card: %w", err)
+		}
+		switch field {
+		case fieldDefault, fieldNumber:
+			value = p.Number
+		case fieldHolder:
+			value = p.Holder
+		case fieldExpiry:
+			value = p.Expiry
+		case fieldCVV:
+			value = p.CVV
+		default:
+			return "", fmt.Errorf("%w: %s", errNoSuchField, field)
+		}
+	case models.ItemTypeSSHKey:
+		var p models.SSHKeyPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return "", fmt.Errorf("failed to decode SSH key: %w", err)
+		}
+		switch field {
+		case fieldDefault, fieldPrivateKey:
+			value = p.PrivateKey
+		case fieldPassphrase:
+			value = p.Passphrase
+		case fieldPublicKey:
+			pub, err := sshPublicKey(data)
+			if err != nil {
+				return "", err
+			}
+			value = pub
+		default:
+			return "", fmt.Errorf("%w: %s", errNoSuchField, field)
+		}
+	case models.ItemTypeText:
+		if field != fieldDefault && field != fieldText {
+			return "", fmt.Errorf("%w: %s", errNoSuchField, field)
+		}
+		value = string(data)
+	default:
+		return "", fmt.Errorf("%w: %s", errNoSuchField, field)
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("%w: %s is empty", errNoSuchField, field)
+	}
+	return value, nil
+}
+
+// fieldValue returns the value of a field of the item data, see selectField.
+// For the otp field of credentials the current one-time password is returned instead of the secret.
+func (a *App) fieldValue(item *models.Item, data []byte, field string) (string, error) {
+	value, err := selectField(item.Type, data, field)
+	if err != nil {
+		return "", err
+	}
+	if item.Type != models.ItemTypeCredential || field != fieldOTP {
+		return value, nil
+	}
+
+	var payload models.CredentialPayload
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("failed to decode credential: %w", err)
+	}
+	code, _, _, err := a.otpCode(item, payload)
+	return code, err
+}
+
+// copyField puts a field of the item on the clipboard and waits until it is cleared.
+// For the otp field the current one-time password is copied instead of the secret.
+// Interrupting the command clears the clipboard early.
+func (a *App) copyField(cmd *cobra.Command, item *models.Item, data []byte, field string, timeout time.Duration) error {
+	value, err := a.fieldValue(item, data, field)
+	if err != nil {
+		return err
+	}
+
+	if err = a.clipboard.Write(value); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+
+	name := field
+	if name == fieldDefault {
+		name = "secret"
+	}
+	if timeout > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Copied %s of %q to the clipboard (%s), clearing in %s\n", name, item.Title, a.clipboard.Name(), timeout)
+	} else {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Copied %s of %q to the clipboard (%s)\n", name, item.Title, a.clipboard.Name())
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err = clipboard.ClearAfter(ctx, a.clipboard, value, timeout); err != nil {
+		return fmt.Errorf("failed to clear clipboard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCredentialJSON = `{"username":"alice","password":"hunter2","url":"https://mail.example.com"}`
+	testCardJSON       = `{"number":"4111 1111 1111 1234","holder":"Alice","expiry":"12/30","cvv":"123"}`
+)
+
+func TestMaskPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      models.ItemType
+		data     string
+		expected string
+	}{
+		{
+			name:     "credential",
+			typ:      models.ItemTypeCredential,
+			data:     `{"username":"alice","password":"hunter2","otp":"JBSWY3DPEHPK3PXP"}`,
+			expected: `{"username":"alice","password":"********","otp":"********"}`,
+		},
+		{
+			name:     "card",
+			typ:      models.ItemTypeCard,
+			data:     testCardJSON,
+			expected: `{"number":"**** 1234","holder":"Alice","expiry":"12/30","cvv":"********"}`,
+		},
+		{
+			name:     "unparsable credential",
+			typ:      models.ItemTypeCredential,
+			data:     "login: alice / hunter2",
+			expected: "<hidden, use --reveal>",
+		},
+		{
+			name:     "text",
+			typ:      models.ItemTypeText,
+			data:     "plain note",
+			expected: "plain note",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(maskPayload(tt.typ, []byte(tt.data))))
+		})
+	}
+}
+
+func TestSelectField(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      models.ItemType
+		data     string
+		field    string
+		expected string
+		wantErr  bool
+	}{
+		{"credential default", models.ItemTypeCredential, testCredentialJSON, fieldDefault, "hunter2", false},
+		{"credential username", models.ItemTypeCredential, testCredentialJSON, fieldUsername, "alice", false},
+		{"credential url", models.ItemTypeCredential, testCredentialJSON, fieldURL, "https://mail.example.com", false},
+		{"credential empty otp", models.ItemTypeCredential, testCredentialJSON, fieldOTP, "", true},
+		{"credential card field", models.ItemTypeCredential, testCredentialJSON, fieldCVV, "", true},
+		{"card default", models.ItemTypeCard, testCardJSON, fieldDefault, "4111 1111 1111 1234", false},
+		{"card cvv", models.ItemTypeCard, testCardJSON, fieldCVV, "123", false},
+		{"text", models.ItemTypeText, "note", fieldText, "note", false},
+		{"text password", models.ItemTypeText, "note", fieldPassword, "", true},
+		{"binary", models.ItemTypeBinary, "data", fieldDefault, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := selectField(tt.typ, []byte(tt.data), tt.field)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errNoSuchField)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestCmdGet_MasksSecrets(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	data := base64.StdEncoding.EncodeToString([]byte(testCredentialJSON))
+	mockAPI.On("GetItem", item.ID).Return(item, &data, nil)
+
+	cmd := app.cmdGet()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--id", item.ID.String()})
+	require.NoError(t, cmd.Execute())
+	assert.NotContains(t, out.String(), "hunter2")
+	assert.Contains(t, out.String(), `"password":"********"`)
+
+	cmd = app.cmdGet()
+	out.Reset()
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--reveal"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "hunter2")
+}
+
+func TestCmdGet_Copy(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	fake := &clipboard.Fake{}
+	app.clipboard = fake
+	app.config.ClipboardTimeout = time.Millisecond
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Visa"}
+	data := base64.StdEncoding.EncodeToString([]byte(testCardJSON))
+	mockAPI.On("GetItem", item.ID).Return(item, &data, nil)
+
+	cmd := app.cmdGet()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--copy"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, []string{"4111 1111 1111 1234", ""}, fake.Writes())
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), `Copied secret of "Visa" to the clipboard (fake), clearing in 1ms`)
+}
+
+func TestCmdGet_CopyWithoutClearing(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	fake := &clipboard.Fake{}
+	app.clipboard = fake
+	app.config.ClipboardTimeout = time.Hour
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Visa"}
+	data := base64.StdEncoding.EncodeToString([]byte(testCardJSON))
+	mockAPI.On("GetItem", item.ID).Return(item, &data, nil)
+
+	cmd := app.cmdGet()
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--copy=cvv", "--timeout", "0"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, []string{"123"}, fake.Writes())
+}
+
+func TestCmdGet_CopyOTP(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+	fake := &clipboard.Fake{}
+	app.clipboard = fake
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	mockAPI.On("GetItem", item.ID).Return(item, otpCredential(t, rfcSecret), nil)
+
+	cmd := app.cmdGet()
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--copy=otp", "--timeout", "0"})
+
+	require.NoError(t, cmd.Execute())
+	writes := fake.Writes()
+	require.Len(t, writes, 1)
+	assert.Regexp(t, `^\d{6}$`, writes[0])
+	mockAPI.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything)
+}
+
+func TestCmdGet_CopyErrors(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	data := base64.StdEncoding.EncodeToString([]byte(testCredentialJSON))
+	mockAPI.On("GetItem", item.ID).Return(item, &data, nil)
+
+	cmd := app.cmdGet()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--copy=number"})
+	assert.ErrorIs(t, cmd.Execute(), errNoSuchField)
+
+	cmd = app.cmdGet()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", item.ID.String(), "--copy", "--out", "file"})
+	assert.Error(t, cmd.Execute())
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/sshagent"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// sshKeyData returns the JSON payload of an ssh-key item.
+// A bare private key, e.g. read from ~/.ssh/id_ed25519, is wrapped into the payload.
+// The key must be parseable, so broken keys are not stored.
+func sshKeyData(raw []byte) ([]byte, error) {
+	data := raw
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var err error
+		if data, err = json.Marshal(models.SSHKeyPayload{PrivateKey: string(raw)}); err != nil {
+			return nil, fmt.Errorf("failed to encode SSH key: %w", err)
+		}
+	}
+	if _, err := sshagent.ParseSigner(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// terminalConfirm returns a confirmation function asking on the terminal.
+// Questions are serialized, so concurrent signatures are confirmed one by one.
+func terminalConfirm(in io.Reader, out io.Writer) sshagent.ConfirmFunc {
+	var mu sync.Mutex
+	reader := bufio.NewReader(in)
+	return func(key sshagent.Key) bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprintf(out, "Allow signing with %q (%s)? [y/N] ", key.Title, key.Fingerprint)
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(out)
+			return false
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+func (a *App) cmdSSHAgent() *cobra.Command {
+	var socket string
+	var confirm bool
+	var lifetime time.Duration
+	cmd := &cobra.Command{
+		Use:   "ssh-agent",
+		Short: "Serve SSH keys stored in the vault over the SSH agent protocol",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lifetime < 0 {
+				return fmt.Errorf("%w: lifetime cannot be negative", errUsage)
+			}
+
+			if socket == "" {
+				dir, err := os.MkdirTemp("", "gophkeeper-ssh-")
+				if err != nil {
+					return fmt.Errorf("failed to create socket directory: %w", err)
+				}
+				defer os.RemoveAll(dir)
+				socket = filepath.Join(dir, "agent.sock")
+			}
+
+			listener, err := net.Listen("unix", socket)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", socket, err)
+			}
+			defer listener.Close()
+			if err = os.Chmod(socket, 0600); err != nil {
+				return fmt.Errorf("failed to restrict socket permissions: %w", err)
+			}
+
+			opts := sshagent.Options{Lifetime: lifetime}
+			if confirm {
+				opts.Confirm = terminalConfirm(cmd.InOrStdin(), cmd.ErrOrStderr())
+			}
+			keyring := sshagent.New(a.api, opts)
+
+			fmt.Fprintf(cmd.OutOrStdout(), "SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", socket)
+			fmt.Fprintln(cmd.ErrOrStderr(), "Agent is running, press Ctrl+C to stop")
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				listener.Close()
+			}()
+
+			return serveAgent(listener, keyring, func(err error) {
+				a.logger.Debug("SSH agent connection failed", zap.Error(err))
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&socket, "socket", "", "Path of the agent socket (a private temporary directory by default)")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Ask for confirmation on the terminal before every signature")
+	cmd.Flags().DurationVar(&lifetime, "lifetime", 0, "Stop offering a key this long after it was loaded (0 means no limit)")
+	return cmd
+}
+
+// serveAgent accepts connections until the listener is closed and serves the agent protocol on each.
+// Open connections are not waited for, ssh keeps them open for the whole session.
+func serveAgent(listener net.Listener, keyring agent.Agent, onError func(error)) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := agent.ServeAgent(keyring, conn); err != nil && !errors.Is(err, io.EOF) {
+				onError(err)
+			}
+		}()
+	}
+}
+
+// sshPublicKey returns the public key of an ssh-key item in authorized_keys format.
+func sshPublicKey(data []byte) (string, error) {
+	signer, err := sshagent.ParseSigner(data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/sshagent"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// testSSHKey returns a new ed25519 private key in OpenSSH PEM format and its public key.
+func testSSHKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(block)), sshPub
+}
+
+func TestSSHKeyData(t *testing.T) {
+	key, pub := testSSHKey(t)
+
+	data, err := sshKeyData([]byte(key))
+	require.NoError(t, err)
+	var payload models.SSHKeyPayload
+	require.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, key, payload.PrivateKey)
+
+	value, err := selectField(models.ItemTypeSSHKey, data, fieldPublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))), value)
+
+	masked := string(maskPayload(models.ItemTypeSSHKey, data))
+	assert.NotContains(t, masked, "PRIVATE KEY")
+
+	_, err = sshKeyData([]byte("not a key"))
+	assert.Error(t, err)
+	_, err = sshKeyData([]byte(`{"private_key":"garbage"}`))
+	assert.Error(t, err)
+}
+
+func TestCmdCreate_SSHKeyFromFile(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+
+	key, _ := testSSHKey(t)
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(path, []byte(key), 0600))
+
+	created := &models.Item{ID: uuid.New(), Type: models.ItemTypeSSHKey, Title: "Laptop"}
+	mockAPI.On("CreateItem", mock.MatchedBy(func(req *models.CreateItemRequest) bool {
+		raw, err := base64.StdEncoding.DecodeString(req.DataBase64)
+		if err != nil {
+			return false
+		}
+		var payload models.SSHKeyPayload
+		return req.Type == models.ItemTypeSSHKey && json.Unmarshal(raw, &payload) == nil && payload.PrivateKey == key
+	})).Return(created, nil)
+
+	cmd := app.cmdCreate()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--type", "ssh-key", "--title", "Laptop", "--file", path})
+	require.NoError(t, cmd.Execute())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdSSHAgent(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	key, pub := testSSHKey(t)
+	data, err := sshKeyData([]byte(key))
+	require.NoError(t, err)
+	item := &models.Item{ID: uuid.New(), Type: models.ItemTypeSSHKey, Title: "Laptop"}
+	mockAPI.On("ListItems").Return([]*models.Item{item}, nil)
+	mockAPI.On("GetItem", item.ID).Return(item, encodedData(string(data)), nil)
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd := app.cmdSSHAgent()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("y\nn\n"))
+	cmd.SetArgs([]string{"--socket", socket, "--confirm"})
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		conn, err = net.Dial("unix", socket)
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	defer conn.Close()
+	client := agent.NewClient(conn)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "Laptop", keys[0].Comment)
+
+	sig, err := client.Sign(pub, []byte("data"))
+	require.NoError(t, err)
+	assert.NoError(t, pub.Verify([]byte("data"), sig))
+	_, err = client.Sign(pub, []byte("data"))
+	assert.Error(t, err, "the second signature is refused on the terminal")
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Contains(t, out.String(), "SSH_AUTH_SOCK="+socket)
+}
+
+func TestTerminalConfirm(t *testing.T) {
+	confirm := terminalConfirm(strings.NewReader("yes\n\n"), &bytes.Buffer{})
+	key := sshagent.Key{Title: "Laptop"}
+	assert.True(t, confirm(key))
+	assert.False(t, confirm(key))
+	assert.False(t, confirm(key), "closed input refuses")
+}
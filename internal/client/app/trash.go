@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// purgeOutput is the output schema of the purge command.
+// Purged lists the IDs of purged items when they are known.
+type purgeOutput struct {
+	Purged []uuid.UUID `json:"purged"`
+	Count  int64       `json:"count"`
+}
+
+func (a *App) cmdTrash() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trash",
+		Short: "List deleted items",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := a.api.ListTrash()
+			if err != nil {
+				return fmt.Errorf("failed to list trash: %w", err)
+			}
+
+			res := newItemOutputs(items)
+			return a.render(cmd.OutOrStdout(), res, func(w io.Writer) {
+				for _, item := range res {
+					deletedAt := ""
+					if item.DeletedAt != nil {
+						deletedAt = item.DeletedAt.Local().Format("2006-01-02 15:04")
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.ID, item.Type, item.Title, deletedAt)
+				}
+			})
+		},
+	}
+}
+
+func (a *App) cmdRestore() *cobra.Command {
+	var rawID string
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a deleted item from the trash",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID(rawID)
+			if err != nil {
+				return fmt.Errorf("invalid ID format: %w", err)
+			}
+
+			item, err := a.api.RestoreItem(id)
+			if err != nil {
+				return fmt.Errorf("failed to restore item: %w", err)
+			}
+			a.cache.ItemsList()[item.ID.String()] = *item
+			return a.render(cmd.OutOrStdout(), newItemOutput(*item), func(w io.Writer) {
+				fmt.Fprintf(w, "Item restored: %s\n", item.ID)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&rawID, "id", "", "Item ID")
+	_ = cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func (a *App) cmdPurge() *cobra.Command {
+	var rawID string
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently remove deleted items",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (rawID == "") == !all {
+				return errors.New("exactly one of --id or --all is required")
+			}
+
+			if all {
+				n, err := a.api.EmptyTrash()
+				if err != nil {
+					return fmt.Errorf("failed to empty trash: %w", err)
+				}
+				return a.render(cmd.OutOrStdout(), purgeOutput{Purged: []uuid.UUID{}, Count: n}, func(w io.Writer) {
+					fmt.Fprintf(w, "Items purged: %d\n", n)
+				})
+			}
+
+			id, err := parseID(rawID)
+			if err != nil {
+				return fmt.Errorf("invalid ID format: %w", err)
+			}
+			if err = a.api.PurgeItem(id); err != nil {
+				return fmt.Errorf("failed to purge item: %w", err)
+			}
+			return a.render(cmd.OutOrStdout(), purgeOutput{Purged: []uuid.UUID{id}, Count: 1}, func(w io.Writer) {
+				fmt.Fprintf(w, "Item purged: %s\n", id)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&rawID, "id", "", "Item ID")
+	cmd.Flags().BoolVar(&all, "all", false, "Purge all items in the trash")
+	return cmd
+}
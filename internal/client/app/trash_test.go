@@ -0,0 +1,89 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdTrash(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("ListTrash").Return([]*models.Item{
+		{ID: uuid.New(), Type: models.ItemTypeText, Title: "Deleted"},
+	}, nil)
+
+	cmd := app.cmdTrash()
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdRestore(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	itemID := uuid.New()
+	cache := make(map[string]models.Item)
+
+	mockAPI.On("RestoreItem", itemID).Return(&models.Item{ID: itemID, Title: "Restored"}, nil)
+	mockCache.On("ItemsList").Return(cache)
+
+	cmd := app.cmdRestore()
+	cmd.SetArgs([]string{"--id", itemID.String()})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, cache, itemID.String())
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdPurge_ByID(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	itemID := uuid.New()
+	mockAPI.On("PurgeItem", itemID).Return(nil)
+
+	cmd := app.cmdPurge()
+	cmd.SetArgs([]string{"--id", itemID.String()})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdPurge_All(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	app := createTestAppWithMocks(mockAPI, mockCache)
+
+	mockAPI.On("EmptyTrash").Return(int64(2), nil)
+
+	cmd := app.cmdPurge()
+	cmd.SetArgs([]string{"--all"})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdPurge_RequiresTarget(t *testing.T) {
+	app := createTestAppWithMocks(new(MockApiService), new(MockCacheRepository))
+
+	cmd := app.cmdPurge()
+	cmd.SetArgs([]string{})
+	assert.Error(t, cmd.Execute())
+
+	cmd = app.cmdPurge()
+	cmd.SetArgs([]string{"--all", "--id", uuid.New().String()})
+	assert.Error(t, cmd.Execute())
+}
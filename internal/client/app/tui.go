@@ -0,0 +1,633 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func (a *App) cmdTUI() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse the vault in an interactive terminal UI",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := tea.NewProgram(newTUIModel(a), tea.WithAltScreen(), tea.WithContext(cmd.Context()))
+			if _, err := p.Run(); err != nil {
+				return fmt.Errorf("failed to run terminal UI: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// tuiMode is the screen the terminal UI is showing.
+type tuiMode int
+
+const (
+	tuiModeList tuiMode = iota
+	tuiModeSearch
+	tuiModeTypePicker
+	tuiModeForm
+	tuiModeConfirmDelete
+)
+
+// tuiAction is what happens after an item has been decrypted.
+type tuiAction int
+
+const (
+	tuiActionShow tuiAction = iota
+	tuiActionEdit
+	tuiActionCopy
+)
+
+// Messages produced by terminal UI commands.
+type (
+	tuiItemsMsg struct {
+		items []*models.Item
+		err   error
+	}
+	tuiFetchedMsg struct {
+		item   *models.Item
+		data   []byte
+		action tuiAction
+		field  string
+		err    error
+	}
+	tuiCopiedMsg struct {
+		label string
+		value string
+		err   error
+	}
+	tuiSavedMsg struct {
+		item    *models.Item
+		data    []byte
+		created bool
+		warning string
+		err     error
+	}
+	tuiDeletedMsg struct {
+		id  uuid.UUID
+		err error
+	}
+	tuiClearClipboardMsg struct {
+		value string
+	}
+)
+
+// tuiDetail is a decrypted item shown in the detail pane.
+type tuiDetail struct {
+	item   models.Item
+	data   []byte
+	reveal bool
+}
+
+var (
+	tuiTitleStyle    = lipgloss.NewStyle().Bold(true)
+	tuiSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	tuiDimStyle      = lipgloss.NewStyle().Faint(true)
+	tuiPaneStyle     = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).Padding(0, 1)
+)
+
+// tuiModel is the bubbletea model of the terminal UI.
+type tuiModel struct {
+	app      *App
+	mode     tuiMode
+	items    []models.Item
+	visible  []int
+	cursor   int
+	search   textinput.Model
+	detail   *tuiDetail
+	form     *tuiForm
+	status   string
+	offline  bool
+	loading  bool
+	copied   string
+	width    int
+	height   int
+	quitting bool
+}
+
+func newTUIModel(a *App) *tuiModel {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search"
+	return &tuiModel{app: a, search: search, loading: true}
+}
+
+// Init loads the item list.
+func (m *tuiModel) Init() tea.Cmd {
+	return m.loadItems()
+}
+
+func (m *tuiModel) loadItems() tea.Cmd {
+	api := m.app.api
+	return func() tea.Msg {
+		items, err := api.ListItems()
+		return tuiItemsMsg{items: items, err: err}
+	}
+}
+
+// fetch decrypts an item on demand and runs the action afterwards.
+func (m *tuiModel) fetch(id uuid.UUID, action tuiAction, field string) tea.Cmd {
+	api := m.app.api
+	return func() tea.Msg {
+		item, data, err := api.GetItem(id)
+		if err != nil {
+			return tuiFetchedMsg{err: err}
+		}
+		if item == nil {
+			return tuiFetchedMsg{err: errors.New("item not found")}
+		}
+		msg := tuiFetchedMsg{item: item, action: action, field: field}
+		if data != nil && *data != "" {
+			if msg.data, err = base64.StdEncoding.DecodeString(*data); err != nil {
+				return tuiFetchedMsg{err: fmt.Errorf("failed to decode base64 data: %w", err)}
+			}
+		}
+		return msg
+	}
+}
+
+// copy puts a field of the decrypted item on the clipboard.
+func (m *tuiModel) copy(d *tuiDetail, field string) tea.Cmd {
+	a := m.app
+	item, data := d.item, d.data
+	return func() tea.Msg {
+		value, err := a.fieldValue(&item, data, field)
+		if err != nil {
+			return tuiCopiedMsg{err: err}
+		}
+		label := field
+		switch field {
+		case fieldDefault:
+			label = "secret"
+		case fieldOTP:
+			label = "OTP code"
+		}
+		if err = a.clipboard.Write(value); err != nil {
+			return tuiCopiedMsg{err: fmt.Errorf("failed to copy to clipboard: %w", err)}
+		}
+		return tuiCopiedMsg{label: label, value: value}
+	}
+}
+
+func (m *tuiModel) deleteItem(id uuid.UUID) tea.Cmd {
+	api := m.app.api
+	return func() tea.Msg {
+		return tuiDeletedMsg{id: id, err: api.DeleteItem(id)}
+	}
+}
+
+// Update handles messages and key presses.
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tuiItemsMsg:
+		m.loading = false
+		m.setItems(msg.items, msg.err)
+		return m, nil
+	case tuiFetchedMsg:
+		return m, m.handleFetched(msg)
+	case tuiCopiedMsg:
+		if msg.err != nil {
+			m.status = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		m.copied = msg.value
+		timeout := m.app.config.ClipboardTimeout
+		if timeout <= 0 {
+			m.status = fmt.Sprintf("Copied %s to the clipboard", msg.label)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Copied %s to the clipboard, clearing in %s", msg.label, timeout)
+		return m, tea.Tick(timeout, func(time.Time) tea.Msg { return tuiClearClipboardMsg{value: msg.value} })
+	case tuiClearClipboardMsg:
+		if m.copied == msg.value {
+			m.clearClipboard()
+			m.status = "Clipboard cleared"
+		}
+		return m, nil
+	case tuiSavedMsg:
+		return m, m.handleSaved(msg)
+	case tuiDeletedMsg:
+		if msg.err != nil {
+			m.status = "Error: failed to delete item: " + msg.err.Error()
+			return m, nil
+		}
+		m.removeItem(msg.id)
+		m.status = "Item moved to the trash"
+		return m, nil
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+
+	if m.mode == tuiModeForm {
+		return m, m.form.update(msg)
+	}
+	return m, nil
+}
+
+// setItems replaces the item list, falling back to the cache if loading failed.
+func (m *tuiModel) setItems(items []*models.Item, err error) {
+	m.items = m.items[:0]
+	m.offline = err != nil
+	if err != nil {
+		m.app.logger.Warn("Failed to get items from server, using cache", zap.Error(err))
+		for _, item := range m.app.cache.ItemsList() {
+			m.items = append(m.items, item)
+		}
+		m.status = "Offline: showing cached items, data cannot be decrypted"
+	} else {
+		for _, item := range items {
+			if item != nil {
+				m.items = append(m.items, *item)
+			}
+		}
+		m.status = ""
+	}
+	sort.Slice(m.items, func(i, j int) bool {
+		return m.items[i].UpdatedAt.After(m.items[j].UpdatedAt)
+	})
+	m.applyFilter()
+}
+
+// applyFilter recomputes the visible items from the search query.
+// Titles, types, folders and URLs are matched case-insensitively.
+func (m *tuiModel) applyFilter() {
+	query := strings.ToLower(strings.TrimSpace(m.search.Value()))
+	m.visible = m.visible[:0]
+	for i, item := range m.items {
+		meta := models.ParseItemMetadata(item.Metadata)
+		haystack := strings.ToLower(strings.Join([]string{item.Title, string(item.Type), meta.Folder, meta.URL}, " "))
+		if query == "" || strings.Contains(haystack, query) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = max(len(m.visible)-1, 0)
+	}
+	m.dropDetailIfHidden()
+}
+
+// selected returns the highlighted item or nil if the list is empty.
+func (m *tuiModel) selected() *models.Item {
+	if len(m.visible) == 0 {
+		return nil
+	}
+	return &m.items[m.visible[m.cursor]]
+}
+
+// dropDetailIfHidden forgets decrypted data that no longer belongs to the highlighted item.
+func (m *tuiModel) dropDetailIfHidden() {
+	if m.detail == nil {
+		return
+	}
+	if sel := m.selected(); sel == nil || sel.ID != m.detail.item.ID {
+		m.detail = nil
+	}
+}
+
+func (m *tuiModel) handleFetched(msg tuiFetchedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.status = "Error: failed to get item: " + msg.err.Error()
+		return nil
+	}
+	sel := m.selected()
+	if sel == nil || sel.ID != msg.item.ID {
+		return nil
+	}
+
+	m.detail = &tuiDetail{item: *msg.item, data: msg.data}
+	m.status = ""
+	switch msg.action {
+	case tuiActionEdit:
+		return m.openForm(msg.item.Type, m.detail)
+	case tuiActionCopy:
+		return m.copy(m.detail, msg.field)
+	default:
+		return nil
+	}
+}
+
+// withDetail runs an action on the decrypted highlighted item, decrypting it first if necessary.
+func (m *tuiModel) withDetail(action tuiAction, field string) tea.Cmd {
+	sel := m.selected()
+	if sel == nil {
+		return nil
+	}
+	if m.offline {
+		m.status = "Offline: data cannot be decrypted"
+		return nil
+	}
+	if m.detail == nil {
+		m.status = "Decrypting..."
+		return m.fetch(sel.ID, action, field)
+	}
+	switch action {
+	case tuiActionEdit:
+		return m.openForm(m.detail.item.Type, m.detail)
+	case tuiActionCopy:
+		return m.copy(m.detail, field)
+	default:
+		return nil
+	}
+}
+
+func (m *tuiModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() == "ctrl+c" {
+		return m.quit()
+	}
+
+	switch m.mode {
+	case tuiModeSearch:
+		switch msg.String() {
+		case "enter":
+			m.mode = tuiModeList
+			m.search.Blur()
+			return nil
+		case "esc":
+			m.mode = tuiModeList
+			m.search.Blur()
+			m.search.SetValue("")
+			m.applyFilter()
+			return nil
+		}
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		m.applyFilter()
+		return cmd
+	case tuiModeTypePicker:
+		types := map[string]models.ItemType{"1": models.ItemTypeCredential, "2": models.ItemTypeCard, "3": models.ItemTypeText}
+		if typ, ok := types[msg.String()]; ok {
+			return m.openForm(typ, nil)
+		}
+		if msg.String() == "esc" {
+			m.mode = tuiModeList
+		}
+		return nil
+	case tuiModeForm:
+		switch msg.String() {
+		case "esc":
+			m.mode = tuiModeList
+			m.form = nil
+			m.status = "Cancelled"
+			return nil
+		case "ctrl+s":
+			return m.submitForm()
+		case "enter":
+			if m.form.focus == len(m.form.inputs)-1 {
+				return m.submitForm()
+			}
+		}
+		return m.form.handleKey(msg)
+	case tuiModeConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			m.mode = tuiModeList
+			if sel := m.selected(); sel != nil {
+				return m.deleteItem(sel.ID)
+			}
+		case "n", "N", "esc":
+			m.mode = tuiModeList
+			m.status = "Cancelled"
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "q":
+		return m.quit()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.dropDetailIfHidden()
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+			m.dropDetailIfHidden()
+		}
+	case "/":
+		m.mode = tuiModeSearch
+		return m.search.Focus()
+	case "esc":
+		m.detail = nil
+	case "ctrl+r":
+		m.loading = true
+		m.detail = nil
+		return m.loadItems()
+	case "enter":
+		return m.withDetail(tuiActionShow, "")
+	case "r":
+		if m.detail != nil {
+			m.detail.reveal = !m.detail.reveal
+		}
+	case "c":
+		return m.withDetail(tuiActionCopy, fieldDefault)
+	case "u":
+		return m.withDetail(tuiActionCopy, fieldUsername)
+	case "o":
+		return m.withDetail(tuiActionCopy, fieldOTP)
+	case "n":
+		if m.offline {
+			m.status = "Offline: items cannot be created"
+			return nil
+		}
+		m.mode = tuiModeTypePicker
+	case "e":
+		return m.withDetail(tuiActionEdit, "")
+	case "d":
+		if m.selected() != nil && !m.offline {
+			m.mode = tuiModeConfirmDelete
+		}
+	}
+	return nil
+}
+
+// clearClipboard removes a copied secret from the clipboard unless it was replaced.
+func (m *tuiModel) clearClipboard() {
+	if m.copied == "" {
+		return
+	}
+	if err := clipboard.ClearIfUnchanged(m.app.clipboard, m.copied); err != nil {
+		m.app.logger.Warn("Failed to clear clipboard", zap.Error(err))
+	}
+	m.copied = ""
+}
+
+func (m *tuiModel) quit() tea.Cmd {
+	m.clearClipboard()
+	m.quitting = true
+	return tea.Quit
+}
+
+func (m *tuiModel) removeItem(id uuid.UUID) {
+	for i, item := range m.items {
+		if item.ID == id {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			break
+		}
+	}
+	delete(m.app.cache.ItemsList(), id.String())
+	m.detail = nil
+	m.applyFilter()
+}
+
+// View renders the current screen.
+func (m *tuiModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	header := fmt.Sprintf("GophKeeper — %d items", len(m.items))
+	if m.offline {
+		header += " (offline)"
+	}
+	b.WriteString(tuiTitleStyle.Render(header) + "\n")
+	if m.mode == tuiModeSearch || m.search.Value() != "" {
+		b.WriteString(m.search.View() + "\n")
+	}
+	b.WriteString("\n")
+
+	switch m.mode {
+	case tuiModeTypePicker:
+		b.WriteString("New item type:\n  1  credential\n  2  card\n  3  text\n")
+	case tuiModeForm:
+		b.WriteString(m.form.view())
+	default:
+		list, detail := m.listView(), m.detailView()
+		if m.width >= 80 {
+			listWidth := m.width * 2 / 5
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+				tuiPaneStyle.Width(listWidth).Render(list),
+				tuiPaneStyle.Width(m.width-listWidth-4).Render(detail)))
+		} else {
+			b.WriteString(list + "\n\n" + detail)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.mode == tuiModeConfirmDelete {
+		if sel := m.selected(); sel != nil {
+			b.WriteString(fmt.Sprintf("\nMove %q to the trash? (y/n)\n", sel.Title))
+		}
+	}
+	if m.status != "" {
+		b.WriteString("\n" + m.status + "\n")
+	}
+	b.WriteString("\n" + tuiDimStyle.Render(m.help()))
+	return b.String()
+}
+
+func (m *tuiModel) help() string {
+	switch m.mode {
+	case tuiModeSearch:
+		return "type to filter • enter: done • esc: clear"
+	case tuiModeTypePicker:
+		return "1-3: choose type • esc: cancel"
+	case tuiModeForm:
+		return "tab/shift+tab: move • ctrl+g: generate password • ctrl+s: save • esc: cancel"
+	case tuiModeConfirmDelete:
+		return "y: delete • n: cancel"
+	default:
+		return "↑/↓: move • /: search • enter: decrypt • r: reveal • c: copy secret • u: copy username • o: copy OTP • n: new • e: edit • d: delete • ctrl+r: reload • q: quit"
+	}
+}
+
+func (m *tuiModel) listView() string {
+	if m.loading {
+		return "Loading..."
+	}
+	if len(m.visible) == 0 {
+		return "No items"
+	}
+
+	var lines []string
+	for n, idx := range m.visible {
+		item := m.items[idx]
+		line := fmt.Sprintf("%-10s %s", item.Type, item.Title)
+		if n == m.cursor {
+			line = tuiSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *tuiModel) detailView() string {
+	sel := m.selected()
+	if sel == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(tuiTitleStyle.Render(sel.Title) + "\n")
+	fmt.Fprintf(&b, "ID:      %s\n", sel.ID)
+	fmt.Fprintf(&b, "Type:    %s\n", sel.Type)
+	meta := models.ParseItemMetadata(sel.Metadata)
+	if meta.Folder != "" {
+		fmt.Fprintf(&b, "Folder:  %s\n", meta.Folder)
+	}
+	if meta.URL != "" {
+		fmt.Fprintf(&b, "URL:     %s\n", meta.URL)
+	}
+	if meta.Notes != "" {
+		fmt.Fprintf(&b, "Notes:   %s\n", meta.Notes)
+	}
+	fmt.Fprintf(&b, "Updated: %s\n", sel.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if sel.ExpiresAt != nil {
+		fmt.Fprintf(&b, "Expires: %s\n", sel.ExpiresAt.Local().Format(time.DateOnly))
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.detail == nil:
+		b.WriteString(tuiDimStyle.Render("Press enter to decrypt"))
+	case len(m.detail.data) == 0:
+		b.WriteString("No data")
+	case m.detail.item.Type == models.ItemTypeBinary:
+		fmt.Fprintf(&b, "Binary data, %d bytes", len(m.detail.data))
+	default:
+		data := m.detail.data
+		if !m.detail.reveal {
+			data = maskPayload(m.detail.item.Type, data)
+		}
+		b.WriteString(formatPayload(data))
+	}
+	return b.String()
+}
+
+// formatPayload renders a JSON object as "key: value" lines and other data as is.
+func formatPayload(data []byte) string {
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return string(data)
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %s", k, fields[k]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,347 @@
+package app
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/passgen"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Form fields that are stored in the item rather than in its data.
+const (
+	formFieldTitle  = "title"
+	formFieldFolder = "folder"
+	formFieldNotes  = "notes"
+)
+
+// tuiFormField describes an input of the item form.
+type tuiFormField struct {
+	key    string
+	label  string
+	secret bool
+}
+
+// tuiFormFields lists the form inputs for every item type that can be edited in the terminal UI.
+var tuiFormFields = map[models.ItemType][]tuiFormField{
+	models.ItemTypeCredential: {
+		{formFieldTitle, "Title", false},
+		{formFieldFolder, "Folder", false},
+		{fieldUsername, "Username", false},
+		{fieldPassword, "Password", true},
+		{fieldURL, "URL", false},
+		{fieldOTP, "OTP secret", true},
+		{formFieldNotes, "Notes", false},
+	},
+	models.ItemTypeCard: {
+		{formFieldTitle, "Title", false},
+		{formFieldFolder, "Folder", false},
+		{fieldNumber, "Number", true},
+		{fieldHolder, "Holder", false},
+		{fieldExpiry, "Expiry (MM/YY)", false},
+		{fieldCVV, "CVV", true},
+		{formFieldNotes, "Notes", false},
+	},
+	models.ItemTypeText: {
+		{formFieldTitle, "Title", false},
+		{formFieldFolder, "Folder", false},
+		{fieldText, "Text", false},
+	},
+	models.ItemTypeBinary: {
+		{formFieldTitle, "Title", false},
+		{formFieldFolder, "Folder", false},
+	},
+}
+
+// tuiForm creates or edits an item.
+type tuiForm struct {
+	typ    models.ItemType
+	fields []tuiFormField
+	inputs []textinput.Model
+	focus  int
+	// orig is the item being edited, nil when a new item is created.
+	orig *tuiDetail
+}
+
+// newTUIForm builds a form for the item type, filled from the decrypted item when editing.
+func newTUIForm(typ models.ItemType, orig *tuiDetail) (*tuiForm, error) {
+	fields, ok := tuiFormFields[typ]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type: %s", typ)
+	}
+
+	values := map[string]string{}
+	if orig != nil {
+		var err error
+		if values, err = formValues(orig); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &tuiForm{typ: typ, fields: fields, orig: orig}
+	for _, field := range fields {
+		in := textinput.New()
+		in.Prompt = ""
+		in.CharLimit = 0
+		in.SetValue(values[field.key])
+		if field.secret {
+			in.EchoMode = textinput.EchoPassword
+			in.EchoCharacter = '*'
+		}
+		f.inputs = append(f.inputs, in)
+	}
+	f.inputs[0].Focus()
+	return f, nil
+}
+
+// formValues returns the form values of a decrypted item keyed by field.
+func formValues(d *tuiDetail) (map[string]string, error) {
+	meta := models.ParseItemMetadata(d.item.Metadata)
+	values := map[string]string{
+		formFieldTitle:  d.item.Title,
+		formFieldFolder: meta.Folder,
+	}
+
+	switch d.item.Type {
+	case models.ItemTypeCredential:
+		var p models.CredentialPayload
+		if len(d.data) > 0 {
+			if err := json.Unmarshal(d.data, &p); err != nil {
+				return nil, fmt.Errorf("failed to decode credential: %w", err)
+			}
+		}
+		values[fieldUsername], values[fieldPassword], values[fieldURL] = p.Username, p.Password, p.URL
+		values[fieldOTP], values[formFieldNotes] = p.OTP, p.Notes
+	case models.ItemTypeCard:
+		var p models.CardPayload
+		if len(d.data) > 0 {
+			if err := json.Unmarshal(d.data, &p); err != nil {
+				return nil, fmt.Errorf("failed to decode card: %w", err)
+			}
+		}
+		values[fieldNumber], values[fieldHolder], values[fieldExpiry] = p.Number, p.Holder, p.Expiry
+		values[fieldCVV], values[formFieldNotes] = p.CVV, p.Notes
+	case models.ItemTypeText:
+		values[fieldText] = string(d.data)
+	}
+	return values, nil
+}
+
+// value returns the trimmed value of a field.
+func (f *tuiForm) value(key string) string {
+	for i, field := range f.fields {
+		if field.key == key {
+			return strings.TrimSpace(f.inputs[i].Value())
+		}
+	}
+	return ""
+}
+
+// payload encodes the item data from the form.
+// Fields of the original payload that the form does not show, like the card brand, are kept.
+// Binary items return nil because their data is not edited in the form.
+func (f *tuiForm) payload() ([]byte, error) {
+	var orig []byte
+	if f.orig != nil {
+		orig = f.orig.data
+	}
+
+	switch f.typ {
+	case models.ItemTypeCredential:
+		var p models.CredentialPayload
+		if len(orig) > 0 {
+			_ = json.Unmarshal(orig, &p)
+		}
+		p.Username, p.Password, p.URL = f.value(fieldUsername), f.value(fieldPassword), f.value(fieldURL)
+		p.OTP, p.Notes = f.value(fieldOTP), f.value(formFieldNotes)
+		return json.Marshal(p)
+	case models.ItemTypeCard:
+		var p models.CardPayload
+		if len(orig) > 0 {
+			_ = json.Unmarshal(orig, &p)
+		}
+		p.Number, p.Holder, p.Expiry = f.value(fieldNumber), f.value(fieldHolder), f.value(fieldExpiry)
+		p.CVV, p.Notes = f.value(fieldCVV), f.value(formFieldNotes)
+		return json.Marshal(p)
+	case models.ItemTypeText:
+		return []byte(f.value(fieldText)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// metadata returns the item metadata with the folder from the form.
+func (f *tuiForm) metadata() string {
+	var meta models.ItemMetadata
+	if f.orig != nil {
+		meta = models.ParseItemMetadata(f.orig.item.Metadata)
+	}
+	meta.Folder = f.value(formFieldFolder)
+	return meta.String()
+}
+
+func (f *tuiForm) setFocus(i int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	return f.inputs[f.focus].Focus()
+}
+
+// handleKey moves between the inputs, generates passwords and passes other keys to the focused input.
+func (f *tuiForm) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "tab", "down", "enter":
+		return f.setFocus(f.focus + 1)
+	case "shift+tab", "up":
+		return f.setFocus(f.focus - 1)
+	case "ctrl+g":
+		if f.fields[f.focus].key == fieldPassword {
+			if password, err := passgen.Password(passgen.DefaultOptions()); err == nil {
+				f.inputs[f.focus].SetValue(password)
+			}
+		}
+		return nil
+	}
+	return f.update(msg)
+}
+
+// update passes a message to the focused input.
+func (f *tuiForm) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return cmd
+}
+
+func (f *tuiForm) view() string {
+	var b strings.Builder
+	if f.orig == nil {
+		fmt.Fprintf(&b, "New %s\n\n", f.typ)
+	} else {
+		fmt.Fprintf(&b, "Edit %q\n\n", f.orig.item.Title)
+	}
+	for i, field := range f.fields {
+		cursor := "  "
+		if i == f.focus {
+			cursor = "> "
+		}
+		fmt.Fprintf(&b, "%s%-15s %s\n", cursor, field.label+":", f.inputs[i].View())
+	}
+	return b.String()
+}
+
+// openForm switches to the item form, filled from the decrypted item when editing.
+func (m *tuiModel) openForm(typ models.ItemType, orig *tuiDetail) tea.Cmd {
+	form, err := newTUIForm(typ, orig)
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		m.mode = tuiModeList
+		return nil
+	}
+	m.form = form
+	m.mode = tuiModeForm
+	m.status = ""
+	return textinput.Blink
+}
+
+// submitForm saves the form in the background.
+func (m *tuiModel) submitForm() tea.Cmd {
+	f := m.form
+	title := f.value(formFieldTitle)
+	if title == "" {
+		m.status = "Error: title is required"
+		return nil
+	}
+	data, err := f.payload()
+	if err != nil {
+		m.status = "Error: failed to encode item: " + err.Error()
+		return nil
+	}
+	meta := f.metadata()
+
+	a := m.app
+	m.status = "Saving..."
+	return func() tea.Msg {
+		var warning bytes.Buffer
+		if f.typ == models.ItemTypeCredential {
+			a.warnPassword(&warning, data, title)
+		}
+
+		if f.orig == nil {
+			item, err := a.api.CreateItem(&models.CreateItemRequest{
+				Type:       f.typ,
+				Title:      title,
+				Metadata:   meta,
+				DataBase64: base64.StdEncoding.EncodeToString(data),
+			})
+			if err == nil && item == nil {
+				err = errors.New("empty response")
+			}
+			return tuiSavedMsg{item: item, data: data, created: true, warning: firstLine(warning.String()), err: err}
+		}
+
+		req := &models.UpdateItemRequest{Title: &title, Metadata: &meta}
+		if data != nil {
+			dataBase64 := base64.StdEncoding.EncodeToString(data)
+			req.DataBase64 = &dataBase64
+		} else {
+			data = f.orig.data
+		}
+		item, err := a.api.UpdateItem(f.orig.item.ID, req)
+		if err == nil && item == nil {
+			err = errors.New("empty response")
+		}
+		return tuiSavedMsg{item: item, data: data, warning: firstLine(warning.String()), err: err}
+	}
+}
+
+// handleSaved stores the saved item and shows it in the list.
+func (m *tuiModel) handleSaved(msg tuiSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.status = "Error: failed to save item: " + msg.err.Error()
+		return nil
+	}
+
+	m.app.cache.ItemsList()[msg.item.ID.String()] = *msg.item
+	m.mode = tuiModeList
+	m.form = nil
+
+	replaced := false
+	for i := range m.items {
+		if m.items[i].ID == msg.item.ID {
+			m.items[i] = *msg.item
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		m.items = append([]models.Item{*msg.item}, m.items...)
+	}
+	m.search.SetValue("")
+	m.applyFilter()
+	for n, idx := range m.visible {
+		if m.items[idx].ID == msg.item.ID {
+			m.cursor = n
+		}
+	}
+	m.detail = &tuiDetail{item: *msg.item, data: msg.data}
+
+	m.status = "Item updated"
+	if msg.created {
+		m.status = "Item created"
+	}
+	if msg.warning != "" {
+		m.status += ". " + msg.warning
+	}
+	return nil
+}
+
+// firstLine returns the first line of s without the trailing newline.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
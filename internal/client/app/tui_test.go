@@ -0,0 +1,296 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/client/clipboard"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// key returns the key message for a key name or typed text.
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "ctrl+g":
+		return tea.KeyMsg{Type: tea.KeyCtrlG}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+}
+
+// press sends keys to the model and runs the terminal UI commands they return.
+func press(t *testing.T, m *tuiModel, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		_, cmd := m.Update(key(k))
+		drain(m, cmd)
+	}
+}
+
+// drain runs a command and feeds the resulting terminal UI messages back to the model.
+// Commands of bubbles components, like cursor blinking, are abandoned when they do not finish quickly.
+func drain(m *tuiModel, cmd tea.Cmd) {
+	for cmd != nil {
+		done := make(chan tea.Msg, 1)
+		go func(cmd tea.Cmd) { done <- cmd() }(cmd)
+
+		var msg tea.Msg
+		select {
+		case msg = <-done:
+		case <-time.After(20 * time.Millisecond):
+			return
+		}
+		switch msg := msg.(type) {
+		case tuiItemsMsg, tuiFetchedMsg, tuiCopiedMsg, tuiSavedMsg, tuiDeletedMsg, tuiClearClipboardMsg:
+			_, cmd = m.Update(msg)
+		case tea.BatchMsg:
+			for _, c := range msg {
+				drain(m, c)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
+
+// newTestTUI returns a terminal UI with the items loaded.
+func newTestTUI(t *testing.T, mockAPI *MockApiService, mockCache *MockCacheRepository, items ...*models.Item) (*tuiModel, *clipboard.Fake) {
+	t.Helper()
+	app := createTestAppWithMocks(mockAPI, mockCache)
+	fake := &clipboard.Fake{}
+	app.clipboard = fake
+	app.config.ClipboardTimeout = 0
+
+	mockAPI.On("ListItems").Return(items, nil).Once()
+	m := newTUIModel(app)
+	drain(m, m.Init())
+	return m, fake
+}
+
+func encodedData(s string) *string {
+	data := base64.StdEncoding.EncodeToString([]byte(s))
+	return &data
+}
+
+func TestTUI_ListAndSearch(t *testing.T) {
+	now := time.Now()
+	mail := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail", UpdatedAt: now}
+	visa := &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Visa", UpdatedAt: now.Add(-time.Hour)}
+	bank := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Online banking",
+		Metadata: `{"folder":"finance"}`, UpdatedAt: now.Add(-2 * time.Hour)}
+	m, _ := newTestTUI(t, new(MockApiService), new(MockCacheRepository), visa, bank, mail)
+
+	require.Len(t, m.visible, 3)
+	assert.Equal(t, "Mail", m.selected().Title, "most recently updated item first")
+	assert.Contains(t, m.View(), "Press enter to decrypt")
+
+	press(t, m, "/", "f", "i", "n")
+	assert.Equal(t, tuiModeSearch, m.mode)
+	require.Len(t, m.visible, 1)
+	assert.Equal(t, "Online banking", m.selected().Title)
+
+	press(t, m, "enter")
+	assert.Equal(t, tuiModeList, m.mode)
+	assert.Len(t, m.visible, 1)
+
+	press(t, m, "/", "esc")
+	assert.Len(t, m.visible, 3)
+}
+
+func TestTUI_OfflineUsesCache(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	item := models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Cached note"}
+	mockCache.On("ItemsList").Return(map[string]models.Item{item.ID.String(): item})
+
+	app := createTestAppWithMocks(mockAPI, mockCache)
+	mockAPI.On("ListItems").Return(nil, errors.New("connection refused"))
+	m := newTUIModel(app)
+	drain(m, m.Init())
+
+	assert.True(t, m.offline)
+	require.Len(t, m.visible, 1)
+	assert.Contains(t, m.View(), "(offline)")
+
+	press(t, m, "enter", "n", "d")
+	assert.Nil(t, m.detail)
+	assert.Equal(t, tuiModeList, m.mode)
+	mockAPI.AssertNotCalled(t, "GetItem", mock.Anything)
+}
+
+func TestTUI_DetailMasksSecrets(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mail := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	note := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	m, _ := newTestTUI(t, mockAPI, new(MockCacheRepository), mail, note)
+	mockAPI.On("GetItem", mail.ID).Return(mail, encodedData(testCredentialJSON), nil)
+
+	press(t, m, "enter")
+	require.NotNil(t, m.detail)
+	view := m.View()
+	assert.Contains(t, view, "username: alice")
+	assert.Contains(t, view, "password: ********")
+	assert.NotContains(t, view, "hunter2")
+
+	press(t, m, "r")
+	assert.Contains(t, m.View(), "password: hunter2")
+
+	press(t, m, "j")
+	assert.Nil(t, m.detail, "decrypted data is dropped when the selection moves")
+	assert.NotContains(t, m.View(), "hunter2")
+}
+
+func TestTUI_Copy(t *testing.T) {
+	mockAPI := new(MockApiService)
+	visa := &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Visa"}
+	m, fake := newTestTUI(t, mockAPI, new(MockCacheRepository), visa)
+	m.app.config.ClipboardTimeout = time.Millisecond
+	mockAPI.On("GetItem", visa.ID).Return(visa, encodedData(testCardJSON), nil).Once()
+
+	press(t, m, "c")
+	assert.Equal(t, []string{"4111 1111 1111 1234", ""}, fake.Writes())
+	assert.Equal(t, "Clipboard cleared", m.status)
+
+	press(t, m, "u")
+	assert.Contains(t, m.status, "not available")
+	mockAPI.AssertExpectations(t)
+}
+
+func TestTUI_QuitClearsClipboard(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mail := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Mail"}
+	m, fake := newTestTUI(t, mockAPI, new(MockCacheRepository), mail)
+	mockAPI.On("GetItem", mail.ID).Return(mail, encodedData(testCredentialJSON), nil)
+
+	press(t, m, "u")
+	assert.Equal(t, []string{"alice"}, fake.Writes())
+
+	_, cmd := m.Update(key("q"))
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+	assert.Equal(t, []string{"alice", ""}, fake.Writes())
+}
+
+func TestTUI_CreateCredential(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	cache := map[string]models.Item{}
+	mockCache.On("ItemsList").Return(cache)
+	m, _ := newTestTUI(t, mockAPI, mockCache)
+
+	created := &models.Item{ID: uuid.New(), Type: models.ItemTypeCredential, Title: "Forum"}
+	var req *models.CreateItemRequest
+	mockAPI.On("CreateItem", mock.Anything).Run(func(args mock.Arguments) {
+		req = args.Get(0).(*models.CreateItemRequest)
+	}).Return(created, nil)
+
+	press(t, m, "n", "1")
+	require.Equal(t, tuiModeForm, m.mode)
+	press(t, m, "F", "o", "r", "u", "m", "tab", "w", "e", "b", "tab", "b", "o", "b", "tab", "ctrl+g", "ctrl+s")
+
+	require.NotNil(t, req)
+	assert.Equal(t, models.ItemTypeCredential, req.Type)
+	assert.Equal(t, "Forum", req.Title)
+	assert.Equal(t, `{"folder":"web"}`, req.Metadata)
+
+	raw, err := base64.StdEncoding.DecodeString(req.DataBase64)
+	require.NoError(t, err)
+	var payload models.CredentialPayload
+	require.NoError(t, json.Unmarshal(raw, &payload))
+	assert.Equal(t, "bob", payload.Username)
+	assert.Len(t, payload.Password, 20)
+
+	assert.Equal(t, tuiModeList, m.mode)
+	assert.Equal(t, "Item created", m.status)
+	assert.Equal(t, "Forum", m.selected().Title)
+	assert.Contains(t, cache, created.ID.String())
+}
+
+func TestTUI_CreateRequiresTitle(t *testing.T) {
+	mockAPI := new(MockApiService)
+	m, _ := newTestTUI(t, mockAPI, new(MockCacheRepository))
+
+	press(t, m, "n", "3", "ctrl+s")
+	assert.Equal(t, tuiModeForm, m.mode)
+	assert.Equal(t, "Error: title is required", m.status)
+
+	press(t, m, "esc")
+	assert.Equal(t, tuiModeList, m.mode)
+	mockAPI.AssertNotCalled(t, "CreateItem", mock.Anything)
+}
+
+func TestTUI_EditCard(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	mockCache.On("ItemsList").Return(map[string]models.Item{})
+	visa := &models.Item{ID: uuid.New(), Type: models.ItemTypeCard, Title: "Visa", Metadata: `{"folder":"bank","source":"bitwarden"}`}
+	m, _ := newTestTUI(t, mockAPI, mockCache, visa)
+
+	data := `{"number":"4111 1111 1111 1234","holder":"Alice","expiry":"12/30","cvv":"123","brand":"Visa"}`
+	mockAPI.On("GetItem", visa.ID).Return(visa, encodedData(data), nil)
+	var req *models.UpdateItemRequest
+	mockAPI.On("UpdateItem", visa.ID, mock.Anything).Run(func(args mock.Arguments) {
+		req = args.Get(1).(*models.UpdateItemRequest)
+	}).Return(visa, nil)
+
+	press(t, m, "e")
+	require.Equal(t, tuiModeForm, m.mode)
+	assert.Equal(t, "Visa", m.form.value(formFieldTitle))
+	assert.Equal(t, "123", m.form.value(fieldCVV))
+	assert.NotContains(t, m.View(), "4111", "secret inputs are masked")
+
+	m.form.setFocus(3)
+	m.form.inputs[3].SetValue("Alice Smith")
+	press(t, m, "ctrl+s")
+
+	require.NotNil(t, req)
+	assert.Equal(t, "Visa", *req.Title)
+	assert.Equal(t, `{"folder":"bank","source":"bitwarden"}`, *req.Metadata)
+	raw, err := base64.StdEncoding.DecodeString(*req.DataBase64)
+	require.NoError(t, err)
+	var payload models.CardPayload
+	require.NoError(t, json.Unmarshal(raw, &payload))
+	assert.Equal(t, "Alice Smith", payload.Holder)
+	assert.Equal(t, "Visa", payload.Brand, "fields not shown in the form are kept")
+	assert.Equal(t, "Item updated", m.status)
+}
+
+func TestTUI_Delete(t *testing.T) {
+	mockAPI := new(MockApiService)
+	mockCache := new(MockCacheRepository)
+	note := &models.Item{ID: uuid.New(), Type: models.ItemTypeText, Title: "Note"}
+	cache := map[string]models.Item{note.ID.String(): *note}
+	mockCache.On("ItemsList").Return(cache)
+	m, _ := newTestTUI(t, mockAPI, mockCache, note)
+	mockAPI.On("DeleteItem", note.ID).Return(nil)
+
+	press(t, m, "d")
+	assert.Contains(t, m.View(), `Move "Note" to the trash? (y/n)`)
+	press(t, m, "n")
+	mockAPI.AssertNotCalled(t, "DeleteItem", mock.Anything)
+
+	press(t, m, "d", "y")
+	mockAPI.AssertCalled(t, "DeleteItem", note.ID)
+	assert.Empty(t, m.visible)
+	assert.Empty(t, cache)
+	assert.Equal(t, "Item moved to the trash", m.status)
+}
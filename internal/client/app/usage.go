@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/spf13/cobra"
+)
+
+func (a *App) cmdUsage() *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage",
+		Short: "Show storage usage and quota",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := a.api.GetUsage()
+			if err != nil {
+				return fmt.Errorf("failed to get usage: %w", err)
+			}
+
+			return a.render(cmd.OutOrStdout(), report, func(w io.Writer) {
+				fmt.Fprintf(w, "Items:\t%d / %s\n", report.Usage.Items, formatLimit(report.Quota.MaxItems, formatCount))
+				fmt.Fprintf(w, "Storage:\t%s / %s\n", formatBytes(report.Usage.Bytes), formatLimit(report.Quota.MaxBytes, formatBytes))
+				printQuotaWarning(w, report)
+			})
+		},
+	}
+}
+
+// usageWarnRatio is the share of a quota above which the usage command prints a warning.
+const usageWarnRatio = 0.9
+
+// printQuotaWarning warns when the usage is close to the quota.
+func printQuotaWarning(w io.Writer, report *models.UsageReport) {
+	near := func(used, limit int64) bool {
+		return limit > 0 && float64(used) >= usageWarnRatio*float64(limit)
+	}
+	if near(report.Usage.Items, report.Quota.MaxItems) || near(report.Usage.Bytes, report.Quota.MaxBytes) {
+		fmt.Fprintln(w, "Warning: storage is almost full, delete items and empty the trash to free space")
+	}
+}
+
+// formatLimit formats a quota limit, zero limits are unlimited.
+func formatLimit(limit int64, format func(int64) string) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return format(limit)
+}
+
+// formatCount formats a number of items.
+func formatCount(n int64) string {
+	return fmt.Sprintf("%d", n)
+}
+
+// formatBytes formats a size in bytes with a binary unit.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdUsage(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("GetUsage").Return(&models.UsageReport{
+		Usage: models.Usage{Items: 3, Bytes: 1536},
+		Quota: models.Quota{MaxBytes: 256 << 20},
+	}, nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdUsage()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Items:\t3 / unlimited")
+	assert.Contains(t, out.String(), "Storage:\t1.5 KiB / 256.0 MiB")
+	assert.NotContains(t, out.String(), "Warning")
+	mockAPI.AssertExpectations(t)
+}
+
+func TestCmdUsage_NearQuota(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("GetUsage").Return(&models.UsageReport{
+		Usage: models.Usage{Items: 95},
+		Quota: models.Quota{MaxItems: 100},
+	}, nil)
+
+	var out bytes.Buffer
+	cmd := app.cmdUsage()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Warning")
+}
+
+func TestCmdUsage_Error(t *testing.T) {
+	mockAPI := new(MockApiService)
+	app := createTestAppWithMocks(mockAPI, new(MockCacheRepository))
+
+	mockAPI.On("GetUsage").Return(nil, errors.New("server down"))
+
+	cmd := app.cmdUsage()
+	cmd.SetArgs([]string{})
+
+	assert.Error(t, cmd.Execute())
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{256 << 20, "256.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatBytes(tt.in))
+	}
+}
@@ -0,0 +1,266 @@
+// Package clipboard provides access to the system clipboard for the GophKeeper client.
+//
+// Several backends are supported: wl-copy/wl-paste on Wayland, xclip or xsel on X11,
+// pbcopy/pbpaste on macOS and the OSC 52 terminal escape sequence, which works over
+// SSH in most modern terminals. A Fake backend is provided for tests.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend names accepted by New.
+const (
+	// BackendAuto selects the first available backend.
+	BackendAuto = "auto"
+	// BackendWayland uses wl-copy and wl-paste.
+	BackendWayland = "wl-copy"
+	// BackendXclip uses xclip.
+	BackendXclip = "xclip"
+	// BackendXsel uses xsel.
+	BackendXsel = "xsel"
+	// BackendPbcopy uses pbcopy and pbpaste.
+	BackendPbcopy = "pbcopy"
+	// BackendOSC52 writes the OSC 52 escape sequence to the terminal.
+	BackendOSC52 = "osc52"
+)
+
+var (
+	// ErrUnknownBackend is returned by New for an unsupported backend name.
+	ErrUnknownBackend = errors.New("unknown clipboard backend")
+
+	// ErrReadUnsupported is returned by backends that cannot read the clipboard.
+	ErrReadUnsupported = errors.New("clipboard backend cannot read the clipboard")
+)
+
+// Clipboard reads and writes the system clipboard.
+type Clipboard interface {
+	// Name returns the backend name.
+	Name() string
+	// Write replaces the clipboard content.
+	Write(text string) error
+	// Read returns the clipboard content or ErrReadUnsupported.
+	Read() (string, error)
+}
+
+// lookPath is replaced in tests.
+var lookPath = exec.LookPath
+
+// New returns the clipboard backend with the given name.
+// For BackendAuto the first available command-line tool is used, falling back to OSC 52.
+// OSC 52 sequences are written to term.
+func New(backend string, term io.Writer) (Clipboard, error) {
+	switch backend {
+	case BackendAuto, "":
+		return detect(term), nil
+	case BackendWayland:
+		return commandClipboard{name: BackendWayland, copyCmd: []string{"wl-copy"}, pasteCmd: []string{"wl-paste", "--no-newline"}}, nil
+	case BackendXclip:
+		return commandClipboard{name: BackendXclip, copyCmd: []string{"xclip", "-selection", "clipboard"}, pasteCmd: []string{"xclip", "-selection", "clipboard", "-o"}}, nil
+	case BackendXsel:
+		return commandClipboard{name: BackendXsel, copyCmd: []string{"xsel", "--clipboard", "--input"}, pasteCmd: []string{"xsel", "--clipboard", "--output"}}, nil
+	case BackendPbcopy:
+		return commandClipboard{name: BackendPbcopy, copyCmd: []string{"pbcopy"}, pasteCmd: []string{"pbpaste"}}, nil
+	case BackendOSC52:
+		return &OSC52{w: term}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
+
+// detect picks a backend based on the session type and the installed tools.
+func detect(term io.Writer) Clipboard {
+	var candidates []string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append(candidates, BackendWayland)
+	}
+	if os.Getenv("DISPLAY") != "" {
+		candidates = append(candidates, BackendXclip, BackendXsel)
+	}
+	candidates = append(candidates, BackendPbcopy)
+
+	for _, name := range candidates {
+		cb, _ := New(name, term)
+		if cmd, ok := cb.(commandClipboard); ok {
+			if _, err := lookPath(cmd.copyCmd[0]); err == nil {
+				return cmd
+			}
+		}
+	}
+	return &OSC52{w: term}
+}
+
+// CopyAndClear writes text to the clipboard and clears it after timeout or when ctx is done.
+// The clipboard is left alone if its content changed in the meantime.
+// A zero timeout copies without clearing.
+func CopyAndClear(ctx context.Context, cb Clipboard, text string, timeout time.Duration) error {
+	if err := cb.Write(text); err != nil {
+		return err
+	}
+	return ClearAfter(ctx, cb, text, timeout)
+}
+
+// ClearAfter clears text from the clipboard after timeout or when ctx is done.
+// The clipboard is left alone if its content changed in the meantime.
+// A zero timeout returns immediately.
+func ClearAfter(ctx context.Context, cb Clipboard, text string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return ClearIfUnchanged(cb, text)
+}
+
+// ClearIfUnchanged clears the clipboard unless it holds something other than text.
+// Backends that cannot read the clipboard are always cleared.
+func ClearIfUnchanged(cb Clipboard, text string) error {
+	if current, err := cb.Read(); err == nil && current != text {
+		return nil
+	}
+	return cb.Write("")
+}
+
+// commandClipboard runs external tools to copy and paste.
+type commandClipboard struct {
+	name     string
+	copyCmd  []string
+	pasteCmd []string
+}
+
+// Name returns the backend name.
+func (c commandClipboard) Name() string {
+	return c.name
+}
+
+// xclip and wl-copy fork a child that serves the selection and inherits stderr,
+// so stderr stays open after the copy command exits.
+const (
+	// maxStderr is the number of bytes of the copy command stderr kept for the error message.
+	maxStderr = 4096
+	// stderrWaitDelay is how long Write waits for stderr to close after the copy command exits.
+	stderrWaitDelay = 100 * time.Millisecond
+)
+
+// Write pipes text into the copy command.
+// Stdout is discarded and stderr is kept only for the error message, Write does not wait
+// for the forked child that keeps stderr open until the selection is taken.
+func (c commandClipboard) Write(text string) error {
+	stderr := &limitedBuffer{max: maxStderr}
+	cmd := exec.Command(c.copyCmd[0], c.copyCmd[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stderr = stderr
+	cmd.WaitDelay = stderrWaitDelay
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		return fmt.Errorf("failed to run %s: %w: %s", c.copyCmd[0], err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first max bytes written to it and drops the rest.
+type limitedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	max int
+}
+
+// Write stores as much of p as fits and always reports p as written.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.max - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// Bytes returns the stored bytes.
+func (b *limitedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+// Read returns the output of the paste command.
+func (c commandClipboard) Read() (string, error) {
+	out, err := exec.Command(c.pasteCmd[0], c.pasteCmd[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", c.pasteCmd[0], err)
+	}
+	return string(out), nil
+}
+
+// OSC52 sets the clipboard of the terminal emulator with an escape sequence.
+// The terminal does not report the clipboard back, so Read is unsupported.
+type OSC52 struct {
+	w io.Writer
+}
+
+// Name returns the backend name.
+func (c *OSC52) Name() string {
+	return BackendOSC52
+}
+
+// Write sends the text to the terminal clipboard. An empty text clears it.
+func (c *OSC52) Write(text string) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if _, err := io.WriteString(c.w, seq); err != nil {
+		return fmt.Errorf("failed to write OSC 52 sequence: %w", err)
+	}
+	return nil
+}
+
+// Read always returns ErrReadUnsupported.
+func (c *OSC52) Read() (string, error) {
+	return "", ErrReadUnsupported
+}
+
+// Fake is an in-memory clipboard for tests.
+type Fake struct {
+	mu     sync.Mutex
+	text   string
+	writes []string
+}
+
+// Name returns the backend name.
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+// Write stores the text.
+func (f *Fake) Write(text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.text = text
+	f.writes = append(f.writes, text)
+	return nil
+}
+
+// Read returns the stored text.
+func (f *Fake) Read() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.text, nil
+}
+
+// Writes returns every text written so far in order.
+func (f *Fake) Writes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.writes...)
+}
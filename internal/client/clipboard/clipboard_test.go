@@ -0,0 +1,157 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		backend string
+		name    string
+	}{
+		{BackendWayland, "wl-copy"},
+		{BackendXclip, "xclip"},
+		{BackendXsel, "xsel"},
+		{BackendPbcopy, "pbcopy"},
+		{BackendOSC52, "osc52"},
+	}
+	for _, tt := range tests {
+		cb, err := New(tt.backend, &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.name, cb.Name())
+	}
+
+	_, err := New("clippy", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownBackend)
+}
+
+func TestNew_Auto(t *testing.T) {
+	orig := lookPath
+	defer func() { lookPath = orig }()
+
+	t.Run("prefers wayland", func(t *testing.T) {
+		t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+		t.Setenv("DISPLAY", ":0")
+		lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+		cb, err := New(BackendAuto, &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, BackendWayland, cb.Name())
+	})
+
+	t.Run("uses xsel when xclip is missing", func(t *testing.T) {
+		t.Setenv("WAYLAND_DISPLAY", "")
+		t.Setenv("DISPLAY", ":0")
+		lookPath = func(file string) (string, error) {
+			if file == "xsel" {
+				return "/usr/bin/xsel", nil
+			}
+			return "", errors.New("not found")
+		}
+
+		cb, err := New(BackendAuto, &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, BackendXsel, cb.Name())
+	})
+
+	t.Run("falls back to osc52", func(t *testing.T) {
+		t.Setenv("WAYLAND_DISPLAY", "")
+		t.Setenv("DISPLAY", "")
+		lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+
+		cb, err := New(BackendAuto, &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, BackendOSC52, cb.Name())
+	})
+}
+
+func TestOSC52(t *testing.T) {
+	var term bytes.Buffer
+	cb, err := New(BackendOSC52, &term)
+	require.NoError(t, err)
+
+	require.NoError(t, cb.Write("secret"))
+	assert.Equal(t, "\x1b]52;c;c2VjcmV0\a", term.String())
+
+	_, err = cb.Read()
+	assert.ErrorIs(t, err, ErrReadUnsupported)
+}
+
+func TestCommandClipboard_Write(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	t.Run("does not wait for forked child", func(t *testing.T) {
+		// Like xclip, the command exits and leaves a child holding stdout and stderr.
+		cb := commandClipboard{name: "test", copyCmd: []string{"sh", "-c", "cat >/dev/null; sleep 5 &"}}
+
+		start := time.Now()
+		require.NoError(t, cb.Write("secret"))
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("reports stderr on failure", func(t *testing.T) {
+		cb := commandClipboard{name: "test", copyCmd: []string{"sh", "-c", "echo no display >&2; exit 1"}}
+
+		err := cb.Write("secret")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no display")
+	})
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 4}
+	n, err := b.Write([]byte("abcdef"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, "abcd", string(b.Bytes()))
+}
+
+func TestCopyAndClear(t *testing.T) {
+	t.Run("clears after timeout", func(t *testing.T) {
+		cb := &Fake{}
+		require.NoError(t, CopyAndClear(context.Background(), cb, "secret", time.Millisecond))
+		assert.Equal(t, []string{"secret", ""}, cb.Writes())
+	})
+
+	t.Run("clears when cancelled", func(t *testing.T) {
+		cb := &Fake{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NoError(t, CopyAndClear(ctx, cb, "secret", time.Hour))
+		assert.Equal(t, []string{"secret", ""}, cb.Writes())
+	})
+
+	t.Run("keeps content copied by someone else", func(t *testing.T) {
+		cb := &Fake{}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			for {
+				if text, _ := cb.Read(); text == "secret" {
+					_ = cb.Write("other")
+					cancel()
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+		require.NoError(t, CopyAndClear(ctx, cb, "secret", time.Hour))
+		text, _ := cb.Read()
+		assert.Equal(t, "other", text)
+	})
+
+	t.Run("zero timeout does not clear", func(t *testing.T) {
+		cb := &Fake{}
+		require.NoError(t, CopyAndClear(context.Background(), cb, "secret", 0))
+		assert.Equal(t, []string{"secret"}, cb.Writes())
+	})
+}
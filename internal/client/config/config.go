@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -25,10 +26,19 @@ type Config struct {
 	CachePath string
 	// TokenPath is the path to the authentication token file.
 	TokenPath string
+	// BreachCorpusPath is the path to a sorted file of breached SHA-1 password hashes.
+	// Breach checks are skipped when empty.
+	BreachCorpusPath string
+	// ClipboardBackend selects the clipboard backend (auto, wl-copy, xclip, xsel, pbcopy, osc52).
+	ClipboardBackend string
+	// ClipboardTimeout is how long copied secrets stay on the clipboard. Zero disables clearing.
+	ClipboardTimeout time.Duration
 	// BuildVersion contains the version of the application.
 	BuildVersion string
 	// BuildDate contains the build timestamp.
 	BuildDate string
+	// Args holds the command line arguments left after the flags above, i.e. the command and its arguments.
+	Args []string
 }
 
 // Load reads configuration from environment variables and command-line flags.
@@ -53,8 +63,12 @@ func Load() (*Config, error) {
 	flag.BoolVar(&cfg.TLSInsecure, "v", getBoolEnv("TLS_INSECURE", false), "Disable TLS certificate verification")
 	flag.StringVar(&cfg.CachePath, "c", getEnv("CACHE_PATH", defaultCache), "Path to the local cache file")
 	flag.StringVar(&cfg.TokenPath, "t", getEnv("TOKEN_PATH", defaultToken), "Path to the token file")
+	flag.StringVar(&cfg.BreachCorpusPath, "b", getEnv("BREACH_CORPUS", ""), "Path to the breached password hashes file")
+	flag.StringVar(&cfg.ClipboardBackend, "clipboard", getEnv("CLIPBOARD", "auto"), "Clipboard backend (auto, wl-copy, xclip, xsel, pbcopy, osc52)")
+	flag.DurationVar(&cfg.ClipboardTimeout, "clipboard-timeout", getEnvDuration("CLIPBOARD_TIMEOUT", 45*time.Second), "How long copied secrets stay on the clipboard")
 
 	flag.Parse()
+	cfg.Args = flag.Args()
 
 	return cfg, nil
 }
@@ -76,3 +90,14 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvDuration retrieves a duration from an environment variable or returns a default value.
+// The environment variable should contain a valid duration string (e.g., "45s", "2m").
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -66,6 +67,34 @@ func TestGetBoolEnv(t *testing.T) {
 	}
 }
 
+func TestGetEnvDuration_Client(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue time.Duration
+		envValue     string
+		setEnv       bool
+		expected     time.Duration
+	}{
+		{"Valid duration", "TEST_DURATION_CLIENT", 45 * time.Second, "2m", true, 2 * time.Minute},
+		{"Zero", "TEST_DURATION_CLIENT", 45 * time.Second, "0s", true, 0},
+		{"Invalid duration", "TEST_DURATION_CLIENT", 45 * time.Second, "soon", true, 45 * time.Second},
+		{"Not set", "TEST_DURATION_CLIENT_NOTSET", 45 * time.Second, "", false, 45 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				os.Setenv(tt.key, tt.envValue)
+				defer os.Unsetenv(tt.key)
+			}
+
+			result := getEnvDuration(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestClientConfig_Struct(t *testing.T) {
 	cfg := &Config{
 		ServerAddr:   "https://server.com",
@@ -0,0 +1,184 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// ErrEncryptedExport is returned when a password-protected export is provided.
+var ErrEncryptedExport = errors.New("encrypted exports are not supported, export unencrypted data")
+
+// Bitwarden item types as used in the JSON export.
+const (
+	bitwardenTypeLogin      = 1
+	bitwardenTypeSecureNote = 2
+	bitwardenTypeCard       = 3
+	bitwardenTypeIdentity   = 4
+)
+
+// bitwardenExport mirrors the parts of the Bitwarden JSON export used by the importer.
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+// bitwardenItem is a single Bitwarden vault item.
+type bitwardenItem struct {
+	FolderID *string `json:"folderId"`
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    string  `json:"notes"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]any `json:"identity"`
+}
+
+// parseBitwardenJSON parses an unencrypted Bitwarden JSON export.
+// Logins become credentials, cards become card items, and secure notes and identities become text items.
+func parseBitwardenJSON(r io.Reader) (*Result, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to decode Bitwarden JSON: %w", err)
+	}
+	if export.Encrypted {
+		return nil, ErrEncryptedExport
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, f := range export.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	res := &Result{}
+	for _, item := range export.Items {
+		title := normalizeTitle(item.Name)
+		meta := models.ItemMetadata{Source: string(FormatBitwardenJSON)}
+		if item.FolderID != nil {
+			meta.Folder = folders[*item.FolderID]
+		}
+
+		custom := make([]string, 0, len(item.Fields))
+		for _, f := range item.Fields {
+			custom = append(custom, f.Name+": "+f.Value)
+		}
+		notes := joinNotes(item.Notes, strings.Join(custom, "\n"))
+
+		var err error
+		switch {
+		case item.Type == bitwardenTypeLogin && item.Login != nil:
+			payload := models.CredentialPayload{
+				Username: item.Login.Username,
+				Password: item.Login.Password,
+				Notes:    notes,
+				OTP:      item.Login.TOTP,
+			}
+			if len(item.Login.URIs) > 0 {
+				payload.URL = item.Login.URIs[0].URI
+				meta.URL = payload.URL
+			}
+			err = res.addJSON(models.ItemTypeCredential, title, meta, payload)
+		case item.Type == bitwardenTypeCard && item.Card != nil:
+			err = res.addJSON(models.ItemTypeCard, title, meta, models.CardPayload{
+				Number: item.Card.Number,
+				Holder: item.Card.CardholderName,
+				Expiry: formatExpiry(item.Card.ExpMonth, item.Card.ExpYear),
+				CVV:    item.Card.Code,
+				Brand:  item.Card.Brand,
+				Notes:  notes,
+			})
+		case item.Type == bitwardenTypeSecureNote:
+			res.add(models.ItemTypeText, title, meta, []byte(notes))
+		case item.Type == bitwardenTypeIdentity && item.Identity != nil:
+			res.add(models.ItemTypeText, title, meta, []byte(joinNotes(formatIdentity(item.Identity), notes)))
+		default:
+			res.skip(title, fmt.Sprintf("unsupported item type %d", item.Type))
+		}
+		if err != nil {
+			res.skip(title, err.Error())
+		}
+	}
+	return res, nil
+}
+
+// bitwardenIdentityFields lists identity fields in display order.
+var bitwardenIdentityFields = []string{
+	"title", "firstName", "middleName", "lastName", "company", "email", "phone",
+	"address1", "address2", "address3", "city", "state", "postalCode", "country",
+	"ssn", "passportNumber", "licenseNumber", "username",
+}
+
+// formatIdentity renders the non-empty fields of a Bitwarden identity as "name: value" lines.
+func formatIdentity(identity map[string]any) string {
+	var lines []string
+	for _, key := range bitwardenIdentityFields {
+		if v, ok := identity[key].(string); ok && v != "" {
+			lines = append(lines, key+": "+v)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseBitwardenCSV parses a Bitwarden CSV export.
+// The CSV format only contains logins and secure notes.
+func parseBitwardenCSV(r io.Reader) (*Result, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Bitwarden CSV: %w", err)
+	}
+
+	res := &Result{}
+	for _, row := range rows {
+		title := normalizeTitle(row.get("name"))
+		meta := models.ItemMetadata{
+			Folder: row.get("folder"),
+			URL:    row.get("login_uri"),
+			Source: string(FormatBitwardenCSV),
+		}
+		notes := joinNotes(row.get("notes"), row.get("fields"))
+
+		switch row.get("type") {
+		case "login":
+			if err = res.addJSON(models.ItemTypeCredential, title, meta, models.CredentialPayload{
+				Username: row.get("login_username"),
+				Password: row.get("login_password"),
+				URL:      row.get("login_uri"),
+				Notes:    notes,
+				OTP:      row.get("login_totp"),
+			}); err != nil {
+				res.skip(title, err.Error())
+			}
+		case "note":
+			res.add(models.ItemTypeText, title, meta, []byte(notes))
+		default:
+			res.skip(title, "unsupported item type "+row.get("type"))
+		}
+	}
+	return res, nil
+}
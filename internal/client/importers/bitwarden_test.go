@@ -0,0 +1,113 @@
+package importers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodePayload(t *testing.T, req *models.CreateItemRequest, v any) {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(req.DataBase64)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, v))
+}
+
+func TestParseBitwardenJSON(t *testing.T) {
+	export := `{
+		"encrypted": false,
+		"folders": [{"id": "f1", "name": "Personal"}],
+		"items": [
+			{"type": 1, "name": "Mail", "folderId": "f1", "notes": null,
+			 "login": {"username": "bob", "password": "pw", "uris": [{"uri": "https://mail.example.com"}]}},
+			{"type": 2, "name": "Wifi", "notes": "guest/guest", "secureNote": {"type": 0}},
+			{"type": 3, "name": "Visa", "card": {"cardholderName": "Bob", "brand": "Visa",
+			 "number": "4111111111111111", "expMonth": "3", "expYear": "2028", "code": "123"}},
+			{"type": 4, "name": "Me", "identity": {"firstName": "Bob", "email": "bob@example.com"}},
+			{"type": 9, "name": "Unknown"}
+		]
+	}`
+
+	res, err := Parse(FormatBitwardenJSON, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 4)
+	require.Len(t, res.Skipped, 1)
+
+	assert.Equal(t, models.ItemTypeCredential, res.Items[0].Type)
+	meta := models.ParseItemMetadata(res.Items[0].Metadata)
+	assert.Equal(t, "Personal", meta.Folder)
+	assert.Equal(t, "https://mail.example.com", meta.URL)
+
+	assert.Equal(t, models.ItemTypeText, res.Items[1].Type)
+
+	assert.Equal(t, models.ItemTypeCard, res.Items[2].Type)
+	var card models.CardPayload
+	decodePayload(t, res.Items[2], &card)
+	assert.Equal(t, "03/28", card.Expiry)
+	assert.Equal(t, "123", card.CVV)
+
+	assert.Equal(t, models.ItemTypeText, res.Items[3].Type)
+	assert.Equal(t, map[models.ItemType]int{
+		models.ItemTypeCredential: 1,
+		models.ItemTypeText:       2,
+		models.ItemTypeCard:       1,
+	}, res.CountByType())
+}
+
+func TestParseBitwardenJSON_Encrypted(t *testing.T) {
+	_, err := Parse(FormatBitwardenJSON, strings.NewReader(`{"encrypted": true, "items": []}`))
+	assert.ErrorIs(t, err, ErrEncryptedExport)
+}
+
+func TestParseBitwardenCSV(t *testing.T) {
+	export := "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n" +
+		"Work,,login,VPN,,,0,https://vpn.example.com,carol,hunter2,JBSWY3DPEHPK3PXP\n" +
+		",,note,Recovery codes,\"one\ntwo\",,0,,,,\n" +
+		",,card,Ignored,,,0,,,,\n"
+
+	res, err := Parse(FormatBitwardenCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+	require.Len(t, res.Skipped, 1)
+
+	var cred models.CredentialPayload
+	decodePayload(t, res.Items[0], &cred)
+	assert.Equal(t, "carol", cred.Username)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", cred.OTP)
+	assert.Equal(t, "hunter2", cred.Password)
+	assert.Equal(t, "Work", models.ParseItemMetadata(res.Items[0].Metadata).Folder)
+
+	assert.Equal(t, models.ItemTypeText, res.Items[1].Type)
+}
+
+func TestParseBitwardenJSON_Titles(t *testing.T) {
+	long := strings.Repeat("x", maxTitleLength+1)
+	export := `{"items": [
+		{"type": 2, "name": "", "notes": "no name"},
+		{"type": 2, "name": "` + long + `", "notes": "long name"}
+	]}`
+
+	res, err := Parse(FormatBitwardenJSON, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, untitled, res.Items[0].Title)
+	assert.Equal(t, long[:maxTitleLength], res.Items[1].Title)
+}
+
+func TestParseBitwardenCSV_Titles(t *testing.T) {
+	long := strings.Repeat("x", maxTitleLength+1)
+	export := "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n" +
+		",,note,,no name,,0,,,,\n" +
+		",," + "note," + long + ",long name,,0,,,,\n"
+
+	res, err := Parse(FormatBitwardenCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, untitled, res.Items[0].Title)
+	assert.Equal(t, long[:maxTitleLength], res.Items[1].Title)
+}
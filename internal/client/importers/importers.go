@@ -0,0 +1,154 @@
+// Package importers provides parsers for exports of other password managers.
+//
+// Each parser maps the exported entries onto models.CreateItemRequest values so they
+// can be reviewed in a dry run and uploaded to the GophKeeper server.
+package importers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// Format identifies a supported export format.
+type Format string
+
+const (
+	// FormatKeePassXML is the unencrypted XML export of KeePass 2.x.
+	FormatKeePassXML Format = "keepass-xml"
+	// FormatBitwardenJSON is the unencrypted JSON export of Bitwarden.
+	FormatBitwardenJSON Format = "bitwarden-json"
+	// FormatBitwardenCSV is the CSV export of Bitwarden.
+	FormatBitwardenCSV Format = "bitwarden-csv"
+	// Format1PasswordCSV is the CSV export of 1Password.
+	Format1PasswordCSV Format = "1password-csv"
+)
+
+const (
+	// maxTitleLength is the maximum number of characters in an item title accepted by the server.
+	maxTitleLength = 255
+	// untitled is the title of entries exported without one.
+	untitled = "Untitled"
+)
+
+// Formats lists all supported export formats.
+var Formats = []Format{FormatKeePassXML, FormatBitwardenJSON, FormatBitwardenCSV, Format1PasswordCSV}
+
+// ErrUnsupportedFormat is returned when an unknown export format is requested.
+var ErrUnsupportedFormat = errors.New("unsupported import format")
+
+// Skipped describes an exported entry that could not be imported.
+type Skipped struct {
+	// Title is the title of the skipped entry.
+	Title string
+	// Reason explains why the entry was skipped.
+	Reason string
+}
+
+// Result holds the items parsed from an export.
+type Result struct {
+	// Items are the create requests ready for upload.
+	Items []*models.CreateItemRequest
+	// Skipped are the entries that were not mapped onto items.
+	Skipped []Skipped
+}
+
+// CountByType returns the number of parsed items per item type.
+func (r *Result) CountByType() map[models.ItemType]int {
+	counts := make(map[models.ItemType]int)
+	for _, item := range r.Items {
+		counts[item.Type]++
+	}
+	return counts
+}
+
+// Parse reads an export in the specified format and maps its entries onto create requests.
+// Returns ErrUnsupportedFormat if the format is unknown.
+func Parse(format Format, r io.Reader) (*Result, error) {
+	switch format {
+	case FormatKeePassXML:
+		return parseKeePassXML(r)
+	case FormatBitwardenJSON:
+		return parseBitwardenJSON(r)
+	case FormatBitwardenCSV:
+		return parseBitwardenCSV(r)
+	case Format1PasswordCSV:
+		return parse1PasswordCSV(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// add appends a create request to the result.
+func (r *Result) add(typ models.ItemType, title string, meta models.ItemMetadata, data []byte) {
+	req := &models.CreateItemRequest{
+		Type:     typ,
+		Title:    title,
+		Metadata: meta.String(),
+	}
+	if len(data) > 0 {
+		req.DataBase64 = base64.StdEncoding.EncodeToString(data)
+	}
+	r.Items = append(r.Items, req)
+}
+
+// addJSON marshals a typed payload and appends it as a create request.
+func (r *Result) addJSON(typ models.ItemType, title string, meta models.ItemMetadata, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", typ, err)
+	}
+	r.add(typ, title, meta, data)
+	return nil
+}
+
+// skip records an entry that was not imported.
+func (r *Result) skip(title, reason string) {
+	r.Skipped = append(r.Skipped, Skipped{Title: title, Reason: reason})
+}
+
+// normalizeTitle returns the title of an exported entry as accepted by the server:
+// blank titles become "Untitled" and long titles are truncated to maxTitleLength characters.
+func normalizeTitle(title string) string {
+	if strings.TrimSpace(title) == "" {
+		return untitled
+	}
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength])
+}
+
+// formatExpiry converts a month and a two- or four-digit year into MM/YY format.
+// Returns an empty string if either part is missing.
+func formatExpiry(month, year string) string {
+	month = strings.TrimSpace(month)
+	year = strings.TrimSpace(year)
+	if month == "" || year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) > 2 {
+		year = year[len(year)-2:]
+	}
+	return month + "/" + year
+}
+
+// joinNotes joins non-empty note fragments with newlines.
+func joinNotes(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
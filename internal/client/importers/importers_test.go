@@ -0,0 +1,31 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	_, err := Parse(Format("lastpass"), strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestFormatExpiry(t *testing.T) {
+	assert.Equal(t, "01/27", formatExpiry("1", "2027"))
+	assert.Equal(t, "12/30", formatExpiry("12", "30"))
+	assert.Equal(t, "", formatExpiry("", "2027"))
+}
+
+func TestJoinNotes(t *testing.T) {
+	assert.Equal(t, "a\nb", joinNotes("a", "", " ", "b"))
+	assert.Equal(t, "", joinNotes())
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	assert.Equal(t, "Mail", normalizeTitle("Mail"))
+	assert.Equal(t, untitled, normalizeTitle(""))
+	assert.Equal(t, untitled, normalizeTitle("  "))
+	assert.Equal(t, strings.Repeat("я", maxTitleLength), normalizeTitle(strings.Repeat("я", maxTitleLength+10)))
+}
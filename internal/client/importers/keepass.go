@@ -0,0 +1,188 @@
+package importers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// keePassFile mirrors the parts of the KeePass 2.x XML export used by the importer.
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string          `xml:"RecycleBinUUID"`
+		Binaries       []keePassBinary `xml:"Binaries>Binary"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+// keePassBinary is a shared attachment stored in the Meta section.
+type keePassBinary struct {
+	ID         string `xml:"ID,attr"`
+	Compressed bool   `xml:"Compressed,attr"`
+	Content    string `xml:",chardata"`
+}
+
+// keePassGroup is a folder of entries which may contain nested groups.
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry is a single KeePass record. Entry history is intentionally not mapped.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref     string `xml:"Ref,attr"`
+			Content string `xml:",chardata"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+}
+
+// keePassStandardFields are the string fields with a dedicated mapping.
+var keePassStandardFields = map[string]bool{
+	"Title":    true,
+	"UserName": true,
+	"Password": true,
+	"URL":      true,
+	"Notes":    true,
+}
+
+// parseKeePassXML parses a KeePass 2.x XML export.
+// Entries with a username or password become credentials, entries with only notes become
+// text items and every attachment becomes a separate binary item.
+func parseKeePassXML(r io.Reader) (*Result, error) {
+	var file keePassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode KeePass XML: %w", err)
+	}
+
+	binaries := make(map[string][]byte, len(file.Meta.Binaries))
+	for _, b := range file.Meta.Binaries {
+		data, err := decodeKeePassBinary(b.Content, b.Compressed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode KeePass binary %s: %w", b.ID, err)
+		}
+		binaries[b.ID] = data
+	}
+
+	res := &Result{}
+	for _, g := range file.Root.Groups {
+		// The top-level group is the database itself and is not part of the folder path.
+		walkKeePassGroup(res, g, "", file.Meta.RecycleBinUUID, binaries)
+	}
+	return res, nil
+}
+
+// walkKeePassGroup imports the entries of a group and recurses into its subgroups.
+func walkKeePassGroup(res *Result, g keePassGroup, folder, recycleBin string, binaries map[string][]byte) {
+	if recycleBin != "" && g.UUID == recycleBin {
+		return
+	}
+
+	for _, e := range g.Entries {
+		importKeePassEntry(res, e, folder, binaries)
+	}
+
+	for _, sub := range g.Groups {
+		subFolder := sub.Name
+		if folder != "" {
+			subFolder = folder + "/" + sub.Name
+		}
+		walkKeePassGroup(res, sub, subFolder, recycleBin, binaries)
+	}
+}
+
+// importKeePassEntry maps a single entry and its attachments onto create requests.
+func importKeePassEntry(res *Result, e keePassEntry, folder string, binaries map[string][]byte) {
+	fields := make(map[string]string, len(e.Strings))
+	var custom []string
+	for _, s := range e.Strings {
+		fields[s.Key] = s.Value
+		if !keePassStandardFields[s.Key] && s.Value != "" {
+			custom = append(custom, s.Key+": "+s.Value)
+		}
+	}
+	sort.Strings(custom)
+
+	title := normalizeTitle(fields["Title"])
+	meta := models.ItemMetadata{Folder: folder, URL: fields["URL"], Source: string(FormatKeePassXML)}
+	notes := joinNotes(fields["Notes"], strings.Join(custom, "\n"))
+
+	switch {
+	case fields["UserName"] != "" || fields["Password"] != "":
+		payload := models.CredentialPayload{
+			Username: fields["UserName"],
+			Password: fields["Password"],
+			URL:      fields["URL"],
+			Notes:    notes,
+			OTP:      fields["otp"],
+		}
+		if err := res.addJSON(models.ItemTypeCredential, title, meta, payload); err != nil {
+			res.skip(title, err.Error())
+		}
+	case notes != "":
+		res.add(models.ItemTypeText, title, meta, []byte(notes))
+	case len(e.Binaries) == 0:
+		res.skip(title, "entry has no data")
+	}
+
+	for _, b := range e.Binaries {
+		attTitle := normalizeTitle(title + "/" + b.Key)
+		var data []byte
+		if b.Value.Ref != "" {
+			shared, ok := binaries[b.Value.Ref]
+			if !ok {
+				res.skip(attTitle, "attachment reference "+b.Value.Ref+" not found")
+				continue
+			}
+			data = shared
+		} else {
+			inline, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b.Value.Content))
+			if err != nil {
+				res.skip(attTitle, "invalid attachment encoding")
+				continue
+			}
+			data = inline
+		}
+		res.add(models.ItemTypeBinary, attTitle, meta, data)
+	}
+}
+
+// decodeKeePassBinary decodes a base64 attachment, decompressing it when necessary.
+func decodeKeePassBinary(content string, compressed bool) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64: %w", err)
+	}
+	if !compressed {
+		return raw, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,148 @@
+package importers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBase64(t *testing.T, data string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestParseKeePassXML(t *testing.T) {
+	export := `<?xml version="1.0" encoding="utf-8"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinUUID>bin</RecycleBinUUID>
+		<Binaries>
+			<Binary ID="0" Compressed="True">` + gzipBase64(t, "key material") + `</Binary>
+		</Binaries>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>root</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>GitHub</Value></String>
+				<String><Key>UserName</Key><Value>alice</Value></String>
+				<String><Key>Password</Key><Value Protected="True">s3cret</Value></String>
+				<String><Key>URL</Key><Value>https://github.com</Value></String>
+				<String><Key>Recovery</Key><Value>abcd-efgh</Value></String>
+				<History>
+					<Entry>
+						<String><Key>Title</Key><Value>GitHub old</Value></String>
+						<String><Key>Password</Key><Value>old</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>work</UUID>
+				<Name>Work</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Server keys</Value></String>
+					<String><Key>Notes</Key><Value>rotate yearly</Value></String>
+					<Binary><Key>id_ed25519</Key><Value Ref="0"/></Binary>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>bin</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Deleted</Value></String>
+					<String><Key>Password</Key><Value>gone</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+	res, err := Parse(FormatKeePassXML, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 3)
+
+	cred := res.Items[0]
+	assert.Equal(t, models.ItemTypeCredential, cred.Type)
+	assert.Equal(t, "GitHub", cred.Title)
+	raw, err := base64.StdEncoding.DecodeString(cred.DataBase64)
+	require.NoError(t, err)
+	var payload models.CredentialPayload
+	require.NoError(t, json.Unmarshal(raw, &payload))
+	assert.Equal(t, "alice", payload.Username)
+	assert.Equal(t, "s3cret", payload.Password)
+	assert.Equal(t, "Recovery: abcd-efgh", payload.Notes)
+	assert.Equal(t, "https://github.com", models.ParseItemMetadata(cred.Metadata).URL)
+
+	note := res.Items[1]
+	assert.Equal(t, models.ItemTypeText, note.Type)
+	assert.Equal(t, "Work", models.ParseItemMetadata(note.Metadata).Folder)
+
+	att := res.Items[2]
+	assert.Equal(t, models.ItemTypeBinary, att.Type)
+	assert.Equal(t, "Server keys/id_ed25519", att.Title)
+	raw, err = base64.StdEncoding.DecodeString(att.DataBase64)
+	require.NoError(t, err)
+	assert.Equal(t, "key material", string(raw))
+}
+
+func TestParseKeePassXML_MissingAttachment(t *testing.T) {
+	export := `<KeePassFile><Root><Group><Name>Root</Name>
+		<Entry>
+			<String><Key>Title</Key><Value>Broken</Value></String>
+			<Binary><Key>file.bin</Key><Value Ref="7"/></Binary>
+		</Entry>
+	</Group></Root></KeePassFile>`
+
+	res, err := Parse(FormatKeePassXML, strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Empty(t, res.Items)
+	require.Len(t, res.Skipped, 1)
+	assert.Equal(t, "Broken/file.bin", res.Skipped[0].Title)
+}
+
+func TestParseKeePassXML_Invalid(t *testing.T) {
+	_, err := Parse(FormatKeePassXML, strings.NewReader("not xml"))
+	assert.Error(t, err)
+}
+
+func TestParseKeePassXML_Titles(t *testing.T) {
+	long := strings.Repeat("x", maxTitleLength+1)
+	export := `<?xml version="1.0" encoding="utf-8"?>
+<KeePassFile>
+	<Root>
+		<Group>
+			<UUID>root</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Notes</Key><Value>no title</Value></String>
+			</Entry>
+			<Entry>
+				<String><Key>Title</Key><Value>` + long + `</Value></String>
+				<String><Key>Notes</Key><Value>long title</Value></String>
+				<Binary><Key>id_rsa</Key><Value>` + base64.StdEncoding.EncodeToString([]byte("key")) + `</Value></Binary>
+			</Entry>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+	res, err := Parse(FormatKeePassXML, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 3)
+	assert.Equal(t, untitled, res.Items[0].Title)
+	assert.Equal(t, long[:maxTitleLength], res.Items[1].Title)
+	assert.Equal(t, long[:maxTitleLength], res.Items[2].Title)
+	assert.Equal(t, models.ItemTypeBinary, res.Items[2].Type)
+}
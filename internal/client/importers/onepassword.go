@@ -0,0 +1,132 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// onePasswordColumns maps canonical field names to the column headers used by
+// different 1Password versions.
+var onePasswordColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"url":      {"url", "website", "urls"},
+	"username": {"username", "login"},
+	"password": {"password"},
+	"otp":      {"otpauth", "one-time password", "otp"},
+	"notes":    {"notes", "notesplain"},
+	"category": {"type", "category"},
+	"folder":   {"vault", "folder"},
+	"number":   {"number", "card number", "ccnum"},
+	"holder":   {"cardholder", "cardholder name"},
+	"expiry":   {"expiry", "expiry date", "expires"},
+	"cvv":      {"cvv", "verification number", "cvc"},
+}
+
+// parse1PasswordCSV parses a 1Password CSV export.
+// Rows with card fields become card items, rows with a username or password become
+// credentials, and the remaining rows with notes become text items.
+func parse1PasswordCSV(r io.Reader) (*Result, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read 1Password CSV: %w", err)
+	}
+
+	res := &Result{}
+	for _, row := range rows {
+		field := func(name string) string {
+			return row.get(onePasswordColumns[name]...)
+		}
+
+		title := normalizeTitle(field("title"))
+		category := strings.ToLower(field("category"))
+		meta := models.ItemMetadata{
+			Folder: field("folder"),
+			URL:    field("url"),
+			Source: string(Format1PasswordCSV),
+		}
+
+		switch {
+		case strings.Contains(category, "card") || field("number") != "":
+			err = res.addJSON(models.ItemTypeCard, title, meta, models.CardPayload{
+				Number: field("number"),
+				Holder: field("holder"),
+				Expiry: field("expiry"),
+				CVV:    field("cvv"),
+				Notes:  field("notes"),
+			})
+		case field("username") != "" || field("password") != "":
+			err = res.addJSON(models.ItemTypeCredential, title, meta, models.CredentialPayload{
+				Username: field("username"),
+				Password: field("password"),
+				URL:      field("url"),
+				Notes:    field("notes"),
+				OTP:      field("otp"),
+			})
+		case field("notes") != "":
+			res.add(models.ItemTypeText, title, meta, []byte(field("notes")))
+		default:
+			res.skip(title, "entry has no data")
+		}
+		if err != nil {
+			res.skip(title, err.Error())
+			err = nil
+		}
+	}
+	return res, nil
+}
+
+// csvRow is a CSV record addressable by lower-cased header name.
+type csvRow map[string]string
+
+// get returns the first non-empty value among the given column names.
+func (r csvRow) get(names ...string) string {
+	for _, name := range names {
+		if v := r[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// readCSV reads a CSV file with a header row into records keyed by lower-cased column name.
+func readCSV(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing header row")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i, h := range header {
+		// Strip a UTF-8 byte order mark written by some exporters.
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+
+		row := make(csvRow, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
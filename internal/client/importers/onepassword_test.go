@@ -0,0 +1,65 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse1PasswordCSV(t *testing.T) {
+	export := "\ufeffTitle,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+		"Bank,https://bank.example.com,dave,pa55,,false,false,,\n" +
+		"Door code,,,,,false,false,,1234#\n" +
+		"Empty,,,,,false,false,,\n"
+
+	res, err := Parse(Format1PasswordCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+	require.Len(t, res.Skipped, 1)
+	assert.Equal(t, "Empty", res.Skipped[0].Title)
+
+	assert.Equal(t, models.ItemTypeCredential, res.Items[0].Type)
+	var cred models.CredentialPayload
+	decodePayload(t, res.Items[0], &cred)
+	assert.Equal(t, "dave", cred.Username)
+	assert.Equal(t, "https://bank.example.com", cred.URL)
+
+	assert.Equal(t, models.ItemTypeText, res.Items[1].Type)
+}
+
+func TestParse1PasswordCSV_Card(t *testing.T) {
+	export := "title,type,cardholder name,number,expiry date,verification number\n" +
+		"Amex,Credit Card,Eve,378282246310005,11/27,4321\n"
+
+	res, err := Parse(Format1PasswordCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+
+	var card models.CardPayload
+	decodePayload(t, res.Items[0], &card)
+	assert.Equal(t, models.ItemTypeCard, res.Items[0].Type)
+	assert.Equal(t, "Eve", card.Holder)
+	assert.Equal(t, "11/27", card.Expiry)
+	assert.Equal(t, "4321", card.CVV)
+}
+
+func TestParse1PasswordCSV_Empty(t *testing.T) {
+	_, err := Parse(Format1PasswordCSV, strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestParse1PasswordCSV_Titles(t *testing.T) {
+	long := strings.Repeat("x", maxTitleLength+1)
+	export := "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+		",,,,,false,false,,no title\n" +
+		long + ",,,,,false,false,,long title\n"
+
+	res, err := Parse(Format1PasswordCSV, strings.NewReader(export))
+	require.NoError(t, err)
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, untitled, res.Items[0].Title)
+	assert.Equal(t, long[:maxTitleLength], res.Items[1].Title)
+}
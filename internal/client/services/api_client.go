@@ -5,22 +5,68 @@
 package services
 
 import (
+	"crypto/rand"
 	"fmt"
+	"strconv"
 
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/go-resty/resty/v2"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // APIClient handles HTTP communication with the GophKeeper server.
 type APIClient struct {
 	client *resty.Client
+	// traceContext is the trace shared by all requests of the client,
+	// so the server spans of one command end up in the same trace.
+	traceContext trace.SpanContext
 }
 
 // NewAPIClient creates a new API client instance with the specified base URL.
+// Every request carries a W3C traceparent header.
 func NewAPIClient(client *resty.Client, baseURL string) *APIClient {
 	client.SetBaseURL(baseURL)
-	return &APIClient{client: client}
+	c := &APIClient{client: client}
+
+	var traceID trace.TraceID
+	_, _ = rand.Read(traceID[:])
+	c.traceContext = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	client.OnBeforeRequest(c.injectTraceContext)
+	client.OnBeforeRequest(setRequestID)
+	return c
+}
+
+// TraceID returns the ID of the trace the requests of the client belong to.
+func (c *APIClient) TraceID() string {
+	return c.traceContext.TraceID().String()
+}
+
+// injectTraceContext adds the traceparent header to a request.
+// A span context already present in the request context takes precedence,
+// otherwise the request becomes a new span of the client's trace.
+func (c *APIClient) injectTraceContext(_ *resty.Client, req *resty.Request) error {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		var spanID trace.SpanID
+		_, _ = rand.Read(spanID[:])
+		ctx = trace.ContextWithRemoteSpanContext(ctx, c.traceContext.WithSpanID(spanID))
+	}
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return nil
+}
+
+// setRequestID gives every request its own X-Request-ID, so a failure can be matched
+// with the server logs. The server returns the ID in the response.
+func setRequestID(_ *resty.Client, req *resty.Request) error {
+	if req.Header.Get(requestIDHeader) == "" {
+		req.Header.Set(requestIDHeader, uuid.NewString())
+	}
+	return nil
 }
 
 // authResponse represents the authentication response from the server.
@@ -43,11 +89,11 @@ func (c *APIClient) SetToken(token string) {
 // Returns the authentication token for the new user.
 func (c *APIClient) Register(username, password string) (string, error) {
 	var resp authResponse
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetBody(map[string]string{"username": username, "password": password}).
 		SetResult(&resp).
 		Post("/api/v1/register")
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return "", fmt.Errorf("failed to register user %q: %w", username, err)
 	}
 	if resp.Token == "" {
@@ -60,11 +106,11 @@ func (c *APIClient) Register(username, password string) (string, error) {
 // Returns the authentication token for the user.
 func (c *APIClient) Login(username, password string) (string, error) {
 	var resp authResponse
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetBody(map[string]string{"username": username, "password": password}).
 		SetResult(&resp).
 		Post("/api/v1/login")
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return "", fmt.Errorf("failed to login user %q: %w", username, err)
 	}
 	if resp.Token == "" {
@@ -82,11 +128,11 @@ func (c *APIClient) CreateItem(req *models.CreateItemRequest) (*models.Item, err
 	var resp struct {
 		Item *models.Item `json:"item"`
 	}
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetBody(req).
 		SetResult(&resp).
 		Post("/api/v1/items")
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return nil, fmt.Errorf("failed to create item %q: %w", req.Title, err)
 	}
 	return resp.Item, nil
@@ -101,11 +147,11 @@ func (c *APIClient) UpdateItem(id uuid.UUID, req *models.UpdateItemRequest) (*mo
 	var resp struct {
 		Item *models.Item `json:"item"`
 	}
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetBody(req).
 		SetResult(&resp).
 		Put(fmt.Sprintf("/api/v1/items/%s", id))
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return nil, fmt.Errorf("failed to update item %s: %w", id, err)
 	}
 	return resp.Item, nil
@@ -118,10 +164,10 @@ func (c *APIClient) GetItem(id uuid.UUID) (*models.Item, *string, error) {
 		Item *models.Item `json:"item"`
 		Data *string      `json:"data_base64,omitempty"`
 	}
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetResult(&resp).
 		Get(fmt.Sprintf("/api/v1/items/%s", id))
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return nil, nil, fmt.Errorf("failed to get item %s: %w", id, err)
 	}
 	return resp.Item, resp.Data, nil
@@ -133,21 +179,219 @@ func (c *APIClient) ListItems() ([]*models.Item, error) {
 	var resp struct {
 		Items []*models.Item `json:"items"`
 	}
-	_, err := c.client.R().
+	r, err := c.client.R().
 		SetResult(&resp).
 		Get("/api/v1/items")
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return nil, fmt.Errorf("failed to list items: %w", err)
 	}
 	return resp.Items, nil
 }
 
+// ListDue retrieves items that are expired or expire within the given number of days.
+// When olderThan is positive, items not updated for that many days are reported too.
+func (c *APIClient) ListDue(days, olderThan int) ([]models.DueItem, error) {
+	var resp struct {
+		Items []models.DueItem `json:"items"`
+	}
+	req := c.client.R().
+		SetResult(&resp).
+		SetQueryParam("days", strconv.Itoa(days))
+	if olderThan > 0 {
+		req.SetQueryParam("older_than", strconv.Itoa(olderThan))
+	}
+	r, err := req.Get("/api/v1/items/due")
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to list due items: %w", err)
+	}
+	return resp.Items, nil
+}
+
 // DeleteItem removes an item from the server.
 func (c *APIClient) DeleteItem(id uuid.UUID) error {
-	_, err := c.client.R().
+	r, err := c.client.R().
 		Delete(fmt.Sprintf("/api/v1/items/%s", id))
-	if err != nil {
+	if err = checkResponse(r, err); err != nil {
 		return fmt.Errorf("failed to delete item %s: %w", id, err)
 	}
 	return nil
 }
+
+// ExecuteBatch sends several item operations to the server in a single request.
+// Returns the per-operation results. For atomic batches that were rolled back the
+// results describe the failing operation and an error is returned.
+func (c *APIClient) ExecuteBatch(req *models.BatchRequest) ([]models.BatchResult, error) {
+	if req == nil {
+		return nil, fmt.Errorf("batch request cannot be nil")
+	}
+	var resp models.BatchResponse
+	r, err := c.client.R().
+		SetBody(req).
+		SetResult(&resp).
+		SetError(&resp).
+		Post("/api/v1/items/batch")
+	if err = checkResponse(r, err); err != nil {
+		return resp.Results, fmt.Errorf("failed to execute batch of %d operations: %w", len(req.Operations), err)
+	}
+	return resp.Results, nil
+}
+
+// ListTrash retrieves the deleted items of the authenticated user that have not been purged.
+func (c *APIClient) ListTrash() ([]*models.Item, error) {
+	var resp struct {
+		Items []*models.Item `json:"items"`
+	}
+	r, err := c.client.R().
+		SetResult(&resp).
+		Get("/api/v1/trash")
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	return resp.Items, nil
+}
+
+// RestoreItem moves a deleted item out of the trash.
+// Returns the restored item metadata.
+func (c *APIClient) RestoreItem(id uuid.UUID) (*models.Item, error) {
+	var resp struct {
+		Item *models.Item `json:"item"`
+	}
+	r, err := c.client.R().
+		SetResult(&resp).
+		Post(fmt.Sprintf("/api/v1/trash/%s/restore", id))
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to restore item %s: %w", id, err)
+	}
+	return resp.Item, nil
+}
+
+// PurgeItem permanently removes a deleted item from the server.
+func (c *APIClient) PurgeItem(id uuid.UUID) error {
+	r, err := c.client.R().
+		Delete(fmt.Sprintf("/api/v1/trash/%s", id))
+	if err = checkResponse(r, err); err != nil {
+		return fmt.Errorf("failed to purge item %s: %w", id, err)
+	}
+	return nil
+}
+
+// EmptyTrash permanently removes all deleted items of the authenticated user.
+// Returns the number of purged items.
+func (c *APIClient) EmptyTrash() (int64, error) {
+	var resp struct {
+		Purged int64 `json:"purged"`
+	}
+	r, err := c.client.R().
+		SetResult(&resp).
+		Delete("/api/v1/trash")
+	if err = checkResponse(r, err); err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return resp.Purged, nil
+}
+
+// GetUsage retrieves the storage usage and quota of the authenticated user.
+func (c *APIClient) GetUsage() (*models.UsageReport, error) {
+	var resp models.UsageReport
+	r, err := c.client.R().
+		SetResult(&resp).
+		Get("/api/v1/usage")
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	return &resp, nil
+}
+
+// ListUsers retrieves all users with their storage usage. Requires the admin role.
+func (c *APIClient) ListUsers() ([]*models.UserInfo, error) {
+	var resp struct {
+		Users []*models.UserInfo `json:"users"`
+	}
+	r, err := c.client.R().
+		SetResult(&resp).
+		Get("/api/v1/admin/users")
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return resp.Users, nil
+}
+
+// SetUserDisabled disables a user and revokes their tokens, or re-enables a disabled user.
+// Requires the admin role.
+func (c *APIClient) SetUserDisabled(id uuid.UUID, disabled bool) error {
+	action := "enable"
+	if disabled {
+		action = "disable"
+	}
+	r, err := c.client.R().
+		Post(fmt.Sprintf("/api/v1/admin/users/%s/%s", id, action))
+	if err = checkResponse(r, err); err != nil {
+		return fmt.Errorf("failed to %s user %s: %w", action, id, err)
+	}
+	return nil
+}
+
+// LogoutUser revokes all tokens issued to a user. Requires the admin role.
+func (c *APIClient) LogoutUser(id uuid.UUID) error {
+	r, err := c.client.R().
+		Post(fmt.Sprintf("/api/v1/admin/users/%s/logout", id))
+	if err = checkResponse(r, err); err != nil {
+		return fmt.Errorf("failed to log out user %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteUser removes a user together with their keys and items. Requires the admin role.
+func (c *APIClient) DeleteUser(id uuid.UUID) error {
+	r, err := c.client.R().
+		Delete(fmt.Sprintf("/api/v1/admin/users/%s", id))
+	if err = checkResponse(r, err); err != nil {
+		return fmt.Errorf("failed to delete user %s: %w", id, err)
+	}
+	return nil
+}
+
+// ChangePassword changes the password of the authenticated user.
+// The server revokes all tokens of the user, the returned token replaces them.
+func (c *APIClient) ChangePassword(oldPassword, newPassword string) (string, error) {
+	var resp authResponse
+	r, err := c.client.R().
+		SetBody(map[string]string{"old_password": oldPassword, "new_password": newPassword}).
+		SetResult(&resp).
+		Put("/api/v1/account/password")
+	if err = checkResponse(r, err); err != nil {
+		return "", fmt.Errorf("failed to change password: %w", err)
+	}
+	if resp.Token == "" {
+		return "", fmt.Errorf("empty token in response")
+	}
+	return resp.Token, nil
+}
+
+// ChangeUsername renames the account of the authenticated user.
+// Returns the updated user.
+func (c *APIClient) ChangeUsername(username string) (*models.User, error) {
+	var resp struct {
+		User *models.User `json:"user"`
+	}
+	r, err := c.client.R().
+		SetBody(map[string]string{"username": username}).
+		SetResult(&resp).
+		Put("/api/v1/account/username")
+	if err = checkResponse(r, err); err != nil {
+		return nil, fmt.Errorf("failed to change username to %q: %w", username, err)
+	}
+	return resp.User, nil
+}
+
+// DeleteAccount removes the account of the authenticated user with all items and keys.
+// The password is required to confirm the deletion.
+func (c *APIClient) DeleteAccount(password string) error {
+	r, err := c.client.R().
+		SetBody(map[string]string{"password": password}).
+		Delete("/api/v1/account")
+	if err = checkResponse(r, err); err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Pro100x3mal/gophkeeper/models"
@@ -66,6 +67,29 @@ func TestAPIClient_Register_Success(t *testing.T) {
 	assert.Equal(t, "test-token-123", token)
 }
 
+func TestAPIClient_Register_ValidationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ValidationError{
+			Message: "validation failed",
+			Fields: map[string][]string{
+				"password": {"is too common"},
+				"username": {"is reserved"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+
+	_, err := apiClient.Register("admin", "qwerty")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "validation failed: password is too common; username is reserved", apiErr.Message)
+}
+
 func TestAPIClient_Register_EmptyToken(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := authResponse{
@@ -350,9 +374,130 @@ func TestAPIClient_DeleteItem_ServerError(t *testing.T) {
 	client := resty.New()
 	apiClient := NewAPIClient(client, server.URL)
 
-	// Note: resty doesn't treat 4xx/5xx as errors by default
-	// This test just verifies the method doesn't panic
-	_ = apiClient.DeleteItem(itemID)
+	err := apiClient.DeleteItem(itemID)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+	assert.Equal(t, "internal server error", apiErr.Message)
+}
+
+func TestAPIClient_ErrorClasses(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusServiceUnavailable, ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, http.StatusText(tt.status), tt.status)
+			}))
+			defer server.Close()
+
+			apiClient := NewAPIClient(resty.New(), server.URL)
+			_, _, err := apiClient.GetItem(uuid.New())
+			assert.ErrorIs(t, err, tt.target)
+		})
+	}
+
+	t.Run("network error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		apiClient := NewAPIClient(resty.New(), server.URL)
+		_, err := apiClient.ListItems()
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestAPIClient_ExecuteBatch_Success(t *testing.T) {
+	itemID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/items/batch", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req models.BatchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.Operations, 1)
+		assert.Equal(t, models.BatchOpDelete, req.Operations[0].Op)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.BatchResponse{Results: []models.BatchResult{
+			{Index: 0, Op: models.BatchOpDelete, Status: http.StatusNoContent},
+		}})
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+
+	results, err := apiClient.ExecuteBatch(&models.BatchRequest{Operations: []models.BatchOperation{
+		{Op: models.BatchOpDelete, ID: &itemID},
+	}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, http.StatusNoContent, results[0].Status)
+}
+
+func TestAPIClient_ExecuteBatch_AtomicFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "req-42")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.BatchResponse{Results: []models.BatchResult{
+			{Index: 1, Op: models.BatchOpDelete, Status: http.StatusNotFound, Error: "item not found"},
+		}})
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+
+	results, err := apiClient.ExecuteBatch(&models.BatchRequest{Atomic: true})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "operation 1: item not found", apiErr.Message)
+	assert.Equal(t, "req-42", apiErr.RequestID)
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Index)
+}
+
+func TestAPIClient_ExecuteBatch_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-43")
+		http.Error(w, "batch must contain at least one operation", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+
+	results, err := apiClient.ExecuteBatch(&models.BatchRequest{})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "batch must contain at least one operation", apiErr.Message)
+	assert.Equal(t, "req-43", apiErr.RequestID)
+	assert.Empty(t, results)
+}
+
+func TestAPIClient_ExecuteBatch_Unavailable(t *testing.T) {
+	apiClient := NewAPIClient(resty.New(), "http://127.0.0.1:1")
+
+	_, err := apiClient.ExecuteBatch(&models.BatchRequest{})
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestAPIClient_ExecuteBatch_NilRequest(t *testing.T) {
+	apiClient := NewAPIClient(resty.New(), "http://localhost:8080")
+
+	_, err := apiClient.ExecuteBatch(nil)
+	assert.Error(t, err)
 }
 
 // Test with different item types
@@ -405,3 +550,317 @@ func TestAPIClient_CreateItem_DifferentTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIClient_ListTrash_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/trash", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		resp := struct {
+			Items []*models.Item `json:"items"`
+		}{
+			Items: []*models.Item{{ID: uuid.New(), Type: models.ItemTypeText, Title: "Deleted"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	items, err := apiClient.ListTrash()
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "Deleted", items[0].Title)
+}
+
+func TestAPIClient_RestoreItem_Success(t *testing.T) {
+	itemID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/trash/"+itemID.String()+"/restore", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		resp := struct {
+			Item *models.Item `json:"item"`
+		}{
+			Item: &models.Item{ID: itemID, Type: models.ItemTypeText, Title: "Restored"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	item, err := apiClient.RestoreItem(itemID)
+	assert.NoError(t, err)
+	assert.Equal(t, itemID, item.ID)
+}
+
+func TestAPIClient_RestoreItem_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	item, err := apiClient.RestoreItem(uuid.New())
+	assert.Error(t, err)
+	assert.Nil(t, item)
+}
+
+func TestAPIClient_PurgeItem_Success(t *testing.T) {
+	itemID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/trash/"+itemID.String(), r.URL.Path)
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	err := apiClient.PurgeItem(itemID)
+	assert.NoError(t, err)
+}
+
+func TestAPIClient_EmptyTrash_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/trash", r.URL.Path)
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"purged":4}`))
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	n, err := apiClient.EmptyTrash()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+}
+
+func TestAPIClient_GetUsage_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/usage", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"usage":{"items":3,"bytes":2048},"quota":{"max_items":10,"max_bytes":0}}`))
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	report, err := apiClient.GetUsage()
+	require.NoError(t, err)
+	assert.Equal(t, models.Usage{Items: 3, Bytes: 2048}, report.Usage)
+	assert.Equal(t, models.Quota{MaxItems: 10}, report.Quota)
+}
+
+func TestAPIClient_ListDue_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/items/due", r.URL.Path)
+		assert.Equal(t, "14", r.URL.Query().Get("days"))
+		assert.Equal(t, "180", r.URL.Query().Get("older_than"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"item":{"title":"Visa"},"reason":"expiring"}]}`))
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	items, err := apiClient.ListDue(14, 180)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "Visa", items[0].Item.Title)
+	assert.Equal(t, models.DueReasonExpiring, items[0].Reason)
+}
+
+func TestAPIClient_ListDue_WithoutAge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.False(t, r.URL.Query().Has("older_than"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	apiClient := NewAPIClient(client, server.URL)
+
+	items, err := apiClient.ListDue(30, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestAPIClient_TraceContext(t *testing.T) {
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+	require.NoError(t, apiClient.DeleteItem(uuid.New()))
+	require.NoError(t, apiClient.DeleteItem(uuid.New()))
+
+	require.Len(t, traceparents, 2)
+	first := strings.Split(traceparents[0], "-")
+	second := strings.Split(traceparents[1], "-")
+	require.Len(t, first, 4)
+	require.Len(t, second, 4)
+
+	// Both requests belong to the client's trace but are separate spans.
+	assert.Equal(t, "00", first[0])
+	assert.Equal(t, apiClient.TraceID(), first[1])
+	assert.Equal(t, first[1], second[1])
+	assert.NotEqual(t, first[2], second[2])
+	assert.Equal(t, "01", first[3])
+
+	other := NewAPIClient(resty.New(), server.URL)
+	assert.NotEqual(t, apiClient.TraceID(), other.TraceID())
+}
+
+func TestAPIClient_RequestID(t *testing.T) {
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-ID"))
+		w.Header().Set("X-Request-ID", r.Header.Get("X-Request-ID"))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+	_, err1 := apiClient.ListItems()
+	_, err2 := apiClient.ListItems()
+
+	require.Len(t, requestIDs, 2)
+	assert.NotEmpty(t, requestIDs[0])
+	assert.NotEqual(t, requestIDs[0], requestIDs[1])
+
+	var apiErr *APIError
+	require.ErrorAs(t, err1, &apiErr)
+	assert.Equal(t, requestIDs[0], apiErr.RequestID)
+	assert.Contains(t, err1.Error(), "(request ID "+requestIDs[0]+")")
+	assert.Contains(t, err2.Error(), requestIDs[1])
+}
+
+func TestAPIClient_ListUsers_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/admin/users", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"users":[{"username":"alice","role":"admin","usage":{"items":1,"bytes":10}}]}`))
+	}))
+	defer server.Close()
+
+	apiClient := NewAPIClient(resty.New(), server.URL)
+
+	users, err := apiClient.ListUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Equal(t, models.RoleAdmin, users[0].Role)
+	assert.Equal(t, models.Usage{Items: 1, Bytes: 10}, users[0].Usage)
+}
+
+func TestAPIClient_AdminActions(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		call   func(c *APIClient) error
+	}{
+		{"disable", http.MethodPost, "/api/v1/admin/users/" + id.String() + "/disable", func(c *APIClient) error { return c.SetUserDisabled(id, true) }},
+		{"enable", http.MethodPost, "/api/v1/admin/users/" + id.String() + "/enable", func(c *APIClient) error { return c.SetUserDisabled(id, false) }},
+		{"logout", http.MethodPost, "/api/v1/admin/users/" + id.String() + "/logout", func(c *APIClient) error { return c.LogoutUser(id) }},
+		{"delete", http.MethodDelete, "/api/v1/admin/users/" + id.String(), func(c *APIClient) error { return c.DeleteUser(id) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.path, r.URL.Path)
+				assert.Equal(t, tt.method, r.Method)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			assert.NoError(t, tt.call(NewAPIClient(resty.New(), server.URL)))
+		})
+	}
+}
+
+func TestAPIClient_ListUsers_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewAPIClient(resty.New(), server.URL).ListUsers()
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestAPIClient_ChangePassword_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/account/password", r.URL.Path)
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"old_password": "old", "new_password": "new"}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"new-token","user_id":"` + uuid.NewString() + `"}`))
+	}))
+	defer server.Close()
+
+	token, err := NewAPIClient(resty.New(), server.URL).ChangePassword("old", "new")
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", token)
+}
+
+func TestAPIClient_ChangeUsername_Conflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/account/username", r.URL.Path)
+		http.Error(w, "Conflict", http.StatusConflict)
+	}))
+	defer server.Close()
+
+	_, err := NewAPIClient(resty.New(), server.URL).ChangeUsername("taken")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+}
+
+func TestAPIClient_DeleteAccount_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/account", r.URL.Path)
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "password", body["password"])
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, NewAPIClient(resty.New(), server.URL).DeleteAccount("password"))
+}
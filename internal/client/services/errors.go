@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// requestIDHeader is the header carrying the correlation ID of a request.
+const requestIDHeader = "X-Request-ID"
+
+var (
+	// ErrNotFound matches API errors for resources that do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches API errors for missing, expired or insufficient credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUnavailable is returned when the server cannot be reached or is temporarily unavailable.
+	ErrUnavailable = errors.New("server unavailable")
+)
+
+// APIError is returned when the server responds with an error status.
+// It matches ErrNotFound, ErrUnauthorized and ErrUnavailable with errors.Is.
+type APIError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Message is the error text returned by the server (optional).
+	Message string
+	// RequestID is the correlation ID of the failed request, it is written in the server logs (optional).
+	RequestID string
+}
+
+// Error returns the status, the server message and the request ID.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("status %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Is reports whether the status belongs to one of the error classes of this package.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable ||
+			e.StatusCode == http.StatusGatewayTimeout
+	default:
+		return false
+	}
+}
+
+// checkResponse converts a failed request into a typed error.
+// Transport errors wrap ErrUnavailable, error statuses are returned as *APIError.
+// Field-level validation errors are flattened into the message.
+func checkResponse(r *resty.Response, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	if r.IsError() {
+		requestID := r.Header().Get(requestIDHeader)
+		if requestID == "" && r.Request != nil {
+			requestID = r.Request.Header.Get(requestIDHeader)
+		}
+		return &APIError{StatusCode: r.StatusCode(), Message: errorMessage(r), RequestID: requestID}
+	}
+	return nil
+}
+
+// errorMessage returns the error text of a failed response.
+// Field-level validation errors and the failing operation of an aborted batch are rendered as text.
+func errorMessage(r *resty.Response) string {
+	if strings.HasPrefix(r.Header().Get("Content-Type"), "application/json") {
+		var body struct {
+			models.ValidationError
+			models.BatchResponse
+		}
+		if err := json.Unmarshal(r.Body(), &body); err == nil {
+			if len(body.Fields) > 0 {
+				return body.ValidationError.Error()
+			}
+			if len(body.Results) == 1 && body.Results[0].Error != "" {
+				return fmt.Sprintf("operation %d: %s", body.Results[0].Index, body.Results[0].Error)
+			}
+		}
+	}
+	return strings.TrimSpace(r.String())
+}
@@ -0,0 +1,361 @@
+// Package sshagent implements an SSH agent backed by ssh-key items of the GophKeeper vault.
+//
+// Private keys never leave the vault longer than needed: they are downloaded and decrypted
+// when the agent lists its identities to learn the public keys, and again for every signature.
+// Only public keys are kept in memory. Every signature can be confirmed interactively and
+// keys are dropped from the agent once their lifetime runs out.
+package sshagent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var (
+	// ErrDenied is returned when the user refuses to confirm a signature.
+	ErrDenied = errors.New("signature was not confirmed")
+	// ErrUnknownKey is returned when a signature is requested for a key the agent does not offer.
+	ErrUnknownKey = errors.New("key is not available in the agent")
+	// ErrLocked is returned for requests to a locked agent.
+	ErrLocked = errors.New("agent is locked")
+	// ErrReadOnly is returned when a client tries to add or remove keys.
+	ErrReadOnly = errors.New("keys are managed in the vault, adding and removing them is not supported")
+)
+
+// Source provides the ssh-key items of the vault.
+type Source interface {
+	// ListItems returns the metadata of all items.
+	ListItems() ([]*models.Item, error)
+	// GetItem returns an item with its base64-encoded decrypted data.
+	GetItem(id uuid.UUID) (*models.Item, *string, error)
+}
+
+// Key describes a key the agent is asked to sign with.
+type Key struct {
+	// ID is the ID of the vault item.
+	ID uuid.UUID
+	// Title is the title of the vault item.
+	Title string
+	// Fingerprint is the SHA256 fingerprint of the public key.
+	Fingerprint string
+}
+
+// ConfirmFunc asks the user whether a signature with the key is allowed.
+type ConfirmFunc func(key Key) bool
+
+// Options configures an Agent.
+type Options struct {
+	// Confirm is called before every signature if set.
+	Confirm ConfirmFunc
+	// Lifetime is how long a key is offered after it was loaded, zero means no limit.
+	Lifetime time.Duration
+}
+
+// loadedKey is a key the agent offers.
+type loadedKey struct {
+	id        uuid.UUID
+	title     string
+	comment   string
+	updatedAt time.Time
+	loadedAt  time.Time
+	pub       ssh.PublicKey
+}
+
+// Agent is an ssh agent.ExtendedAgent serving the ssh-key items of a Source.
+// It is safe for concurrent use.
+type Agent struct {
+	source   Source
+	confirm  ConfirmFunc
+	lifetime time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	keys       map[uuid.UUID]*loadedKey
+	expired    map[uuid.UUID]bool
+	locked     bool
+	passphrase []byte
+}
+
+var _ agent.ExtendedAgent = (*Agent)(nil)
+
+// New creates an agent serving the ssh-key items of the source.
+func New(source Source, opts Options) *Agent {
+	return &Agent{
+		source:   source,
+		confirm:  opts.Confirm,
+		lifetime: opts.Lifetime,
+		now:      time.Now,
+		keys:     make(map[uuid.UUID]*loadedKey),
+		expired:  make(map[uuid.UUID]bool),
+	}
+}
+
+// ParseSigner parses the JSON data of an ssh-key item into a signer.
+func ParseSigner(data []byte) (ssh.Signer, error) {
+	var payload models.SSHKeyPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode SSH key: %w", err)
+	}
+	if payload.PrivateKey == "" {
+		return nil, errors.New("SSH key has no private_key")
+	}
+
+	var signer ssh.Signer
+	var err error
+	if payload.Passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(payload.PrivateKey), []byte(payload.Passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(payload.PrivateKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return signer, nil
+}
+
+// fetchSigner downloads and decrypts the key of an item.
+func (a *Agent) fetchSigner(id uuid.UUID) (*models.Item, ssh.Signer, string, error) {
+	item, data, err := a.source.GetItem(id)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to get item %s: %w", id, err)
+	}
+	if item == nil || data == nil {
+		return nil, nil, "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	raw, err := base64.StdEncoding.DecodeString(*data)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to decode item %s: %w", id, err)
+	}
+
+	signer, err := ParseSigner(raw)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("item %q: %w", item.Title, err)
+	}
+	var payload models.SSHKeyPayload
+	_ = json.Unmarshal(raw, &payload)
+	return item, signer, payload.Comment, nil
+}
+
+// refresh loads new and updated ssh-key items and drops deleted and expired ones.
+// Must be called with the mutex held.
+func (a *Agent) refresh() error {
+	items, err := a.source.ListItems()
+	if err != nil {
+		return fmt.Errorf("failed to list items: %w", err)
+	}
+
+	now := a.now()
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range items {
+		if item == nil || item.Type != models.ItemTypeSSHKey || item.DeletedAt != nil {
+			continue
+		}
+		if item.ExpiresAt != nil && !item.ExpiresAt.After(now) {
+			continue
+		}
+		seen[item.ID] = true
+		if a.expired[item.ID] {
+			continue
+		}
+		if key, ok := a.keys[item.ID]; ok && key.updatedAt.Equal(item.UpdatedAt) {
+			continue
+		}
+
+		fetched, signer, comment, err := a.fetchSigner(item.ID)
+		if err != nil {
+			// A broken key must not hide the others.
+			delete(a.keys, item.ID)
+			continue
+		}
+		loadedAt := now
+		if key, ok := a.keys[item.ID]; ok {
+			loadedAt = key.loadedAt
+		}
+		a.keys[item.ID] = &loadedKey{
+			id:        fetched.ID,
+			title:     fetched.Title,
+			comment:   comment,
+			updatedAt: item.UpdatedAt,
+			loadedAt:  loadedAt,
+			pub:       signer.PublicKey(),
+		}
+	}
+
+	for id := range a.keys {
+		if !seen[id] {
+			delete(a.keys, id)
+		}
+	}
+	a.expire(now)
+	return nil
+}
+
+// expire drops keys whose lifetime has run out, they are not loaded again.
+// Must be called with the mutex held.
+func (a *Agent) expire(now time.Time) {
+	if a.lifetime <= 0 {
+		return
+	}
+	for id, key := range a.keys {
+		if now.Sub(key.loadedAt) >= a.lifetime {
+			delete(a.keys, id)
+			a.expired[id] = true
+		}
+	}
+}
+
+// List returns the public keys of the ssh-key items.
+func (a *Agent) List() ([]*agent.Key, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.locked {
+		return nil, nil
+	}
+	if err := a.refresh(); err != nil {
+		return nil, err
+	}
+
+	res := make([]*agent.Key, 0, len(a.keys))
+	for _, key := range a.keys {
+		comment := key.comment
+		if comment == "" {
+			comment = key.title
+		}
+		res = append(res, &agent.Key{Format: key.pub.Type(), Blob: key.pub.Marshal(), Comment: comment})
+	}
+	return res, nil
+}
+
+// Sign signs data with the key using its default algorithm.
+func (a *Agent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags downloads the private key, asks for confirmation and signs data with it.
+func (a *Agent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	loaded, err := a.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.confirm != nil && !a.confirm(Key{ID: loaded.id, Title: loaded.title, Fingerprint: ssh.FingerprintSHA256(loaded.pub)}) {
+		return nil, ErrDenied
+	}
+
+	_, signer, _, err := a.fetchSigner(loaded.id)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signer.PublicKey().Marshal(), key.Marshal()) {
+		return nil, fmt.Errorf("%w: key of item %q has changed", ErrUnknownKey, loaded.title)
+	}
+
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && key.Type() == ssh.KeyAlgoRSA {
+		switch {
+		case flags&agent.SignatureFlagRsaSha256 != 0:
+			return algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA256)
+		case flags&agent.SignatureFlagRsaSha512 != 0:
+			return algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+		}
+	}
+	return signer.Sign(rand.Reader, data)
+}
+
+// lookup returns the offered key matching a public key, reloading the keys if it is not known yet.
+func (a *Agent) lookup(pub ssh.PublicKey) (*loadedKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.locked {
+		return nil, ErrLocked
+	}
+
+	blob := pub.Marshal()
+	find := func() *loadedKey {
+		for _, key := range a.keys {
+			if bytes.Equal(key.pub.Marshal(), blob) {
+				return key
+			}
+		}
+		return nil
+	}
+
+	a.expire(a.now())
+	if key := find(); key != nil {
+		return key, nil
+	}
+	if err := a.refresh(); err != nil {
+		return nil, err
+	}
+	if key := find(); key != nil {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// Signers is not supported, private keys are only available inside the agent.
+func (a *Agent) Signers() ([]ssh.Signer, error) {
+	return nil, ErrReadOnly
+}
+
+// Add is not supported, keys are managed in the vault.
+func (a *Agent) Add(agent.AddedKey) error {
+	return ErrReadOnly
+}
+
+// Remove is not supported, keys are managed in the vault.
+func (a *Agent) Remove(ssh.PublicKey) error {
+	return ErrReadOnly
+}
+
+// RemoveAll is not supported, keys are managed in the vault.
+func (a *Agent) RemoveAll() error {
+	return ErrReadOnly
+}
+
+// Lock stops the agent from listing keys and signing until Unlock is called with the same passphrase.
+func (a *Agent) Lock(passphrase []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.locked {
+		return ErrLocked
+	}
+	a.locked = true
+	a.passphrase = bytes.Clone(passphrase)
+	return nil
+}
+
+// Unlock undoes Lock.
+func (a *Agent) Unlock(passphrase []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.locked {
+		return errors.New("agent is not locked")
+	}
+	if subtle.ConstantTimeCompare(passphrase, a.passphrase) != 1 {
+		return errors.New("incorrect passphrase")
+	}
+	a.locked = false
+	a.passphrase = nil
+	return nil
+}
+
+// Extension is not supported.
+func (a *Agent) Extension(string, []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
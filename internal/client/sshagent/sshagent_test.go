@@ -0,0 +1,270 @@
+package sshagent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// fakeSource is an in-memory Source that counts downloads.
+type fakeSource struct {
+	mu    sync.Mutex
+	items map[uuid.UUID]*models.Item
+	data  map[uuid.UUID]string
+	gets  int
+	err   error
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{items: make(map[uuid.UUID]*models.Item), data: make(map[uuid.UUID]string)}
+}
+
+func (s *fakeSource) add(t *testing.T, typ models.ItemType, title string, payload any) *models.Item {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := &models.Item{ID: uuid.New(), Type: typ, Title: title, UpdatedAt: time.Now()}
+	s.items[item.ID] = item
+	s.data[item.ID] = base64.StdEncoding.EncodeToString(raw)
+	return item
+}
+
+func (s *fakeSource) ListItems() ([]*models.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	res := make([]*models.Item, 0, len(s.items))
+	for _, item := range s.items {
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+func (s *fakeSource) GetItem(id uuid.UUID) (*models.Item, *string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	item, ok := s.items[id]
+	if !ok {
+		return nil, nil, errors.New("not found")
+	}
+	data := s.data[id]
+	return item, &data, nil
+}
+
+// ed25519Payload returns an ssh-key payload with a new key, optionally encrypted with a passphrase.
+func ed25519Payload(t *testing.T, passphrase string) (models.SSHKeyPayload, ssh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	}
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return models.SSHKeyPayload{PrivateKey: string(pem.EncodeToMemory(block)), Passphrase: passphrase}, sshPub
+}
+
+// connect serves the agent over an in-process connection and returns a client for it.
+func connect(t *testing.T, a *Agent) agent.ExtendedAgent {
+	t.Helper()
+	server, client := net.Pipe()
+	go func() { _ = agent.ServeAgent(a, server) }()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return agent.NewClient(client)
+}
+
+func TestAgent_ListAndSign(t *testing.T) {
+	source := newFakeSource()
+	payload, pub := ed25519Payload(t, "")
+	payload.Comment = "alice@laptop"
+	source.add(t, models.ItemTypeSSHKey, "Laptop", payload)
+	encrypted, encryptedPub := ed25519Payload(t, "s3cret")
+	source.add(t, models.ItemTypeSSHKey, "Server", encrypted)
+	source.add(t, models.ItemTypeText, "Note", "not a key")
+
+	client := connect(t, New(source, Options{}))
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	comments := map[string]string{}
+	for _, key := range keys {
+		comments[string(key.Blob)] = key.Comment
+	}
+	assert.Equal(t, "alice@laptop", comments[string(pub.Marshal())])
+	assert.Equal(t, "Server", comments[string(encryptedPub.Marshal())])
+
+	data := []byte("session data")
+	for _, key := range []ssh.PublicKey{pub, encryptedPub} {
+		sig, err := client.Sign(key, data)
+		require.NoError(t, err)
+		assert.NoError(t, key.Verify(data, sig))
+	}
+}
+
+func TestAgent_SignRSAWithFlags(t *testing.T) {
+	source := newFakeSource()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	source.add(t, models.ItemTypeSSHKey, "RSA", models.SSHKeyPayload{PrivateKey: string(pem.EncodeToMemory(block))})
+	pub, err := ssh.NewPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	client := connect(t, New(source, Options{}))
+	_, err = client.List()
+	require.NoError(t, err)
+
+	sig, err := client.SignWithFlags(pub, []byte("data"), agent.SignatureFlagRsaSha256)
+	require.NoError(t, err)
+	assert.Equal(t, ssh.KeyAlgoRSASHA256, sig.Format)
+	assert.NoError(t, pub.Verify([]byte("data"), sig))
+}
+
+func TestAgent_Confirm(t *testing.T) {
+	source := newFakeSource()
+	payload, pub := ed25519Payload(t, "")
+	item := source.add(t, models.ItemTypeSSHKey, "Laptop", payload)
+
+	var asked []Key
+	allow := false
+	client := connect(t, New(source, Options{Confirm: func(key Key) bool {
+		asked = append(asked, key)
+		return allow
+	}}))
+
+	_, err := client.Sign(pub, []byte("data"))
+	require.Error(t, err)
+	require.Len(t, asked, 1)
+	assert.Equal(t, item.ID, asked[0].ID)
+	assert.Equal(t, "Laptop", asked[0].Title)
+	assert.Equal(t, ssh.FingerprintSHA256(pub), asked[0].Fingerprint)
+
+	source.gets = 0
+	allow = true
+	_, err = client.Sign(pub, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, source.gets, "the private key must be downloaded for every signature")
+}
+
+func TestAgent_Lifetime(t *testing.T) {
+	source := newFakeSource()
+	payload, pub := ed25519Payload(t, "")
+	source.add(t, models.ItemTypeSSHKey, "Laptop", payload)
+
+	now := time.Now()
+	a := New(source, Options{Lifetime: time.Minute})
+	a.now = func() time.Time { return now }
+	client := connect(t, a)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	now = now.Add(time.Minute)
+	_, err = client.Sign(pub, []byte("data"))
+	assert.Error(t, err)
+	keys, err = client.List()
+	require.NoError(t, err)
+	assert.Empty(t, keys, "expired keys must not be loaded again")
+}
+
+func TestAgent_SkipsExpiredAndDeletedItems(t *testing.T) {
+	source := newFakeSource()
+	past := time.Now().Add(-time.Hour)
+	payload, _ := ed25519Payload(t, "")
+	source.add(t, models.ItemTypeSSHKey, "Expired", payload).ExpiresAt = &past
+	payload, _ = ed25519Payload(t, "")
+	source.add(t, models.ItemTypeSSHKey, "Deleted", payload).DeletedAt = &past
+	source.add(t, models.ItemTypeSSHKey, "Broken", models.SSHKeyPayload{PrivateKey: "garbage"})
+
+	keys, err := connect(t, New(source, Options{})).List()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestAgent_ListCachesPublicKeys(t *testing.T) {
+	source := newFakeSource()
+	payload, _ := ed25519Payload(t, "")
+	source.add(t, models.ItemTypeSSHKey, "Laptop", payload)
+	client := connect(t, New(source, Options{}))
+
+	for range 3 {
+		_, err := client.List()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, source.gets)
+}
+
+func TestAgent_LockAndReadOnly(t *testing.T) {
+	source := newFakeSource()
+	payload, pub := ed25519Payload(t, "")
+	source.add(t, models.ItemTypeSSHKey, "Laptop", payload)
+	client := connect(t, New(source, Options{}))
+
+	require.NoError(t, client.Lock([]byte("pw")))
+	keys, err := client.List()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	_, err = client.Sign(pub, []byte("data"))
+	assert.Error(t, err)
+
+	assert.Error(t, client.Unlock([]byte("wrong")))
+	require.NoError(t, client.Unlock([]byte("pw")))
+	keys, err = client.List()
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.Error(t, client.Add(agent.AddedKey{PrivateKey: priv}))
+	assert.Error(t, client.RemoveAll())
+}
+
+func TestParseSigner(t *testing.T) {
+	payload, pub := ed25519Payload(t, "pw")
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+	signer, err := ParseSigner(raw)
+	require.NoError(t, err)
+	assert.Equal(t, pub.Marshal(), signer.PublicKey().Marshal())
+
+	payload.Passphrase = "wrong"
+	raw, err = json.Marshal(payload)
+	require.NoError(t, err)
+	_, err = ParseSigner(raw)
+	assert.Error(t, err)
+
+	_, err = ParseSigner([]byte(`{}`))
+	assert.Error(t, err)
+}
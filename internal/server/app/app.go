@@ -11,6 +11,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os/signal"
 	"syscall"
@@ -18,10 +19,14 @@ import (
 
 	"github.com/Pro100x3mal/gophkeeper/internal/server/config"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/handlers"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/metrics"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/middleware"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/ratelimit"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/repositories"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/validators"
+	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/Pro100x3mal/gophkeeper/pkg/crypto"
 	"github.com/Pro100x3mal/gophkeeper/pkg/jwt"
 	"github.com/Pro100x3mal/gophkeeper/pkg/logger"
@@ -32,18 +37,29 @@ import (
 	"go.uber.org/zap"
 )
 
+// Rate limit stores selectable in the configuration.
+const (
+	rateLimitStoreMemory   = "memory"
+	rateLimitStorePostgres = "postgres"
+)
+
 // App represents the main application with all its dependencies.
 type App struct {
 	config       *config.Config
 	logger       *zap.Logger
 	db           *pgxpool.Pool
 	server       *http.Server
+	metrics      *http.Server
+	itemService  *services.ItemService
+	tracing      tracing.ShutdownFunc
+	sharedLimits *ratelimit.PostgresStore
+	limitWindow  time.Duration
 	buildVersion string
 	buildDate    string
 }
 
 // NewApp creates and initializes a new application instance.
-// It sets up the database, runs migrations, initializes services and handlers,
+// It sets up tracing and the database, runs migrations, initializes services and handlers,
 // and configures the HTTP router.
 //
 // Parameters:
@@ -52,7 +68,7 @@ type App struct {
 //   - buildDate: build timestamp
 //
 // Returns the initialized App instance or an error if initialization fails.
-func NewApp(cfg *config.Config, buildVersion, buildDate string) (*App, error) {
+func NewApp(cfg *config.Config, buildVersion, buildDate string) (_ *App, err error) {
 	log, err := logger.New(cfg.LogLevel)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %w", err)
@@ -70,62 +86,188 @@ func NewApp(cfg *config.Config, buildVersion, buildDate string) (*App, error) {
 	if cfg.MasterKey == "" {
 		return nil, errors.New("master encryption key is required")
 	}
+	if cfg.PurgeInterval <= 0 {
+		return nil, errors.New("purge interval must be positive")
+	}
 	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
 		return nil, errors.New("both TLS certificate and key files must be specified or none of them")
 	}
+	if cfg.QuotaMaxItems < 0 || cfg.QuotaMaxBytes < 0 {
+		return nil, errors.New("storage quotas must not be negative")
+	}
+	passwordPolicy := validators.PasswordPolicy{MinLength: cfg.PasswordMinLength, MinClasses: cfg.PasswordMinClasses}
+	if err := passwordPolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid password policy: %w", err)
+	}
+	if cfg.MetricsAddr != "" && cfg.MetricsAddr == cfg.ServerAddr {
+		return nil, errors.New("metrics address must differ from the server address")
+	}
+
+	if cfg.RateLimitStore != rateLimitStoreMemory && cfg.RateLimitStore != rateLimitStorePostgres {
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+	authLimit, err := ratelimit.ParseLimit(cfg.RateLimitAuth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse auth rate limit: %w", err)
+	}
+	itemsLimit, err := ratelimit.ParseLimit(cfg.RateLimitItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse items rate limit: %w", err)
+	}
 
 	masterKey, err := decodeMasterKey(cfg.MasterKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode master key: %w", err)
 	}
 
+	infoHandler := handlers.NewInfoHandler(buildVersion, buildDate)
+
 	ctx := context.Background()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:       cfg.TraceExporter,
+		Endpoint:       cfg.OTLPEndpoint,
+		SampleRatio:    cfg.TraceSampleRatio,
+		ServiceVersion: infoHandler.BuildVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	// Release the exporter if a later step fails; otherwise Run shuts it down.
+	defer func() {
+		if err != nil {
+			if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+				appLogger.Warn("Failed to shut down tracing", zap.Error(shutdownErr))
+			}
+		}
+	}()
+
 	db, err := initDB(ctx, cfg.DatabaseDSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			db.Close()
+		}
+	}()
 	appLogger.Info("Database initialized successfully")
 
 	if err = runMigrations(cfg.DatabaseDSN); err != nil {
-		db.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 	appLogger.Info("Migrations successfully applied")
 
+	migrationVersion, err := latestMigration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
 	jwtGen := jwt.NewGenerator(cfg.JWTSecret, cfg.JWTExpiration)
 
 	userRepo := repositories.NewUserRepository(db)
-	itemRepo := repositories.NewItemRepository(db)
+	itemRepo := repositories.NewItemRepository(db, models.Quota{MaxItems: cfg.QuotaMaxItems, MaxBytes: cfg.QuotaMaxBytes})
 	keyRepo := repositories.NewKeyRepository(db)
+	healthRepo := repositories.NewHealthRepository(db)
 
 	authService := services.NewAuthService(userRepo, jwtGen)
 	itemService := services.NewItemService(keyRepo, itemRepo, masterKey)
+	healthService := services.NewHealthService(healthRepo, masterKey, migrationVersion)
+	adminService := services.NewAdminService(userRepo)
+
+	if err = healthService.EnsureCanary(ctx); err != nil {
+		return nil, fmt.Errorf("failed to store master key canary: %w", err)
+	}
+
+	missingAdmins, err := adminService.SyncAdmins(ctx, cfg.Admins())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync admins: %w", err)
+	}
+	if len(missingAdmins) > 0 {
+		// The usernames stay reserved, nobody can register them while they are configured.
+		appLogger.Warn("Admin usernames are not registered", zap.Strings("usernames", missingAdmins))
+	}
+	authService.SetAdminUsernames(cfg.Admins())
 
 	authValidator := validators.NewAuthValidator()
+	authValidator.SetPasswordPolicy(passwordPolicy)
 	itemValidator := validators.NewItemValidator()
 
-	infoHandler := handlers.NewInfoHandler(buildVersion, buildDate)
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	serverMetrics := metrics.New(infoHandler.BuildVersion, infoHandler.BuildDate)
+	if err = serverMetrics.RegisterPool(db); err != nil {
+		return nil, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+	authService.SetObserver(serverMetrics)
+	itemService.SetObserver(serverMetrics)
+
 	authHandler := handlers.NewAuthHandler(authService, authValidator, appLogger)
 	itemHandler := handlers.NewItemHandler(itemService, itemValidator, appLogger)
+	adminHandler := handlers.NewAdminHandler(adminService, appLogger)
+
+	var (
+		limitStore   ratelimit.Store = ratelimit.NewMemoryStore()
+		sharedLimits *ratelimit.PostgresStore
+	)
+	if cfg.RateLimitStore == rateLimitStorePostgres {
+		sharedLimits = ratelimit.NewPostgresStore(db)
+		limitStore = sharedLimits
+	}
+	limitAuth := middleware.RateLimit(limitStore, "auth", authLimit, appLogger)
+	limitItems := middleware.RateLimit(limitStore, "items", itemsLimit, appLogger)
+	authBody := middleware.BodyLimit(cfg.MaxAuthBodySize)
+	itemsBody := middleware.BodyLimit(cfg.MaxItemBodySize)
 
 	mux := http.NewServeMux()
 
 	// Public endpoints
 	mux.HandleFunc("GET /api/v1/health", infoHandler.HealthCheck)
+	mux.HandleFunc("GET /api/v1/health/live", infoHandler.HealthCheck)
+	mux.HandleFunc("GET /api/v1/health/ready", healthHandler.Readiness)
 	mux.HandleFunc("GET /api/v1/version", infoHandler.Version)
-	mux.HandleFunc("POST /api/v1/register", authHandler.Register)
-	mux.HandleFunc("POST /api/v1/login", authHandler.Login)
+	mux.Handle("POST /api/v1/register", limitAuth(authBody(http.HandlerFunc(authHandler.Register))))
+	mux.Handle("POST /api/v1/login", limitAuth(authBody(http.HandlerFunc(authHandler.Login))))
 
 	// Protected endpoints
 	authMiddleware := middleware.Auth(jwtGen, appLogger)
-	mux.Handle("POST /api/v1/items/", authMiddleware(middleware.RequireUser(itemHandler.CreateItem)))
-	mux.Handle("GET /api/v1/items/", authMiddleware(middleware.RequireUser(itemHandler.ListItems)))
-	mux.Handle("GET /api/v1/items/{id}", authMiddleware(middleware.RequireUser(itemHandler.GetItem)))
-	mux.Handle("PUT /api/v1/items/{id}", authMiddleware(middleware.RequireUser(itemHandler.UpdateItem)))
-	mux.Handle("DELETE /api/v1/items/{id}", authMiddleware(middleware.RequireUser(itemHandler.DeleteItem)))
-
-	// Wrap with Logger middleware
-	handler := middleware.Logger(appLogger)(mux)
+	session := middleware.Session(authService, appLogger)
+	protected := func(next http.Handler) http.Handler {
+		return authMiddleware(limitItems(session(itemsBody(next))))
+	}
+	// Account changes verify the password, so they share the login rate limit.
+	account := func(next middleware.UserHandler) http.Handler {
+		return authMiddleware(limitAuth(session(authBody(middleware.RequireUser(next)))))
+	}
+	admin := func(next middleware.UserHandler) http.Handler {
+		return authMiddleware(limitItems(session(authBody(middleware.RequireAdmin(middleware.RequireUser(next))))))
+	}
+	mux.Handle("POST /api/v1/items/", protected(middleware.RequireUser(itemHandler.CreateItem)))
+	mux.Handle("POST /api/v1/items/batch", protected(middleware.RequireUser(itemHandler.ExecuteBatch)))
+	mux.Handle("GET /api/v1/items/", protected(middleware.RequireUser(itemHandler.ListItems)))
+	mux.Handle("GET /api/v1/items/due", protected(middleware.RequireUser(itemHandler.ListDue)))
+	mux.Handle("GET /api/v1/items/{id}", protected(middleware.RequireUser(itemHandler.GetItem)))
+	mux.Handle("PUT /api/v1/items/{id}", protected(middleware.RequireUser(itemHandler.UpdateItem)))
+	mux.Handle("DELETE /api/v1/items/{id}", protected(middleware.RequireUser(itemHandler.DeleteItem)))
+	mux.Handle("GET /api/v1/trash", protected(middleware.RequireUser(itemHandler.ListTrash)))
+	mux.Handle("DELETE /api/v1/trash", protected(middleware.RequireUser(itemHandler.EmptyTrash)))
+	mux.Handle("POST /api/v1/trash/{id}/restore", protected(middleware.RequireUser(itemHandler.RestoreItem)))
+	mux.Handle("DELETE /api/v1/trash/{id}", protected(middleware.RequireUser(itemHandler.PurgeItem)))
+	mux.Handle("GET /api/v1/usage", protected(middleware.RequireUser(itemHandler.Usage)))
+	mux.Handle("PUT /api/v1/account/password", account(authHandler.ChangePassword))
+	mux.Handle("PUT /api/v1/account/username", account(authHandler.ChangeUsername))
+	mux.Handle("DELETE /api/v1/account", account(authHandler.DeleteAccount))
+
+	// Admin endpoints
+	mux.Handle("GET /api/v1/admin/users", admin(adminHandler.ListUsers))
+	mux.Handle("POST /api/v1/admin/users/{id}/disable", admin(adminHandler.DisableUser))
+	mux.Handle("POST /api/v1/admin/users/{id}/enable", admin(adminHandler.EnableUser))
+	mux.Handle("POST /api/v1/admin/users/{id}/logout", admin(adminHandler.LogoutUser))
+	mux.Handle("DELETE /api/v1/admin/users/{id}", admin(adminHandler.DeleteUser))
+
+	// Wrap with RequestID, Logger, Tracing and Metrics middleware
+	handler := middleware.RequestID()(
+		middleware.Logger(appLogger)(middleware.Tracing()(middleware.Metrics(serverMetrics)(mux))),
+	)
 
 	server := &http.Server{
 		Addr:         cfg.ServerAddr,
@@ -138,11 +280,29 @@ func NewApp(cfg *config.Config, buildVersion, buildDate string) (*App, error) {
 		},
 	}
 
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", serverMetrics.Handler())
+		metricsServer = &http.Server{
+			Addr:         cfg.MetricsAddr,
+			Handler:      metricsMux,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+	}
+
 	return &App{
 		config:       cfg,
 		logger:       appLogger,
 		db:           db,
 		server:       server,
+		metrics:      metricsServer,
+		itemService:  itemService,
+		tracing:      shutdownTracing,
+		sharedLimits: sharedLimits,
+		limitWindow:  max(authLimit.Period, itemsLimit.Period),
 		buildVersion: buildVersion,
 		buildDate:    buildDate,
 	}, nil
@@ -157,7 +317,22 @@ func (a *App) Run() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	defer cancel()
 
-	serverErrCh := make(chan error, 1)
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		a.runPurger(ctx)
+	}()
+
+	serverErrCh := make(chan error, 2)
+	if a.metrics != nil {
+		go func() {
+			a.logger.Info("Starting metrics server", zap.String("address", a.config.MetricsAddr))
+			if err := a.metrics.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErrCh <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+	}
+
 	go func() {
 		if a.config.TLSCertFile != "" && a.config.TLSKeyFile != "" {
 			a.logger.Info("Starting HTTPS server", zap.String("address", a.config.ServerAddr))
@@ -176,6 +351,8 @@ func (a *App) Run() error {
 			a.logger.Error("Server failed unexpectedly", zap.Error(err))
 			serverErr = fmt.Errorf("server failed: %w", err)
 		}
+		// The failure may come from the metrics server, the API server must not keep running alone.
+		_ = a.server.Close()
 	case <-ctx.Done():
 		a.logger.Info("Shutting down HTTP server...", zap.String("address", a.config.ServerAddr))
 
@@ -188,9 +365,26 @@ func (a *App) Run() error {
 		}
 	}
 
+	if a.metrics != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := a.metrics.Shutdown(shutdownCtx); err != nil {
+			a.logger.Error("Failed to shutdown metrics server", zap.Error(err))
+		}
+	}
+
+	cancel()
+	<-purgerDone
+
 	a.logger.Info("Closing database connections...")
 	a.db.Close()
 
+	tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer tracingCancel()
+	if err := a.tracing(tracingCtx); err != nil {
+		a.logger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	if serverErr == nil {
 		a.logger.Info("Server shut down gracefully")
 	}
@@ -200,8 +394,49 @@ func (a *App) Run() error {
 	return serverErr
 }
 
+// runPurger periodically removes items that have been in the trash longer than the
+// configured retention period, along with idle shared rate limit buckets.
+// It returns when the context is cancelled.
+func (a *App) runPurger(ctx context.Context) {
+	ticker := time.NewTicker(a.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.purgeRateLimits(ctx)
+			n, err := a.itemService.PurgeExpired(ctx, a.config.TrashRetention)
+			if err != nil {
+				a.logger.Error("Failed to purge expired items", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				a.logger.Info("Purged expired items from trash", zap.Int64("count", n))
+			}
+		}
+	}
+}
+
+// purgeRateLimits removes the shared rate limit buckets that have refilled completely.
+// Buckets kept in memory are dropped by the store itself.
+func (a *App) purgeRateLimits(ctx context.Context) {
+	if a.sharedLimits == nil {
+		return
+	}
+	n, err := a.sharedLimits.DeleteIdle(ctx, time.Now().Add(-a.limitWindow))
+	if err != nil {
+		a.logger.Error("Failed to delete idle rate limit buckets", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		a.logger.Debug("Deleted idle rate limit buckets", zap.Int64("count", n))
+	}
+}
+
 // initDB initializes a PostgreSQL connection pool with configured parameters.
-// Sets up connection pooling with health checks and connection lifecycle limits.
+// Sets up connection pooling with health checks, connection lifecycle limits and query tracing.
 func initDB(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
@@ -212,6 +447,7 @@ func initDB(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	poolConfig.MaxConnLifetime = 1 * time.Hour
 	poolConfig.MaxConnIdleTime = 30 * time.Minute
 	poolConfig.HealthCheckPeriod = 1 * time.Minute
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -252,6 +488,30 @@ func runMigrations(dsn string) error {
 	return nil
 }
 
+// latestMigration returns the version of the last migration embedded in the binary.
+func latestMigration() (uint, error) {
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iofs source: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read first migration: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
+
 // decodeMasterKey decodes and validates a base64-encoded master encryption key.
 // Ensures the key has the correct length for AES-256 encryption.
 func decodeMasterKey(masterKey string) ([]byte, error) {
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+DELETE FROM items WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_items_deleted_at;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+DROP INDEX IF EXISTS idx_items_expires_at;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS expires_at;
+
+COMMIT;
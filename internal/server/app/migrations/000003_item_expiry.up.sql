@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_items_expires_at ON items (expires_at) WHERE expires_at IS NOT NULL;
+
+COMMIT;
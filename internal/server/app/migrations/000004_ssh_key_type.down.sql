@@ -0,0 +1,12 @@
+BEGIN TRANSACTION;
+
+DELETE FROM items
+WHERE type = 'ssh-key';
+
+ALTER TABLE items
+    DROP CONSTRAINT IF EXISTS items_type_check;
+
+ALTER TABLE items
+    ADD CONSTRAINT items_type_check CHECK (type IN ('credential', 'text', 'binary', 'card'));
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE items
+    DROP CONSTRAINT IF EXISTS items_type_check;
+
+ALTER TABLE items
+    ADD CONSTRAINT items_type_check CHECK (type IN ('credential', 'text', 'binary', 'card', 'ssh-key'));
+
+COMMIT;
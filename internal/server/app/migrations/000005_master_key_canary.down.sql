@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS master_key_canary;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS master_key_canary
+(
+    id              BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    value_encrypted BYTEA NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
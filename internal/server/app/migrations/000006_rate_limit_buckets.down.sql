@@ -0,0 +1,5 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS rate_limit_buckets;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS user_usage
+(
+    user_id    UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    items      BIGINT                   NOT NULL DEFAULT 0,
+    bytes      BIGINT                   NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO user_usage (user_id, items, bytes)
+SELECT i.user_id, COUNT(i.id), COALESCE(SUM(OCTET_LENGTH(d.data_encrypted)), 0)
+FROM items i
+         LEFT JOIN encrypted_data d ON d.item_id = i.id
+WHERE i.deleted_at IS NULL
+GROUP BY i.user_id
+ON CONFLICT (user_id) DO NOTHING;
+
+COMMIT;
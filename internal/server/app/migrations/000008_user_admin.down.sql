@@ -0,0 +1,8 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tokens_valid_after,
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS role;
+
+COMMIT;
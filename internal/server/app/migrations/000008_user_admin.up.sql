@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role               VARCHAR(16) NOT NULL DEFAULT 'user'
+        CHECK (role IN ('user', 'admin')),
+    ADD COLUMN IF NOT EXISTS disabled_at        TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS tokens_valid_after TIMESTAMP WITH TIME ZONE;
+
+COMMIT;
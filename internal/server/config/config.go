@@ -7,6 +7,8 @@ package config
 import (
 	"flag"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -30,6 +32,53 @@ type Config struct {
 	TLSKeyFile string
 	// MasterKey is the base64-encoded master encryption key.
 	MasterKey string
+	// TrashRetention is how long deleted items are kept before they are purged.
+	TrashRetention time.Duration
+	// PurgeInterval is how often expired items are purged from the trash.
+	PurgeInterval time.Duration
+	// MetricsAddr is the address to serve Prometheus metrics on (optional, disabled when empty).
+	MetricsAddr string
+	// TraceExporter selects where OpenTelemetry spans are sent: "otlp", "stdout" or empty to disable tracing.
+	TraceExporter string
+	// OTLPEndpoint is the URL of the OTLP/HTTP collector (optional, OTEL_EXPORTER_OTLP_* variables apply when empty).
+	OTLPEndpoint string
+	// TraceSampleRatio is the fraction of new traces that are recorded.
+	TraceSampleRatio float64
+	// RateLimitAuth limits registration and login requests per client IP address, e.g. "20/m" ("0" disables).
+	RateLimitAuth string
+	// RateLimitItems limits item and trash requests per user, e.g. "600/m" ("0" disables).
+	RateLimitItems string
+	// RateLimitStore selects where rate limit buckets are kept: "memory" or "postgres" to share them between replicas.
+	RateLimitStore string
+	// MaxAuthBodySize is the maximum size of registration and login request bodies in bytes.
+	MaxAuthBodySize int64
+	// MaxItemBodySize is the maximum size of item request bodies in bytes, including batches.
+	MaxItemBodySize int64
+	// QuotaMaxItems is the maximum number of items per user outside the trash (0, the default, disables).
+	QuotaMaxItems int64
+	// QuotaMaxBytes is the maximum size of encrypted data of items per user outside the trash in bytes (0, the default, disables).
+	QuotaMaxBytes int64
+	// PasswordMinLength is the minimum number of characters in new passwords.
+	PasswordMinLength int
+	// PasswordMinClasses is the minimum number of character classes (lowercase, uppercase, digits, other) in new passwords.
+	PasswordMinClasses int
+	// AdminUsernames is the comma-separated list of users with the admin role, the role is synced on start.
+	AdminUsernames string
+}
+
+// Admins returns the usernames listed in AdminUsernames without blanks and duplicates.
+func (c *Config) Admins() []string {
+	var admins []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(c.AdminUsernames, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		admins = append(admins, name)
+	}
+	return admins
 }
 
 // Load reads configuration from environment variables and command-line flags.
@@ -50,6 +99,23 @@ func Load() (*Config, error) {
 	flag.StringVar(&cfg.TLSKeyFile, "tls-key", getEnv("TLS_KEY_FILE", ""), "TLS key file")
 	flag.StringVar(&cfg.MasterKey, "master-key", getEnv("MASTER_KEY", ""), "Master encryption key in base64 format")
 	flag.DurationVar(&cfg.JWTExpiration, "jwt-exp", getEnvDuration("JWT_EXPIRATION", 24*time.Hour), "JWT expiration time")
+	flag.DurationVar(&cfg.TrashRetention, "trash-retention", getEnvDuration("TRASH_RETENTION", 30*24*time.Hour), "How long deleted items are kept in the trash")
+	flag.DurationVar(&cfg.PurgeInterval, "purge-interval", getEnvDuration("PURGE_INTERVAL", time.Hour), "How often expired items are purged from the trash")
+	flag.StringVar(&cfg.MetricsAddr, "metrics-addr", getEnv("METRICS_ADDR", ""), "Address of the Prometheus metrics listener (disabled if empty)")
+	flag.StringVar(&cfg.TraceExporter, "trace-exporter", getEnv("TRACE_EXPORTER", ""), "Trace exporter: otlp, stdout or empty to disable tracing")
+	flag.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", getEnv("OTLP_ENDPOINT", ""), "URL of the OTLP/HTTP trace collector")
+	flag.Float64Var(&cfg.TraceSampleRatio, "trace-sample-ratio", getEnvFloat("TRACE_SAMPLE_RATIO", 1), "Fraction of new traces that are recorded")
+
+	flag.StringVar(&cfg.RateLimitAuth, "rate-limit-auth", getEnv("RATE_LIMIT_AUTH", "20/m"), "Rate limit of registration and login per client IP (0 to disable)")
+	flag.StringVar(&cfg.RateLimitItems, "rate-limit-items", getEnv("RATE_LIMIT_ITEMS", "600/m"), "Rate limit of item and trash requests per user (0 to disable)")
+	flag.StringVar(&cfg.RateLimitStore, "rate-limit-store", getEnv("RATE_LIMIT_STORE", "memory"), "Rate limit store: memory or postgres")
+	flag.Int64Var(&cfg.MaxAuthBodySize, "max-auth-body", getEnvInt64("MAX_AUTH_BODY_SIZE", 4<<10), "Maximum size of registration and login request bodies in bytes")
+	flag.Int64Var(&cfg.MaxItemBodySize, "max-item-body", getEnvInt64("MAX_ITEM_BODY_SIZE", 32<<20), "Maximum size of item request bodies in bytes")
+	flag.Int64Var(&cfg.QuotaMaxItems, "quota-max-items", getEnvInt64("QUOTA_MAX_ITEMS", 0), "Maximum number of items per user (0 to disable)")
+	flag.Int64Var(&cfg.QuotaMaxBytes, "quota-max-bytes", getEnvInt64("QUOTA_MAX_BYTES", 0), "Maximum size of encrypted data per user in bytes (0 to disable)")
+	flag.IntVar(&cfg.PasswordMinLength, "password-min-length", int(getEnvInt64("PASSWORD_MIN_LENGTH", 8)), "Minimum length of new passwords")
+	flag.IntVar(&cfg.PasswordMinClasses, "password-min-classes", int(getEnvInt64("PASSWORD_MIN_CLASSES", 2)), "Minimum number of character classes in new passwords (1-4)")
+	flag.StringVar(&cfg.AdminUsernames, "admins", getEnv("ADMIN_USERNAMES", ""), "Comma-separated usernames of admins")
 
 	flag.Parse()
 
@@ -75,3 +141,25 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getEnvFloat retrieves a floating-point number from an environment variable or returns a default value.
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, ok := os.LookupEnv(key); ok {
+		f, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt64 retrieves an integer from an environment variable or returns a default value.
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value, ok := os.LookupEnv(key); ok {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
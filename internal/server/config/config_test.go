@@ -65,6 +65,64 @@ func TestGetEnvDuration(t *testing.T) {
 	}
 }
 
+func TestGetEnvFloat(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue float64
+		envValue     string
+		setEnv       bool
+		expected     float64
+	}{
+		{"Valid float", "TEST_FLOAT", 1, "0.25", true, 0.25},
+		{"Invalid float", "TEST_FLOAT_INVALID", 1, "half", true, 1},
+		{"Not set", "TEST_FLOAT_NOTSET", 0.5, "", false, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				os.Setenv(tt.key, tt.envValue)
+				defer os.Unsetenv(tt.key)
+			} else {
+				os.Unsetenv(tt.key)
+			}
+
+			result := getEnvFloat(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetEnvInt64(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue int64
+		envValue     string
+		setEnv       bool
+		expected     int64
+	}{
+		{"Valid integer", "TEST_INT", 1, "4096", true, 4096},
+		{"Invalid integer", "TEST_INT_INVALID", 1, "4k", true, 1},
+		{"Not set", "TEST_INT_NOTSET", 7, "", false, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				os.Setenv(tt.key, tt.envValue)
+				defer os.Unsetenv(tt.key)
+			} else {
+				os.Unsetenv(tt.key)
+			}
+
+			result := getEnvInt64(tt.key, tt.defaultValue)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestConfig_Struct(t *testing.T) {
 	cfg := &Config{
 		LogLevel:      "warn",
@@ -86,3 +144,22 @@ func TestConfig_Struct(t *testing.T) {
 	assert.Equal(t, "/path/to/key", cfg.TLSKeyFile)
 	assert.Equal(t, "master-key", cfg.MasterKey)
 }
+
+func TestConfig_Admins(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{name: "empty", value: "", expected: nil},
+		{name: "single", value: "alice", expected: []string{"alice"}},
+		{name: "blanks and duplicates", value: " alice, ,bob,alice ", expected: []string{"alice", "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{AdminUsernames: tt.value}
+			assert.Equal(t, tt.expected, cfg.Admins())
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ChangePasswordRequest represents a request to change the password of the authenticated user.
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangeUsernameRequest represents a request to rename the account of the authenticated user.
+type ChangeUsernameRequest struct {
+	Username string `json:"username"`
+}
+
+// DeleteAccountRequest represents a request to delete the account of the authenticated user.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// userResponse represents a single user.
+type userResponse struct {
+	User *models.User `json:"user"`
+}
+
+// ChangePassword handles requests to change the password of the authenticated user.
+// All tokens of the user are revoked, the response carries a new token.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if !isJSON(r) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := h.validator.ValidatePassword(req.OldPassword); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.authSvc.GetUser(r.Context(), userID)
+	if err != nil {
+		h.writeAccountError(w, r, "failed to get user", err)
+		return
+	}
+	if err = h.validator.ValidateNewPassword(req.NewPassword, user.Username); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	token, err := h.authSvc.ChangePassword(r.Context(), userID, req.OldPassword, req.NewPassword)
+	if err != nil {
+		h.writeAccountError(w, r, "failed to change password", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AuthResponse{
+		Token:  token,
+		UserID: userID,
+	})
+}
+
+// ChangeUsername handles requests to rename the account of the authenticated user.
+func (h *AuthHandler) ChangeUsername(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if !isJSON(r) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var req ChangeUsernameRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := h.validator.ValidateUsername(req.Username); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	user, err := h.authSvc.ChangeUsername(r.Context(), userID, req.Username)
+	if err != nil {
+		h.writeAccountError(w, r, "failed to change username", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, userResponse{User: user})
+}
+
+// DeleteAccount handles requests to delete the account of the authenticated user with all items and keys.
+// The password is required again to confirm the deletion.
+func (h *AuthHandler) DeleteAccount(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if !isJSON(r) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := h.validator.ValidatePassword(req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authSvc.DeleteAccount(r.Context(), userID, req.Password); err != nil {
+		h.writeAccountError(w, r, "failed to delete account", err)
+		return
+	}
+
+	h.logger.Info("account deleted", zap.String("user_id", userID.String()), requestid.Field(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAccountError responds to a failed account change.
+// A wrong password is 403 Forbidden rather than 401, the token of the request is still valid.
+func (h *AuthHandler) writeAccountError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
+		http.Error(w, "invalid password", http.StatusForbidden)
+	case errors.Is(err, models.ErrUserAlreadyExists):
+		http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+	case errors.Is(err, services.ErrAdminRename):
+		http.Error(w, services.ErrAdminRename.Error(), http.StatusConflict)
+	case errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	default:
+		h.logger.Error(msg, zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
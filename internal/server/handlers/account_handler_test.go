@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/validators"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// accountRequest builds a JSON request to an account endpoint.
+func accountRequest(method, path string, v any) *http.Request {
+	body, _ := json.Marshal(v)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestAuthHandler_ChangePassword_Success(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+
+	userID := uuid.New()
+	mockService.On("GetUser", mock.Anything, userID).Return(&models.User{ID: userID, Username: "alice"}, nil)
+	mockService.On("ChangePassword", mock.Anything, userID, "old", "N3w-Passw0rd").Return("new-token", nil)
+
+	w := httptest.NewRecorder()
+	handler.ChangePassword(w, accountRequest(http.MethodPut, "/api/v1/account/password",
+		ChangePasswordRequest{OldPassword: "old", NewPassword: "N3w-Passw0rd"}), userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp AuthResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "new-token", resp.Token)
+	assert.Equal(t, userID, resp.UserID)
+}
+
+func TestAuthHandler_ChangePassword_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      ChangePasswordRequest
+		err      error
+		wantCode int
+	}{
+		{name: "wrong password", req: ChangePasswordRequest{OldPassword: "bad", NewPassword: "N3w-Passw0rd"}, err: services.ErrInvalidCredentials, wantCode: http.StatusForbidden},
+		{name: "service error", req: ChangePasswordRequest{OldPassword: "old", NewPassword: "N3w-Passw0rd"}, err: errors.New("db down"), wantCode: http.StatusInternalServerError},
+		{name: "empty new password", req: ChangePasswordRequest{OldPassword: "old"}, wantCode: http.StatusBadRequest},
+		{name: "weak new password", req: ChangePasswordRequest{OldPassword: "old", NewPassword: "qwerty"}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+			userID := uuid.New()
+			mockService.On("GetUser", mock.Anything, userID).Return(&models.User{ID: userID, Username: "alice"}, nil)
+			mockService.On("ChangePassword", mock.Anything, userID, tt.req.OldPassword, tt.req.NewPassword).Return("", tt.err)
+
+			w := httptest.NewRecorder()
+			handler.ChangePassword(w, accountRequest(http.MethodPut, "/api/v1/account/password", tt.req), userID)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword_ContainsUsername(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+	userID := uuid.New()
+	mockService.On("GetUser", mock.Anything, userID).Return(&models.User{ID: userID, Username: "alice"}, nil)
+
+	w := httptest.NewRecorder()
+	handler.ChangePassword(w, accountRequest(http.MethodPut, "/api/v1/account/password",
+		ChangePasswordRequest{OldPassword: "old", NewPassword: "Alice-2024!"}), userID)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp models.ValidationError
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, map[string][]string{"new_password": {"must not contain the username"}}, resp.Fields)
+	mockService.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_ChangePassword_UserNotFound(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+	userID := uuid.New()
+	mockService.On("GetUser", mock.Anything, userID).Return(nil, models.ErrUserNotFound)
+
+	w := httptest.NewRecorder()
+	handler.ChangePassword(w, accountRequest(http.MethodPut, "/api/v1/account/password",
+		ChangePasswordRequest{OldPassword: "old", NewPassword: "N3w-Passw0rd"}), userID)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthHandler_ChangeUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		user     *models.User
+		err      error
+		wantCode int
+	}{
+		{name: "success", username: "newname", user: &models.User{Username: "newname"}, wantCode: http.StatusOK},
+		{name: "taken", username: "taken", err: models.ErrUserAlreadyExists, wantCode: http.StatusConflict},
+		{name: "admin", username: "newname", err: services.ErrAdminRename, wantCode: http.StatusConflict},
+		{name: "admin username", username: "boss", err: services.ErrUsernameReserved, wantCode: http.StatusConflict},
+		{name: "empty", username: "", wantCode: http.StatusBadRequest},
+		{name: "reserved", username: "Admin", wantCode: http.StatusBadRequest},
+		{name: "invalid characters", username: "new name", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+			userID := uuid.New()
+			if tt.user != nil {
+				mockService.On("ChangeUsername", mock.Anything, userID, tt.username).Return(tt.user, nil)
+			} else {
+				mockService.On("ChangeUsername", mock.Anything, userID, tt.username).Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ChangeUsername(w, accountRequest(http.MethodPut, "/api/v1/account/username",
+				ChangeUsernameRequest{Username: tt.username}), userID)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				var resp userResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "newname", resp.User.Username)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_DeleteAccount(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+	userID := uuid.New()
+	mockService.On("DeleteAccount", mock.Anything, userID, "password").Return(nil)
+
+	w := httptest.NewRecorder()
+	handler.DeleteAccount(w, accountRequest(http.MethodDelete, "/api/v1/account", DeleteAccountRequest{Password: "password"}), userID)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_DeleteAccount_WrongPassword(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+	userID := uuid.New()
+	mockService.On("DeleteAccount", mock.Anything, userID, "wrong").Return(services.ErrInvalidCredentials)
+
+	w := httptest.NewRecorder()
+	handler.DeleteAccount(w, accountRequest(http.MethodDelete, "/api/v1/account", DeleteAccountRequest{Password: "wrong"}), userID)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthHandler_DeleteAccount_MissingPassword(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.DeleteAccount(w, accountRequest(http.MethodDelete, "/api/v1/account", map[string]string{}), uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "DeleteAccount", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AdminSvc defines the user management service contract.
+type AdminSvc interface {
+	ListUsers(ctx context.Context) ([]*models.UserInfo, error)
+	DisableUser(ctx context.Context, adminID, userID uuid.UUID) error
+	EnableUser(ctx context.Context, userID uuid.UUID) error
+	LogoutUser(ctx context.Context, userID uuid.UUID) error
+	DeleteUser(ctx context.Context, adminID, userID uuid.UUID) error
+}
+
+// AdminHandler handles HTTP requests of the admin API.
+// The routes must be guarded by the Session and RequireAdmin middleware.
+type AdminHandler struct {
+	adminSvc AdminSvc
+	logger   *zap.Logger
+}
+
+// NewAdminHandler creates a new admin handler instance.
+func NewAdminHandler(adminSvc AdminSvc, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminSvc: adminSvc,
+		logger:   logger.Named("admin_handler"),
+	}
+}
+
+// usersResponse represents a list of users.
+type usersResponse struct {
+	Users []*models.UserInfo `json:"users"`
+}
+
+// ListUsers handles requests to list all users with their storage usage.
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request, _ uuid.UUID) {
+	users, err := h.adminSvc.ListUsers(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list users", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, usersResponse{Users: users})
+}
+
+// DisableUser handles requests to disable a user and revoke their tokens.
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request, adminID uuid.UUID) {
+	h.userAction(w, r, adminID, "disabled", func(ctx context.Context, userID uuid.UUID) error {
+		return h.adminSvc.DisableUser(ctx, adminID, userID)
+	})
+}
+
+// EnableUser handles requests to re-enable a disabled user.
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request, adminID uuid.UUID) {
+	h.userAction(w, r, adminID, "enabled", h.adminSvc.EnableUser)
+}
+
+// LogoutUser handles requests to revoke all tokens issued to a user.
+func (h *AdminHandler) LogoutUser(w http.ResponseWriter, r *http.Request, adminID uuid.UUID) {
+	h.userAction(w, r, adminID, "logged out", h.adminSvc.LogoutUser)
+}
+
+// DeleteUser handles requests to remove a user together with their keys and items.
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request, adminID uuid.UUID) {
+	h.userAction(w, r, adminID, "deleted", func(ctx context.Context, userID uuid.UUID) error {
+		return h.adminSvc.DeleteUser(ctx, adminID, userID)
+	})
+}
+
+// userAction applies an action to the user from the request path and responds with 204 No Content.
+// Successful actions are logged with the admin and the target user for auditing.
+func (h *AdminHandler) userAction(w http.ResponseWriter, r *http.Request, adminID uuid.UUID, done string,
+	action func(ctx context.Context, userID uuid.UUID) error) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err = action(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		case errors.Is(err, services.ErrSelfAdminAction):
+			http.Error(w, services.ErrSelfAdminAction.Error(), http.StatusConflict)
+		default:
+			h.logger.Error("failed to update user", zap.String("user_id", userID.String()),
+				zap.Error(err), requestid.Field(r.Context()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.logger.Info("user "+done, zap.String("admin_id", adminID.String()),
+		zap.String("user_id", userID.String()), requestid.Field(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockAdminService is a mock implementation of AdminSvc
+type MockAdminService struct {
+	mock.Mock
+}
+
+func (m *MockAdminService) ListUsers(ctx context.Context) ([]*models.UserInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UserInfo), args.Error(1)
+}
+
+func (m *MockAdminService) DisableUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	args := m.Called(ctx, adminID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) EnableUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) LogoutUser(ctx context.Context, userID uuid.UUID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAdminService) DeleteUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	args := m.Called(ctx, adminID, userID)
+	return args.Error(0)
+}
+
+// adminRequest builds a request for a user action with the user ID in the path.
+func adminRequest(method, id string) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/admin/users/"+id, nil)
+	req.SetPathValue("id", id)
+	return req
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService, zap.NewNop())
+
+	users := []*models.UserInfo{{
+		User:  models.User{ID: uuid.New(), Username: "alice", Role: models.RoleAdmin},
+		Usage: models.Usage{Items: 3, Bytes: 100},
+	}}
+	mockService.On("ListUsers", mock.Anything).Return(users, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListUsers(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil), uuid.New())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usersResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, "alice", resp.Users[0].Username)
+	assert.Equal(t, models.RoleAdmin, resp.Users[0].Role)
+	assert.Equal(t, int64(3), resp.Users[0].Usage.Items)
+}
+
+func TestAdminHandler_ListUsers_Error(t *testing.T) {
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService, zap.NewNop())
+	mockService.On("ListUsers", mock.Anything).Return(nil, errors.New("db down"))
+
+	w := httptest.NewRecorder()
+	handler.ListUsers(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/users", nil), uuid.New())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAdminHandler_UserActions(t *testing.T) {
+	adminID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		method   string
+		setup    func(m *MockAdminService, err error)
+		call     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID)
+		err      error
+		wantCode int
+	}{
+		{
+			name:   "disable",
+			method: http.MethodPost,
+			setup: func(m *MockAdminService, err error) {
+				m.On("DisableUser", mock.Anything, adminID, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.DisableUser },
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:   "disable self",
+			method: http.MethodPost,
+			setup: func(m *MockAdminService, err error) {
+				m.On("DisableUser", mock.Anything, adminID, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.DisableUser },
+			err:      services.ErrSelfAdminAction,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:   "enable missing user",
+			method: http.MethodPost,
+			setup: func(m *MockAdminService, err error) {
+				m.On("EnableUser", mock.Anything, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.EnableUser },
+			err:      models.ErrUserNotFound,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:   "logout",
+			method: http.MethodPost,
+			setup: func(m *MockAdminService, err error) {
+				m.On("LogoutUser", mock.Anything, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.LogoutUser },
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			setup: func(m *MockAdminService, err error) {
+				m.On("DeleteUser", mock.Anything, adminID, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.DeleteUser },
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:   "delete failed",
+			method: http.MethodDelete,
+			setup: func(m *MockAdminService, err error) {
+				m.On("DeleteUser", mock.Anything, adminID, userID).Return(err)
+			},
+			call:     func(h *AdminHandler) func(http.ResponseWriter, *http.Request, uuid.UUID) { return h.DeleteUser },
+			err:      errors.New("db down"),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAdminService)
+			handler := NewAdminHandler(mockService, zap.NewNop())
+			tt.setup(mockService, tt.err)
+
+			w := httptest.NewRecorder()
+			tt.call(handler)(w, adminRequest(tt.method, userID.String()), adminID)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_InvalidUserID(t *testing.T) {
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	handler.DeleteUser(w, adminRequest(http.MethodDelete, "not-a-uuid"), uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"net/http"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
@@ -16,11 +16,19 @@ import (
 type AuthSvc interface {
 	Register(ctx context.Context, username, password string) (*models.User, string, error)
 	Login(ctx context.Context, username, password string) (*models.User, string, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) (string, error)
+	ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (*models.User, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error
 }
 
 // AuthValidator defines the contract for validating authentication credentials.
 type AuthValidator interface {
 	ValidateCredentials(login, password string) error
+	ValidateRegistration(username, password string) error
+	ValidateUsername(username string) error
+	ValidateNewPassword(password, username string) error
+	ValidatePassword(password string) error
 }
 
 // AuthHandler handles HTTP requests for user authentication.
@@ -66,13 +74,13 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
-	if err := h.validator.ValidateCredentials(req.Username, req.Password); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := h.validator.ValidateRegistration(req.Username, req.Password); err != nil {
+		writeValidationError(w, err)
 		return
 	}
 
@@ -82,7 +90,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
 			return
 		}
-		h.logger.Error("failed to register user", zap.Error(err))
+		h.logger.Error("failed to register user", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -102,8 +110,8 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -118,7 +126,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
-		h.logger.Error("failed to login user", zap.Error(err))
+		if errors.Is(err, models.ErrUserDisabled) {
+			http.Error(w, models.ErrUserDisabled.Error(), http.StatusForbidden)
+			return
+		}
+		h.logger.Error("failed to login user", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
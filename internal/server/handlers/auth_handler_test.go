@@ -40,6 +40,32 @@ func (m *MockAuthService) Login(ctx context.Context, username, password string)
 	return args.Get(0).(*models.User), args.String(1), args.Error(2)
 }
 
+func (m *MockAuthService) GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) (string, error) {
+	args := m.Called(ctx, userID, oldPassword, newPassword)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (*models.User, error) {
+	args := m.Called(ctx, userID, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) error {
+	args := m.Called(ctx, userID, password)
+	return args.Error(0)
+}
+
 func TestNewAuthHandler(t *testing.T) {
 	mockService := new(MockAuthService)
 	validator := validators.NewAuthValidator()
@@ -61,12 +87,12 @@ func TestAuthHandler_Register_Success(t *testing.T) {
 	token := "test-token"
 	user := &models.User{ID: userID, Username: "testuser"}
 
-	mockService.On("Register", mock.Anything, "testuser", "password123").
+	mockService.On("Register", mock.Anything, "testuser", "S3cure-Passw0rd").
 		Return(user, token, nil)
 
 	reqBody := RegisterRequest{
 		Username: "testuser",
-		Password: "password123",
+		Password: "S3cure-Passw0rd",
 	}
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
@@ -92,7 +118,7 @@ func TestAuthHandler_Register_MissingContentType(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	handler := NewAuthHandler(mockService, validator, logger)
 
-	reqBody := RegisterRequest{Username: "testuser", Password: "password123"}
+	reqBody := RegisterRequest{Username: "testuser", Password: "S3cure-Passw0rd"}
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
 	// No Content-Type header set
@@ -124,7 +150,7 @@ func TestAuthHandler_Register_EmptyUsername(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	handler := NewAuthHandler(mockService, validator, logger)
 
-	reqBody := RegisterRequest{Username: "", Password: "password123"}
+	reqBody := RegisterRequest{Username: "", Password: "S3cure-Passw0rd"}
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -152,16 +178,105 @@ func TestAuthHandler_Register_EmptyPassword(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestAuthHandler_Register_PolicyViolations(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        RegisterRequest
+		wantFields map[string][]string
+	}{
+		{
+			name:       "common password",
+			req:        RegisterRequest{Username: "testuser", Password: "password123"},
+			wantFields: map[string][]string{"password": {"is too common"}},
+		},
+		{
+			name: "short single-class password",
+			req:  RegisterRequest{Username: "testuser", Password: "xkcdfb"},
+			wantFields: map[string][]string{"password": {
+				"must be at least 8 characters long",
+				"must contain at least 2 of lowercase letters, uppercase letters, digits and other characters",
+			}},
+		},
+		{
+			name:       "password contains username",
+			req:        RegisterRequest{Username: "testuser", Password: "my-TestUser-42"},
+			wantFields: map[string][]string{"password": {"must not contain the username"}},
+		},
+		{
+			name: "invalid username",
+			req:  RegisterRequest{Username: "_x", Password: "S3cure-Passw0rd"},
+			wantFields: map[string][]string{"username": {
+				"must be between 3 and 255 characters long",
+				"must start with a letter or digit",
+			}},
+		},
+		{
+			name:       "reserved username",
+			req:        RegisterRequest{Username: "Root", Password: "S3cure-Passw0rd"},
+			wantFields: map[string][]string{"username": {"is reserved"}},
+		},
+		{
+			name: "both fields",
+			req:  RegisterRequest{Username: "test user", Password: ""},
+			wantFields: map[string][]string{
+				"username": {"may contain only letters, digits, dots, underscores and hyphens"},
+				"password": {"is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			handler := NewAuthHandler(mockService, validators.NewAuthValidator(), zap.NewNop())
+
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.Register(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var resp models.ValidationError
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, "validation failed", resp.Message)
+			assert.Equal(t, tt.wantFields, resp.Fields)
+			mockService.AssertNotCalled(t, "Register", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthHandler_Register_CustomPolicy(t *testing.T) {
+	mockService := new(MockAuthService)
+	validator := validators.NewAuthValidator()
+	validator.SetPasswordPolicy(validators.PasswordPolicy{MinLength: 16, MinClasses: 4})
+	handler := NewAuthHandler(mockService, validator, zap.NewNop())
+
+	body, _ := json.Marshal(RegisterRequest{Username: "testuser", Password: "S3cure-Passw0rd"})
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Register(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp models.ValidationError
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, []string{"must be at least 16 characters long"}, resp.Fields["password"])
+}
+
 func TestAuthHandler_Register_UserAlreadyExists(t *testing.T) {
 	mockService := new(MockAuthService)
 	validator := validators.NewAuthValidator()
 	logger, _ := zap.NewDevelopment()
 	handler := NewAuthHandler(mockService, validator, logger)
 
-	mockService.On("Register", mock.Anything, "existinguser", "password123").
+	mockService.On("Register", mock.Anything, "existinguser", "S3cure-Passw0rd").
 		Return(nil, "", models.ErrUserAlreadyExists)
 
-	reqBody := RegisterRequest{Username: "existinguser", Password: "password123"}
+	reqBody := RegisterRequest{Username: "existinguser", Password: "S3cure-Passw0rd"}
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -179,10 +294,10 @@ func TestAuthHandler_Register_InternalError(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	handler := NewAuthHandler(mockService, validator, logger)
 
-	mockService.On("Register", mock.Anything, "testuser", "password123").
+	mockService.On("Register", mock.Anything, "testuser", "S3cure-Passw0rd").
 		Return(nil, "", errors.New("database error"))
 
-	reqBody := RegisterRequest{Username: "testuser", Password: "password123"}
+	reqBody := RegisterRequest{Username: "testuser", Password: "S3cure-Passw0rd"}
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -247,6 +362,26 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestAuthHandler_Login_Disabled(t *testing.T) {
+	mockService := new(MockAuthService)
+	validator := validators.NewAuthValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewAuthHandler(mockService, validator, logger)
+
+	mockService.On("Login", mock.Anything, "testuser", "password123").
+		Return(nil, "", models.ErrUserDisabled)
+
+	body, _ := json.Marshal(LoginRequest{Username: "testuser", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Login(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "user is disabled")
+}
+
 func TestAuthHandler_Login_EmptyUsername(t *testing.T) {
 	mockService := new(MockAuthService)
 	validator := validators.NewAuthValidator()
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// HealthSvc defines the readiness check contract.
+type HealthSvc interface {
+	Readiness(ctx context.Context) *models.ReadinessReport
+}
+
+// HealthHandler handles the readiness probe.
+type HealthHandler struct {
+	healthSvc HealthSvc
+}
+
+// NewHealthHandler creates a new health handler instance.
+func NewHealthHandler(healthSvc HealthSvc) *HealthHandler {
+	return &HealthHandler{healthSvc: healthSvc}
+}
+
+// Readiness reports whether the server can serve requests.
+// Responds with 200 when every component is up and with 503 otherwise,
+// the body holds the status and latency of each component in both cases.
+func (hh *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := hh.healthSvc.Readiness(r.Context())
+	status := http.StatusOK
+	if report.Status != models.ReadinessReady {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
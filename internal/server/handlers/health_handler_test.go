@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHealthSvc struct {
+	report *models.ReadinessReport
+}
+
+func (s *stubHealthSvc) Readiness(context.Context) *models.ReadinessReport {
+	return s.report
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *models.ReadinessReport
+		status int
+	}{
+		{
+			name: "ready",
+			report: &models.ReadinessReport{Status: models.ReadinessReady, Components: map[string]models.ComponentStatus{
+				"database": {Status: models.ComponentUp, LatencyMS: 1.5},
+			}},
+			status: http.StatusOK,
+		},
+		{
+			name: "not ready",
+			report: &models.ReadinessReport{Status: models.ReadinessNotReady, Components: map[string]models.ComponentStatus{
+				"database": {Status: models.ComponentDown, LatencyMS: 2000, Error: "context deadline exceeded"},
+			}},
+			status: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(&stubHealthSvc{report: tt.report})
+			w := httptest.NewRecorder()
+
+			handler.Readiness(w, httptest.NewRequest(http.MethodGet, "/api/v1/health/ready", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			var got models.ReadinessReport
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+			assert.Equal(t, *tt.report, got)
+		})
+	}
+}
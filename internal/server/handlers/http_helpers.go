@@ -5,15 +5,62 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
 )
 
+// errTrailingData is returned when a request body contains more than one JSON value.
+var errTrailingData = errors.New("unexpected data after JSON value")
+
 // isJSON checks if the request Content-Type header indicates JSON.
 func isJSON(r *http.Request) bool {
 	return strings.Contains(r.Header.Get("Content-Type"), "application/json")
 }
 
+// decodeJSON decodes the request body into v.
+// Unknown fields and data after the JSON value are rejected.
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return errTrailingData
+	}
+	return nil
+}
+
+// writeDecodeError responds to a request whose body could not be decoded.
+// Bodies over the limit set by middleware.BodyLimit get 413 Request Entity Too Large, other errors 400 Bad Request.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+}
+
+// writeValidationError responds with 400 Bad Request.
+// A *models.ValidationError is written as JSON to report the violated rules by field.
+func writeValidationError(w http.ResponseWriter, err error) {
+	var verr *models.ValidationError
+	if errors.As(err, &verr) {
+		writeJSON(w, http.StatusBadRequest, verr)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
 // writeJSON writes a JSON response with the specified status code.
 // Sets the Content-Type header and encodes the provided value as JSON.
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -109,3 +110,46 @@ func TestWriteJSON_ComplexData(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `"number":123`)
 	assert.Contains(t, w.Body.String(), `"boolean":true`)
 }
+
+func TestDecodeJSON(t *testing.T) {
+	type request struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		limit      int64
+		wantErr    bool
+		wantStatus int
+	}{
+		{"Valid", `{"name":"a"}`, 0, false, 0},
+		{"Trailing whitespace", "{\"name\":\"a\"}\n", 0, false, 0},
+		{"Unknown field", `{"name":"a","extra":1}`, 0, true, http.StatusBadRequest},
+		{"Trailing value", `{"name":"a"}{"name":"b"}`, 0, true, http.StatusBadRequest},
+		{"Malformed", `{"name":`, 0, true, http.StatusBadRequest},
+		{"Too large", `{"name":"` + strings.Repeat("a", 100) + `"}`, 16, true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			if tt.limit > 0 {
+				req.Body = http.MaxBytesReader(w, req.Body, tt.limit)
+			}
+
+			var v request
+			err := decodeJSON(req, &v)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, "a", v.Name)
+				return
+			}
+			assert.Error(t, err)
+
+			writeDecodeError(w, err)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -18,7 +18,8 @@ func NewInfoHandler(version, build string) *InfoHandler {
 	}
 }
 
-// HealthCheck handles health check requests returning the service status.
+// HealthCheck handles liveness probes returning the service status.
+// It does not check dependencies, see HealthHandler.Readiness for that.
 func (ih *InfoHandler) HealthCheck(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -3,10 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
@@ -17,16 +19,26 @@ import (
 type ItemSvc interface {
 	CreateItem(ctx context.Context, req *models.CreateItemRequest, userID uuid.UUID) (*models.Item, error)
 	ListItems(ctx context.Context, userID uuid.UUID) ([]*models.Item, error)
+	ListDue(ctx context.Context, userID uuid.UUID, within, olderThan time.Duration) ([]models.DueItem, error)
 	GetItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, []byte, error)
 	UpdateItem(ctx context.Context, userID, itemID uuid.UUID, req *models.UpdateItemRequest) (*models.Item, error)
 	DeleteItem(ctx context.Context, userID, itemID uuid.UUID) error
+	ExecuteBatch(ctx context.Context, userID uuid.UUID, req *models.BatchRequest) ([]services.BatchOutcome, error)
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.Item, error)
+	RestoreItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, error)
+	PurgeItem(ctx context.Context, userID, itemID uuid.UUID) error
+	EmptyTrash(ctx context.Context, userID uuid.UUID) (int64, error)
+	Usage(ctx context.Context, userID uuid.UUID) (*models.UsageReport, error)
 }
 
 // ItemValidator defines the contract for validating item management requests.
 type ItemValidator interface {
 	ValidateCreateItemRequest(req *models.CreateItemRequest) error
 	ValidateUpdateItemRequest(req *models.UpdateItemRequest) error
+	ValidateBatchRequest(req *models.BatchRequest) error
+	ValidateBatchOperation(op *models.BatchOperation) error
 	ValidateUUID(id string) (uuid.UUID, error)
+	ValidateDays(raw string, def int) (int, error)
 }
 
 // ItemHandler handles HTTP requests for item management operations.
@@ -51,6 +63,19 @@ type itemResponse struct {
 	Data string       `json:"data_base64,omitempty"`
 }
 
+// itemsResponse represents a list of items.
+type itemsResponse struct {
+	Items []*models.Item `json:"items"`
+}
+
+// dueResponse represents a list of items that need attention.
+type dueResponse struct {
+	Items []models.DueItem `json:"items"`
+}
+
+// defaultDueDays is the expiration window used when the days parameter is omitted.
+const defaultDueDays = 30
+
 // CreateItem handles item creation requests.
 // Creates a new encrypted item for the authenticated user.
 func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
@@ -60,8 +85,8 @@ func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request, userID
 	}
 
 	var req models.CreateItemRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -76,7 +101,14 @@ func (h *ItemHandler) CreateItem(w http.ResponseWriter, r *http.Request, userID
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
-		h.logger.Error("failed to create item", zap.Error(err))
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if writeQuotaError(w, err) {
+			return
+		}
+		h.logger.Error("failed to create item", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -99,8 +131,8 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request, userID
 	}
 
 	var req models.UpdateItemRequest
-	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	if err = decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -115,11 +147,18 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request, userID
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, services.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if writeQuotaError(w, err) {
+			return
+		}
 		if errors.Is(err, models.ErrItemNotFound) {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
-		h.logger.Error("failed to update item", zap.Error(err))
+		h.logger.Error("failed to update item", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -132,12 +171,39 @@ func (h *ItemHandler) UpdateItem(w http.ResponseWriter, r *http.Request, userID
 func (h *ItemHandler) ListItems(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
 	items, err := h.itemSvc.ListItems(r.Context(), userID)
 	if err != nil {
-		h.logger.Error("failed to list items", zap.Error(err))
+		h.logger.Error("failed to list items", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, items)
+	writeJSON(w, http.StatusOK, itemsResponse{Items: items})
+}
+
+// ListDue handles requests to list items that are expired or expire soon.
+// The days query parameter sets the expiration window (30 by default) and the optional
+// older_than parameter also reports items not updated for the given number of days.
+func (h *ItemHandler) ListDue(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	query := r.URL.Query()
+	days, err := h.validator.ValidateDays(query.Get("days"), defaultDueDays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	olderThan, err := h.validator.ValidateDays(query.Get("older_than"), 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	const day = 24 * time.Hour
+	items, err := h.itemSvc.ListDue(r.Context(), userID, time.Duration(days)*day, time.Duration(olderThan)*day)
+	if err != nil {
+		h.logger.Error("failed to list due items", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dueResponse{Items: items})
 }
 
 // GetItem handles requests to retrieve a specific item with its decrypted data.
@@ -155,7 +221,7 @@ func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request, userID uui
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
-		h.logger.Error("failed to get item", zap.Error(err))
+		h.logger.Error("failed to get item", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -168,7 +234,7 @@ func (h *ItemHandler) GetItem(w http.ResponseWriter, r *http.Request, userID uui
 }
 
 // DeleteItem handles requests to delete a specific item.
-// Permanently removes the item and its encrypted data from the database.
+// Moves the item to the trash from where it can be restored until purged.
 func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
 	itemID, err := h.validator.ValidateUUID(r.PathValue("id"))
 	if err != nil {
@@ -181,10 +247,121 @@ func (h *ItemHandler) DeleteItem(w http.ResponseWriter, r *http.Request, userID
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
-		h.logger.Error("failed to delete item", zap.Error(err))
+		h.logger.Error("failed to delete item", zap.Error(err), requestid.Field(r.Context()))
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ExecuteBatch handles batch requests with create, update and delete operations.
+// Atomic batches either apply every operation or respond with the status of the first
+// failing one; non-atomic batches respond with 200 OK and a result per operation,
+// invalid operations are reported with 400 Bad Request without affecting the others.
+func (h *ItemHandler) ExecuteBatch(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if !isJSON(r) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var req models.BatchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := h.validator.ValidateBatchRequest(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := models.BatchResponse{Results: make([]models.BatchResult, len(req.Operations))}
+	valid := models.BatchRequest{Atomic: req.Atomic, Operations: make([]models.BatchOperation, 0, len(req.Operations))}
+	indexes := make([]int, 0, len(req.Operations))
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		if err := h.validator.ValidateBatchOperation(op); err != nil {
+			if req.Atomic {
+				http.Error(w, fmt.Sprintf("operation %d: %v", i, err), http.StatusBadRequest)
+				return
+			}
+			resp.Results[i] = models.BatchResult{Index: i, Op: op.Op, Status: http.StatusBadRequest, Error: err.Error()}
+			continue
+		}
+		valid.Operations = append(valid.Operations, *op)
+		indexes = append(indexes, i)
+	}
+	if len(valid.Operations) == 0 {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	outcomes, err := h.itemSvc.ExecuteBatch(r.Context(), userID, &valid)
+	if err != nil {
+		var batchErr *services.BatchError
+		if !errors.As(err, &batchErr) {
+			h.logger.Error("failed to execute batch", zap.Error(err), requestid.Field(r.Context()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		i := indexes[batchErr.Index]
+		result := h.batchResult(r.Context(), i, req.Operations[i].Op, nil, batchErr.Err)
+		writeJSON(w, result.Status, models.BatchResponse{Results: []models.BatchResult{result}})
+		return
+	}
+
+	for j, o := range outcomes {
+		i := indexes[j]
+		resp.Results[i] = h.batchResult(r.Context(), i, o.Op, o.Item, o.Err)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// batchResult converts the outcome of a batch operation into its API representation.
+// Internal errors are logged and reported without details.
+func (h *ItemHandler) batchResult(ctx context.Context, index int, op models.BatchOpType, item *models.Item, err error) models.BatchResult {
+	result := models.BatchResult{Index: index, Op: op, Item: item}
+
+	switch {
+	case err == nil && op == models.BatchOpCreate:
+		result.Status = http.StatusCreated
+	case err == nil && op == models.BatchOpDelete:
+		result.Status = http.StatusNoContent
+	case err == nil:
+		result.Status = http.StatusOK
+	case errors.Is(err, services.ErrInvalidItemType), errors.Is(err, services.ErrInvalidInput):
+		result.Status = http.StatusBadRequest
+		result.Error = err.Error()
+	case errors.Is(err, models.ErrItemNotFound):
+		result.Status = http.StatusNotFound
+		result.Error = err.Error()
+	case errors.Is(err, models.ErrQuotaExceeded):
+		result.Status = http.StatusInsufficientStorage
+		result.Error = err.Error()
+	case errors.Is(err, models.ErrItemTooLarge):
+		result.Status = http.StatusRequestEntityTooLarge
+		result.Error = err.Error()
+	default:
+		h.logger.Error("failed to execute batch operation", zap.Int("index", index), zap.Error(err), requestid.Field(ctx))
+		result.Status = http.StatusInternalServerError
+		result.Error = http.StatusText(http.StatusInternalServerError)
+	}
+	return result
+}
+
+// writeQuotaError responds to a change that does not fit the storage quota of the user:
+// 413 Request Entity Too Large if the item alone exceeds it, 507 Insufficient Storage otherwise.
+// Reports whether err was a quota error.
+func writeQuotaError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, models.ErrItemTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, models.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+	default:
+		return false
+	}
+	return true
+}
@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
 	"github.com/Pro100x3mal/gophkeeper/internal/server/validators"
@@ -15,6 +18,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +48,14 @@ func (m *MockItemService) ListItems(ctx context.Context, userID uuid.UUID) ([]*m
 	return args.Get(0).([]*models.Item), args.Error(1)
 }
 
+func (m *MockItemService) ListDue(ctx context.Context, userID uuid.UUID, within, olderThan time.Duration) ([]models.DueItem, error) {
+	args := m.Called(ctx, userID, within, olderThan)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DueItem), args.Error(1)
+}
+
 func (m *MockItemService) GetItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, []byte, error) {
 	args := m.Called(ctx, userID, itemID)
 	if args.Get(0) == nil {
@@ -69,6 +81,48 @@ func (m *MockItemService) DeleteItem(ctx context.Context, userID, itemID uuid.UU
 	return args.Error(0)
 }
 
+func (m *MockItemService) ExecuteBatch(ctx context.Context, userID uuid.UUID, req *models.BatchRequest) ([]services.BatchOutcome, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.BatchOutcome), args.Error(1)
+}
+
+func (m *MockItemService) ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.Item, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) RestoreItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, error) {
+	args := m.Called(ctx, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) PurgeItem(ctx context.Context, userID, itemID uuid.UUID) error {
+	args := m.Called(ctx, userID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockItemService) EmptyTrash(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemService) Usage(ctx context.Context, userID uuid.UUID) (*models.UsageReport, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UsageReport), args.Error(1)
+}
+
 func (m *MockItemValidator) ValidateCreateItemRequest(req *models.CreateItemRequest) error {
 	args := m.Called(req)
 	return args.Error(0)
@@ -79,11 +133,26 @@ func (m *MockItemValidator) ValidateUpdateItemRequest(req *models.UpdateItemRequ
 	return args.Error(0)
 }
 
+func (m *MockItemValidator) ValidateBatchRequest(req *models.BatchRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockItemValidator) ValidateBatchOperation(op *models.BatchOperation) error {
+	args := m.Called(op)
+	return args.Error(0)
+}
+
 func (m *MockItemValidator) ValidateUUID(id string) (uuid.UUID, error) {
 	args := m.Called(id)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
+func (m *MockItemValidator) ValidateDays(raw string, def int) (int, error) {
+	args := m.Called(raw, def)
+	return args.Int(0), args.Error(1)
+}
+
 func TestNewItemHandler(t *testing.T) {
 	mockService := new(MockItemService)
 	mockValidator := validators.NewItemValidator()
@@ -195,6 +264,103 @@ func TestItemHandler_CreateItem_InvalidItemType(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestItemHandler_CreateItem_QuotaExceeded(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("CreateItem", mock.Anything, mock.AnythingOfType("*models.CreateItemRequest"), userID).
+		Return(nil, fmt.Errorf("%w: limit is 10 items", models.ErrQuotaExceeded))
+
+	body, _ := json.Marshal(models.CreateItemRequest{Type: models.ItemTypeText, Title: "Test Item"})
+	req := httptest.NewRequest(http.MethodPost, "/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateItem(w, req, userID)
+
+	assert.Equal(t, http.StatusInsufficientStorage, w.Code)
+	assert.Contains(t, w.Body.String(), "limit is 10 items")
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_CreateItem_ItemTooLarge(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("CreateItem", mock.Anything, mock.AnythingOfType("*models.CreateItemRequest"), userID).
+		Return(nil, fmt.Errorf("%w: limit is 1024 bytes", models.ErrItemTooLarge))
+
+	body, _ := json.Marshal(models.CreateItemRequest{Type: models.ItemTypeText, Title: "Test Item"})
+	req := httptest.NewRequest(http.MethodPost, "/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateItem(w, req, userID)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "limit is 1024 bytes")
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_CreateItem_UnknownField(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	body := `{"type":"text","title":"Test Item","titel":"typo"}`
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateItem(w, req, uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "titel")
+	mockService.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemHandler_CreateItem_BodyTooLarge(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	body, _ := json.Marshal(models.CreateItemRequest{Type: models.ItemTypeText, Title: "Test Item", DataBase64: strings.Repeat("A", 1024)})
+	req := httptest.NewRequest(http.MethodPost, "/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(w, req.Body, 256)
+
+	handler.CreateItem(w, req, uuid.New())
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemHandler_CreateItem_TitleTooLong(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	body, _ := json.Marshal(models.CreateItemRequest{Type: models.ItemTypeText, Title: strings.Repeat("я", validators.MaxTitleLength+1)})
+	req := httptest.NewRequest(http.MethodPost, "/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateItem(w, req, uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), validators.ErrTitleTooLong.Error())
+}
+
 func TestItemHandler_ListItems_Success(t *testing.T) {
 	mockService := new(MockItemService)
 	validator := validators.NewItemValidator()
@@ -481,3 +647,284 @@ func TestItemHandler_DeleteItem_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestItemHandler_ExecuteBatch_NonAtomic(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	missingID := uuid.New()
+	created := &models.Item{ID: uuid.New(), UserID: userID, Type: models.ItemTypeText, Title: "Note"}
+
+	mockService.On("ExecuteBatch", mock.Anything, userID, mock.AnythingOfType("*models.BatchRequest")).
+		Return([]services.BatchOutcome{
+			{Op: models.BatchOpCreate, Item: created},
+			{Op: models.BatchOpDelete},
+			{Op: models.BatchOpDelete, Err: models.ErrItemNotFound},
+		}, nil)
+
+	reqBody := models.BatchRequest{Operations: []models.BatchOperation{
+		{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText, Title: "Note"}},
+		{Op: models.BatchOpDelete, ID: &itemID},
+		{Op: models.BatchOpDelete, ID: &missingID},
+	}}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ExecuteBatch(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.BatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Results, 3)
+	assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+	assert.Equal(t, created.ID, resp.Results[0].Item.ID)
+	assert.Equal(t, http.StatusNoContent, resp.Results[1].Status)
+	assert.Equal(t, http.StatusNotFound, resp.Results[2].Status)
+	assert.NotEmpty(t, resp.Results[2].Error)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_ExecuteBatch_AtomicFailure(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockService.On("ExecuteBatch", mock.Anything, userID, mock.AnythingOfType("*models.BatchRequest")).
+		Return(nil, &services.BatchError{Index: 1, Err: models.ErrItemNotFound})
+
+	reqBody := models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{
+		{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText, Title: "Note"}},
+		{Op: models.BatchOpDelete, ID: &itemID},
+	}}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ExecuteBatch(w, req, userID)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var resp models.BatchResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Results, 1)
+	assert.Equal(t, 1, resp.Results[0].Index)
+	assert.Equal(t, models.BatchOpDelete, resp.Results[0].Op)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_ExecuteBatch_InvalidInput(t *testing.T) {
+	invalidInput := fmt.Errorf("failed to decode base64 data: %w", services.ErrInvalidInput)
+	itemID := uuid.New()
+	ops := []models.BatchOperation{
+		{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText, Title: "Note", DataBase64: "!!!"}},
+		{Op: models.BatchOpDelete, ID: &itemID},
+	}
+
+	t.Run("Non-atomic", func(t *testing.T) {
+		mockService := new(MockItemService)
+		handler := NewItemHandler(mockService, validators.NewItemValidator(), zap.NewNop())
+		userID := uuid.New()
+		mockService.On("ExecuteBatch", mock.Anything, userID, mock.Anything).Return([]services.BatchOutcome{
+			{Op: models.BatchOpCreate, Err: invalidInput},
+			{Op: models.BatchOpDelete},
+		}, nil)
+
+		body, _ := json.Marshal(models.BatchRequest{Operations: ops})
+		req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.ExecuteBatch(w, req, userID)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp models.BatchResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, http.StatusBadRequest, resp.Results[0].Status)
+		assert.Equal(t, invalidInput.Error(), resp.Results[0].Error)
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		mockService := new(MockItemService)
+		handler := NewItemHandler(mockService, validators.NewItemValidator(), zap.NewNop())
+		userID := uuid.New()
+		mockService.On("ExecuteBatch", mock.Anything, userID, mock.Anything).
+			Return(nil, &services.BatchError{Index: 0, Err: invalidInput})
+
+		body, _ := json.Marshal(models.BatchRequest{Atomic: true, Operations: ops})
+		req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.ExecuteBatch(w, req, userID)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp models.BatchResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(t, resp.Results, 1)
+		assert.Equal(t, http.StatusBadRequest, resp.Results[0].Status)
+	})
+}
+
+func TestItemHandler_ExecuteBatch_InvalidOperation(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	tests := []struct {
+		name string
+		req  models.BatchRequest
+	}{
+		{"Empty batch", models.BatchRequest{}},
+		{"Delete without ID", models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{{Op: models.BatchOpDelete}}}},
+		{"Create without title", models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{
+			{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText}},
+		}}},
+		{"Unknown op", models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{{Op: "rename"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			handler.ExecuteBatch(w, req, uuid.New())
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	mockService.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemHandler_ExecuteBatch_NonAtomicInvalidOperation(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockService.On("ExecuteBatch", mock.Anything, userID, mock.MatchedBy(func(req *models.BatchRequest) bool {
+		return len(req.Operations) == 1 && req.Operations[0].Op == models.BatchOpDelete
+	})).Return([]services.BatchOutcome{{Op: models.BatchOpDelete}}, nil)
+
+	reqBody := models.BatchRequest{Operations: []models.BatchOperation{
+		{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText}},
+		{Op: models.BatchOpDelete, ID: &itemID},
+		{Op: "rename"},
+	}}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ExecuteBatch(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.BatchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[0].Status)
+	assert.Equal(t, validators.ErrEmptyTitle.Error(), resp.Results[0].Error)
+	assert.Equal(t, http.StatusNoContent, resp.Results[1].Status)
+	assert.Equal(t, 1, resp.Results[1].Index)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[2].Status)
+	assert.Equal(t, 2, resp.Results[2].Index)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_ExecuteBatch_NonAtomicAllInvalid(t *testing.T) {
+	mockService := new(MockItemService)
+	handler := NewItemHandler(mockService, validators.NewItemValidator(), zap.NewNop())
+
+	body, _ := json.Marshal(models.BatchRequest{Operations: []models.BatchOperation{{Op: models.BatchOpDelete}}})
+	req := httptest.NewRequest(http.MethodPost, "/items/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ExecuteBatch(w, req, uuid.New())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.BatchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[0].Status)
+	mockService.AssertNotCalled(t, "ExecuteBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemHandler_ListDue_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	expires := time.Now().Add(24 * time.Hour)
+	due := []models.DueItem{
+		{Item: &models.Item{ID: uuid.New(), Title: "Card", ExpiresAt: &expires}, Reason: models.DueReasonExpiring},
+	}
+
+	mockService.On("ListDue", mock.Anything, userID, 7*24*time.Hour, 90*24*time.Hour).Return(due, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/items/due?days=7&older_than=90", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListDue(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dueResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, models.DueReasonExpiring, resp.Items[0].Reason)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_ListDue_Defaults(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("ListDue", mock.Anything, userID, 30*24*time.Hour, time.Duration(0)).Return([]models.DueItem{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/items/due", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListDue(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_ListDue_InvalidDays(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	for _, query := range []string{"days=-1", "days=abc", "older_than=100000"} {
+		req := httptest.NewRequest(http.MethodGet, "/items/due?"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.ListDue(w, req, uuid.New())
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNotCalled(t, "ListDue")
+}
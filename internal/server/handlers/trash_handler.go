@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// purgeResponse represents the result of emptying the trash.
+type purgeResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// ListTrash handles requests to list deleted items of the authenticated user.
+func (h *ItemHandler) ListTrash(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	items, err := h.itemSvc.ListTrash(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to list trash", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, itemsResponse{Items: items})
+}
+
+// RestoreItem handles requests to move a deleted item out of the trash.
+func (h *ItemHandler) RestoreItem(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	itemID, err := h.validator.ValidateUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.itemSvc.RestoreItem(r.Context(), userID, itemID)
+	if err != nil {
+		if errors.Is(err, models.ErrItemNotFound) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		if writeQuotaError(w, err) {
+			return
+		}
+		h.logger.Error("failed to restore item", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, itemResponse{Item: item})
+}
+
+// PurgeItem handles requests to permanently remove a deleted item.
+func (h *ItemHandler) PurgeItem(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	itemID, err := h.validator.ValidateUUID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.itemSvc.PurgeItem(r.Context(), userID, itemID); err != nil {
+		if errors.Is(err, models.ErrItemNotFound) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to purge item", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EmptyTrash handles requests to permanently remove all deleted items of the authenticated user.
+func (h *ItemHandler) EmptyTrash(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	n, err := h.itemSvc.EmptyTrash(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to empty trash", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, purgeResponse{Purged: n})
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/validators"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestItemHandler_ListTrash_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	items := []*models.Item{
+		{ID: uuid.New(), UserID: userID, Type: models.ItemTypeText, Title: "Deleted"},
+	}
+
+	mockService.On("ListTrash", mock.Anything, userID).Return(items, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListTrash(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp itemsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "Deleted", resp.Items[0].Title)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_RestoreItem_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	item := &models.Item{ID: itemID, UserID: userID, Type: models.ItemTypeText, Title: "Restored"}
+
+	mockService.On("RestoreItem", mock.Anything, userID, itemID).Return(item, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /trash/{id}/restore", func(w http.ResponseWriter, req *http.Request) {
+		handler.RestoreItem(w, req, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/trash/"+itemID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_RestoreItem_NotFound(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockService.On("RestoreItem", mock.Anything, userID, itemID).Return(nil, models.ErrItemNotFound)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /trash/{id}/restore", func(w http.ResponseWriter, req *http.Request) {
+		handler.RestoreItem(w, req, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/trash/"+itemID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_RestoreItem_QuotaExceeded(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockService.On("RestoreItem", mock.Anything, userID, itemID).Return(nil, models.ErrQuotaExceeded)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /trash/{id}/restore", func(w http.ResponseWriter, req *http.Request) {
+		handler.RestoreItem(w, req, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/trash/"+itemID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInsufficientStorage, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_PurgeItem_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockService.On("PurgeItem", mock.Anything, userID, itemID).Return(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /trash/{id}", func(w http.ResponseWriter, req *http.Request) {
+		handler.PurgeItem(w, req, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash/"+itemID.String(), nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_PurgeItem_InvalidUUID(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /trash/{id}", func(w http.ResponseWriter, req *http.Request) {
+		handler.PurgeItem(w, req, userID)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash/invalid-uuid", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestItemHandler_EmptyTrash_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("EmptyTrash", mock.Anything, userID).Return(int64(3), nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash", nil)
+	w := httptest.NewRecorder()
+
+	handler.EmptyTrash(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp purgeResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, int64(3), resp.Purged)
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_EmptyTrash_Error(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("EmptyTrash", mock.Anything, userID).Return(int64(0), errors.New("database error"))
+
+	req := httptest.NewRequest(http.MethodDelete, "/trash", nil)
+	w := httptest.NewRecorder()
+
+	handler.EmptyTrash(w, req, userID)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
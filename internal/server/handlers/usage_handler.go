@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Usage handles requests for the storage usage and quota of the authenticated user.
+func (h *ItemHandler) Usage(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	report, err := h.itemSvc.Usage(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to get usage", zap.Error(err), requestid.Field(r.Context()))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/validators"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestItemHandler_Usage_Success(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	report := &models.UsageReport{
+		Usage: models.Usage{Items: 2, Bytes: 512},
+		Quota: models.Quota{MaxItems: 100, MaxBytes: 1 << 20},
+	}
+	mockService.On("Usage", mock.Anything, userID).Return(report, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/usage", nil)
+	w := httptest.NewRecorder()
+
+	handler.Usage(w, req, userID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"usage":{"items":2,"bytes":512},"quota":{"max_items":100,"max_bytes":1048576}}`, w.Body.String())
+
+	var resp models.UsageReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	mockService.AssertExpectations(t)
+}
+
+func TestItemHandler_Usage_Error(t *testing.T) {
+	mockService := new(MockItemService)
+	validator := validators.NewItemValidator()
+	logger, _ := zap.NewDevelopment()
+	handler := NewItemHandler(mockService, validator, logger)
+
+	userID := uuid.New()
+	mockService.On("Usage", mock.Anything, userID).Return(nil, errors.New("db down"))
+
+	req := httptest.NewRequest(http.MethodGet, "/usage", nil)
+	w := httptest.NewRecorder()
+
+	handler.Usage(w, req, userID)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,110 @@
+// Package metrics provides Prometheus metrics for the GophKeeper server.
+//
+// Metrics are registered in a dedicated registry and cover HTTP requests per route,
+// authentication results, encryption timings, database pool statistics and build info.
+// They are served by Handler, usually on a listener separate from the API.
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes all metric names.
+const namespace = "gophkeeper"
+
+// Metrics holds the collectors of the server.
+// It implements services.Observer and middleware.RequestObserver.
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	auth     *prometheus.CounterVec
+	crypto   *prometheus.HistogramVec
+}
+
+// New creates the metrics and registers them together with Go runtime and process metrics.
+func New(buildVersion, buildDate string) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+		auth: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "attempts_total",
+			Help:      "Number of login and registration attempts by result.",
+		}, []string{"operation", "result"}),
+		crypto: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "crypto",
+			Name:      "duration_seconds",
+			Help:      "Duration of encryption and decryption operations.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, []string{"operation"}),
+	}
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "build_info",
+		Help:        "Build information of the server, the value is always 1.",
+		ConstLabels: prometheus.Labels{"version": buildVersion, "build_date": buildDate, "go_version": runtime.Version()},
+	})
+	buildInfo.Set(1)
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.auth,
+		m.crypto,
+		buildInfo,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterPool adds the statistics of a database connection pool.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) error {
+	return m.registry.Register(newPoolCollector(pool))
+}
+
+// ObserveRequest records a served HTTP request.
+func (m *Metrics) ObserveRequest(route, method string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(route, method, code).Inc()
+	m.duration.WithLabelValues(route, method, code).Observe(d.Seconds())
+}
+
+// ObserveAuth records the result of a login or registration attempt.
+func (m *Metrics) ObserveAuth(operation, result string) {
+	m.auth.WithLabelValues(operation, result).Inc()
+}
+
+// ObserveCrypto records the duration of an encryption or decryption.
+func (m *Metrics) ObserveCrypto(operation string, d time.Duration) {
+	m.crypto.WithLabelValues(operation).Observe(d.Seconds())
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolStatter is implemented by *pgxpool.Pool.
+type poolStatter interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolCollector exports the statistics of a pgx connection pool, read at scrape time.
+type poolCollector struct {
+	pool poolStatter
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	newConnsCount        *prometheus.Desc
+	maxLifetimeDestroy   *prometheus.Desc
+	maxIdleDestroy       *prometheus.Desc
+}
+
+func newPoolCollector(pool poolStatter) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_connections", "Number of connections currently in use."),
+		idleConns:            desc("idle_connections", "Number of idle connections."),
+		constructingConns:    desc("constructing_connections", "Number of connections being established."),
+		totalConns:           desc("total_connections", "Total number of connections in the pool."),
+		maxConns:             desc("max_connections", "Maximum size of the pool."),
+		acquireCount:         desc("acquires_total", "Number of successful connection acquisitions."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		canceledAcquireCount: desc("canceled_acquires_total", "Number of acquisitions canceled by their context."),
+		emptyAcquireCount:    desc("empty_acquires_total", "Number of acquisitions that had to wait for a connection."),
+		newConnsCount:        desc("new_connections_total", "Number of connections opened."),
+		maxLifetimeDestroy:   desc("max_lifetime_destroys_total", "Number of connections closed after reaching their maximum lifetime."),
+		maxIdleDestroy:       desc("max_idle_destroys_total", "Number of connections closed after reaching their maximum idle time."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.canceledAcquireCount
+	ch <- c.emptyAcquireCount
+	ch <- c.newConnsCount
+	ch <- c.maxLifetimeDestroy
+	ch <- c.maxIdleDestroy
+}
+
+// Collect implements prometheus.Collector.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	gauge := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v)
+	}
+	counter := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquiredConns, float64(stat.AcquiredConns()))
+	gauge(c.idleConns, float64(stat.IdleConns()))
+	gauge(c.constructingConns, float64(stat.ConstructingConns()))
+	gauge(c.totalConns, float64(stat.TotalConns()))
+	gauge(c.maxConns, float64(stat.MaxConns()))
+	counter(c.acquireCount, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.canceledAcquireCount, float64(stat.CanceledAcquireCount()))
+	counter(c.emptyAcquireCount, float64(stat.EmptyAcquireCount()))
+	counter(c.newConnsCount, float64(stat.NewConnsCount()))
+	counter(c.maxLifetimeDestroy, float64(stat.MaxLifetimeDestroyCount()))
+	counter(c.maxIdleDestroy, float64(stat.MaxIdleDestroyCount()))
+}
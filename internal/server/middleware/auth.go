@@ -1,13 +1,15 @@
 // Package middleware provides HTTP middleware functions for the GophKeeper server.
 //
-// This package implements authentication, logging, and request context management middleware.
+// This package implements authentication, logging, request ID, metrics, tracing and request context management middleware.
 package middleware
 
 import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
 	"github.com/Pro100x3mal/gophkeeper/pkg/jwt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -15,14 +17,17 @@ import (
 
 type contextKey string
 
-const userIDContextKey contextKey = "user_id"
+const (
+	userIDContextKey        contextKey = "user_id"
+	tokenIssuedAtContextKey contextKey = "token_issued_at"
+)
 
 // Auth returns a middleware that validates JWT tokens from the Authorization header.
-// Extracts the user ID from valid tokens and adds it to the request context.
+// Extracts the user ID and the issue time from valid tokens and adds them to the request context.
 func Auth(jwtGen *jwt.Generator, logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			httpLog := logger.With(zap.String("middleware", "auth"))
+			httpLog := logger.With(zap.String("middleware", "auth"), requestid.Field(r.Context()))
 
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
@@ -36,14 +41,15 @@ func Auth(jwtGen *jwt.Generator, logger *zap.Logger) func(http.Handler) http.Han
 				return
 			}
 
-			userID, err := jwtGen.ValidateToken(parts[1])
+			claims, err := jwtGen.ParseToken(parts[1])
 			if err != nil {
 				httpLog.Error("invalid jwt token", zap.Error(err))
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+			ctx := context.WithValue(r.Context(), userIDContextKey, claims.UserID)
+			ctx = context.WithValue(ctx, tokenIssuedAtContextKey, claims.IssuedAt)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -59,3 +65,9 @@ func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	id, ok := val.(uuid.UUID)
 	return id, ok
 }
+
+// getTokenIssuedAtFromContext extracts the issue time of the request token from the request context.
+func getTokenIssuedAtFromContext(ctx context.Context) (time.Time, bool) {
+	issuedAt, ok := ctx.Value(tokenIssuedAtContextKey).(time.Time)
+	return issuedAt, ok
+}
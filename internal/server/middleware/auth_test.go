@@ -28,6 +28,9 @@ func TestAuth_Success(t *testing.T) {
 		extractedUserID, ok := GetUserIDFromContext(r.Context())
 		assert.True(t, ok)
 		assert.Equal(t, userID, extractedUserID)
+		issuedAt, ok := getTokenIssuedAtFromContext(r.Context())
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now(), issuedAt, 2*time.Second)
 		w.WriteHeader(http.StatusOK)
 	})
 
@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+// BodyLimit returns a middleware that limits request bodies to n bytes.
+// Reading past the limit fails with *http.MaxBytesError, which handlers report as 413 Request Entity Too Large.
+// A non-positive n returns the handler unchanged.
+func BodyLimit(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if n <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
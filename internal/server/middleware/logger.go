@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
 	"go.uber.org/zap"
 )
 
@@ -50,6 +51,7 @@ func Logger(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.Int("status", wrw.status),
 				zap.Int("size", wrw.size),
 				zap.Duration("duration", time.Since(start)),
+				requestid.Field(r.Context()),
 			)
 		})
 	}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// unmatchedRoute labels requests that did not match a registered route,
+// so arbitrary paths do not create new label values.
+const unmatchedRoute = "unmatched"
+
+// RequestObserver records served HTTP requests.
+type RequestObserver interface {
+	ObserveRequest(route, method string, status int, d time.Duration)
+}
+
+// Metrics returns a middleware that reports every request to the observer.
+// It must wrap the ServeMux directly, because the route is taken from the pattern the mux matched.
+func Metrics(observer RequestObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrw := &wrappedResponseWriter{
+				ResponseWriter: w,
+			}
+
+			next.ServeHTTP(wrw, r)
+
+			status := wrw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			observer.ObserveRequest(routeOf(r), r.Method, status, time.Since(start))
+		})
+	}
+}
+
+// routeOf returns the path of the pattern that matched the request, without the method.
+func routeOf(r *http.Request) string {
+	if r.Pattern == "" {
+		return unmatchedRoute
+	}
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		return path
+	}
+	return r.Pattern
+}
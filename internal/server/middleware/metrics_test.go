@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observedRequest struct {
+	route  string
+	method string
+	status int
+}
+
+type fakeRequestObserver struct {
+	requests []observedRequest
+}
+
+func (o *fakeRequestObserver) ObserveRequest(route, method string, status int, _ time.Duration) {
+	o.requests = append(o.requests, observedRequest{route: route, method: method, status: status})
+}
+
+func TestMetrics_Routes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	observer := &fakeRequestObserver{}
+	handler := Metrics(observer)(mux)
+
+	for _, path := range []string{"/api/v1/items/123", "/api/v1/items/456", "/api/v1/health", "/random/path"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Len(t, observer.requests, 4)
+	assert.Equal(t, observedRequest{"/api/v1/items/{id}", http.MethodGet, http.StatusNotFound}, observer.requests[0])
+	assert.Equal(t, observer.requests[0], observer.requests[1])
+	assert.Equal(t, observedRequest{"/api/v1/health", http.MethodGet, http.StatusOK}, observer.requests[2])
+	assert.Equal(t, observedRequest{unmatchedRoute, http.MethodGet, http.StatusNotFound}, observer.requests[3])
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/ratelimit"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"go.uber.org/zap"
+)
+
+// RateLimit returns a middleware that limits the requests of a route group.
+// Authenticated requests are counted per user, so on protected routes it must run after Auth;
+// other requests are counted per client IP address. Each group has its own buckets.
+//
+// Responses carry the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers, rejected requests get 429 Too Many Requests with Retry-After. When the store fails,
+// the request is let through, so an unavailable store does not take the API down.
+// A disabled limit returns the handler unchanged.
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limit.Enabled() {
+			return next
+		}
+		policy := strconv.Itoa(limit.Requests) + ";w=" + strconv.Itoa(ceilSeconds(limit.Period))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := store.Take(r.Context(), rateLimitKey(group, r), limit, time.Now())
+			if err != nil {
+				logger.Error("rate limit store failed", zap.String("group", group), zap.Error(err), requestid.Field(r.Context()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			h.Set("RateLimit-Policy", policy)
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the bucket of a request: the user for authenticated requests,
+// the client IP address otherwise.
+func rateLimitKey(group string, r *http.Request) string {
+	if userID, ok := GetUserIDFromContext(r.Context()); ok {
+		return group + ":user:" + userID.String()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return group + ":ip:" + host
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
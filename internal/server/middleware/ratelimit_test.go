@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/ratelimit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// failingStore is a rate limit store that is always unavailable.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 2, Period: time.Minute}
+	handler := RateLimit(ratelimit.NewMemoryStore(), "test", limit, zap.NewNop())(okHandler())
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("192.0.2.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	// Another port of the same address shares the bucket.
+	w = serve("192.0.2.1:5678")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = serve("192.0.2.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	w = serve("192.0.2.2:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_PerUser(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	handler := RateLimit(ratelimit.NewMemoryStore(), "test", limit, zap.NewNop())(okHandler())
+
+	serve := func(userID uuid.UUID) int {
+		ctx := context.WithValue(context.Background(), userIDContextKey, userID)
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	alice, bob := uuid.New(), uuid.New()
+	assert.Equal(t, http.StatusOK, serve(alice))
+	assert.Equal(t, http.StatusTooManyRequests, serve(alice))
+	assert.Equal(t, http.StatusOK, serve(bob), "users behind the same address have separate buckets")
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	handler := RateLimit(failingStore{}, "test", ratelimit.Limit{}, zap.NewNop())(okHandler())
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRateLimit_StoreFailureLetsRequestsThrough(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	handler := RateLimit(failingStore{}, "test", limit, zap.NewNop())(okHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/google/uuid"
+)
+
+// RequestID returns a middleware that assigns a correlation ID to every request.
+// A valid X-Request-ID sent by the client is kept, otherwise a new ID is generated.
+// The ID is stored in the request context and returned in the X-Request-ID response header.
+// It must be the outermost middleware, so every log line of the request can include the ID.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = uuid.NewString()
+			}
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestid.FromContext(r.Context())
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	}))
+
+	t.Run("accepts client ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, "client-id-1")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, "client-id-1", seen)
+		assert.Equal(t, "client-id-1", w.Header().Get(requestid.Header))
+	})
+
+	t.Run("generates missing ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		_, err := uuid.Parse(seen)
+		require.NoError(t, err)
+		assert.Equal(t, seen, w.Header().Get(requestid.Header))
+	})
+
+	t.Run("replaces invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(requestid.Header, "bad id\x00")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.NotEqual(t, "bad id\x00", seen)
+		assert.Equal(t, seen, w.Header().Get(requestid.Header))
+	})
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// RequireAdmin allows only admins to reach the next handler.
+// Returns 403 Forbidden if the role added by Session is not models.RoleAdmin.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role, ok := GetRoleFromContext(r.Context()); !ok || role != models.RoleAdmin {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const roleContextKey contextKey = "role"
+
+// SessionChecker defines the contract for checking that a token may still be used.
+type SessionChecker interface {
+	CheckSession(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (*models.User, error)
+}
+
+// Session returns a middleware that rejects the tokens of deleted users and tokens revoked by an admin
+// with 401 Unauthorized, and the tokens of disabled users with 403 Forbidden.
+// It must run after Auth. Adds the role of the user to the request context.
+func Session(sessions SessionChecker, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			issuedAt, hasIssuedAt := getTokenIssuedAtFromContext(r.Context())
+			if !ok || !hasIssuedAt {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			user, err := sessions.CheckSession(r.Context(), userID, issuedAt)
+			switch {
+			case err == nil:
+			case errors.Is(err, services.ErrSessionRevoked):
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			case errors.Is(err, models.ErrUserDisabled):
+				http.Error(w, models.ErrUserDisabled.Error(), http.StatusForbidden)
+				return
+			default:
+				logger.Error("failed to check session", zap.String("middleware", "session"),
+					zap.String("user_id", userID.String()), zap.Error(err), requestid.Field(r.Context()))
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), roleContextKey, user.Role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetRoleFromContext extracts the role of the user from the request context.
+// Returns the role and true if found, or an empty role and false otherwise.
+func GetRoleFromContext(ctx context.Context) (models.Role, bool) {
+	role, ok := ctx.Value(roleContextKey).(models.Role)
+	return role, ok
+}
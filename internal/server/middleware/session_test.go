@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/services"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// sessionCheckerFunc adapts a function to the SessionChecker interface.
+type sessionCheckerFunc func(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (*models.User, error)
+
+func (f sessionCheckerFunc) CheckSession(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (*models.User, error) {
+	return f(ctx, userID, issuedAt)
+}
+
+func sessionRequest(userID uuid.UUID, issuedAt time.Time) *http.Request {
+	ctx := context.WithValue(context.Background(), userIDContextKey, userID)
+	ctx = context.WithValue(ctx, tokenIssuedAtContextKey, issuedAt)
+	return httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+}
+
+func TestSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *models.User
+		err      error
+		wantCode int
+	}{
+		{name: "active user", user: &models.User{Role: models.RoleUser}, wantCode: http.StatusOK},
+		{name: "revoked token", err: services.ErrSessionRevoked, wantCode: http.StatusUnauthorized},
+		{name: "disabled user", err: models.ErrUserDisabled, wantCode: http.StatusForbidden},
+		{name: "check failed", err: errors.New("database error"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			issuedAt := time.Now().Truncate(time.Second)
+			checker := sessionCheckerFunc(func(_ context.Context, id uuid.UUID, at time.Time) (*models.User, error) {
+				assert.Equal(t, userID, id)
+				assert.Equal(t, issuedAt, at)
+				return tt.user, tt.err
+			})
+			handler := Session(checker, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				role, ok := GetRoleFromContext(r.Context())
+				assert.True(t, ok)
+				assert.Equal(t, models.RoleUser, role)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, sessionRequest(userID, issuedAt))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestSession_WithoutAuth(t *testing.T) {
+	checker := sessionCheckerFunc(func(context.Context, uuid.UUID, time.Time) (*models.User, error) {
+		t.Fatal("session checked without a token")
+		return nil, nil
+	})
+	handler := Session(checker, zap.NewNop())(okHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode int
+	}{
+		{name: "admin", ctx: context.WithValue(context.Background(), roleContextKey, models.RoleAdmin), wantCode: http.StatusOK},
+		{name: "user", ctx: context.WithValue(context.Background(), roleContextKey, models.RoleUser), wantCode: http.StatusForbidden},
+		{name: "no role", ctx: context.Background(), wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			RequireAdmin(okHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by the middleware.
+const tracerName = "github.com/Pro100x3mal/gophkeeper/internal/server/middleware"
+
+// Tracing returns a middleware that starts a server span for every request.
+// The trace context sent by the client in the traceparent header becomes the parent of the span.
+// Like Metrics, it must wrap the ServeMux so the span can be named after the matched route.
+func Tracing() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+			if id := requestid.FromContext(ctx); id != "" {
+				span.SetAttributes(attribute.String("http.request.id", id))
+			}
+
+			wrw := &wrappedResponseWriter{
+				ResponseWriter: w,
+			}
+			r = r.WithContext(ctx)
+			next.ServeHTTP(wrw, r)
+
+			status := wrw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := routeOf(r)
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
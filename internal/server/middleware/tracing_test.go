@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	exporter, restore := tracing.SetupInMemory()
+	defer restore()
+
+	var handlerSpan trace.SpanContext
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("DELETE /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler := Tracing()(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/items/123", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/items/123", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/path", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	get := spans[0]
+	assert.Equal(t, "GET /api/v1/items/{id}", get.Name)
+	assert.Equal(t, trace.SpanKindServer, get.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", get.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", get.Parent.SpanID().String())
+	assert.True(t, get.Parent.IsRemote())
+	assert.Equal(t, get.SpanContext, handlerSpan, "handlers run in the request span")
+	assert.Contains(t, get.Attributes, semconv.HTTPRoute("/api/v1/items/{id}"))
+	assert.Contains(t, get.Attributes, semconv.HTTPResponseStatusCode(http.StatusOK))
+	assert.Equal(t, codes.Unset, get.Status.Code)
+
+	del := spans[1]
+	assert.Equal(t, "DELETE /api/v1/items/{id}", del.Name)
+	assert.False(t, del.Parent.IsValid())
+	assert.Equal(t, codes.Error, del.Status.Code)
+
+	assert.Equal(t, "GET "+unmatchedRoute, spans[2].Name)
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how many takes pass between removals of full buckets from memory.
+const sweepInterval = 1024
+
+// memoryEntry is a bucket with the limit it was last used with.
+type memoryEntry struct {
+	bucket
+	limit Limit
+}
+
+// MemoryStore keeps buckets in process memory. Limits are enforced per server instance.
+// Full buckets are dropped periodically, so idle clients do not accumulate.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryEntry
+	takes   int
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryEntry)}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepInterval == 0 {
+		s.sweep(now)
+	}
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &memoryEntry{bucket: newBucket(limit, now)}
+		s.buckets[key] = e
+	}
+	e.limit = limit
+	return e.take(limit, now), nil
+}
+
+// sweep removes the buckets that have refilled completely.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.buckets {
+		if e.full(e.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore keeps buckets in PostgreSQL, so all server replicas share the same limits.
+// Each take locks the row of its bucket for the duration of a short transaction.
+type PostgresStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresStore creates a store using the rate_limit_buckets table.
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Take implements Store.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	var res Result
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		insertQuery := `
+			INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO NOTHING
+		`
+		full := newBucket(limit, now)
+		if _, err := tx.Exec(ctx, insertQuery, key, full.tokens, full.updated); err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+
+		selectQuery := `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+		var b bucket
+		if err := tx.QueryRow(ctx, selectQuery, key).Scan(&b.tokens, &b.updated); err != nil {
+			return fmt.Errorf("failed to load bucket: %w", err)
+		}
+
+		res = b.take(limit, now)
+
+		updateQuery := `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`
+		if _, err := tx.Exec(ctx, updateQuery, key, b.tokens, b.updated); err != nil {
+			return fmt.Errorf("failed to save bucket: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take token: %w", err)
+	}
+	return res, nil
+}
+
+// DeleteIdle removes the buckets that have not been used since the given time.
+// Returns the number of removed buckets.
+func (s *PostgresStore) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM rate_limit_buckets WHERE updated_at < $1`
+	t, err := s.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idle buckets: %w", err)
+	}
+	return t.RowsAffected(), nil
+}
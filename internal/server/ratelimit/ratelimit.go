@@ -0,0 +1,144 @@
+// Package ratelimit implements token-bucket rate limiting for the GophKeeper server.
+//
+// Every key owns a bucket holding up to Limit.Requests tokens that refills evenly over
+// Limit.Period. A request takes one token and is rejected when the bucket is empty.
+// Buckets live in a Store: MemoryStore for a single server or PostgresStore to share
+// them between replicas.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLimit is returned when a limit cannot be parsed.
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Limit is the number of requests allowed per period.
+// The zero Limit disables rate limiting.
+type Limit struct {
+	// Requests is the size of the bucket.
+	Requests int
+	// Period is the time it takes to refill an empty bucket.
+	Period time.Duration
+}
+
+// ParseLimit parses a limit in the form "requests/period", e.g. "100/m", "10/s" or "1000/1h".
+// The period is a duration or one of the units s, m and h. An empty string or "0" disables the limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w %q: expected requests/period", ErrInvalidLimit, s)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("%w %q: requests must be a positive integer", ErrInvalidLimit, s)
+	}
+
+	var d time.Duration
+	switch period {
+	case "s":
+		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
+	default:
+		d, err = time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			return Limit{}, fmt.Errorf("%w %q: period must be a positive duration", ErrInvalidLimit, s)
+		}
+	}
+	return Limit{Requests: requests, Period: d}, nil
+}
+
+// Enabled reports whether the limit restricts requests.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// String formats the limit the way ParseLimit accepts it.
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "0"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// rate returns the number of tokens added per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	// Allowed reports whether the request may proceed.
+	Allowed bool
+	// Limit is the size of the bucket.
+	Limit int
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available, set when the request is rejected.
+	RetryAfter time.Duration
+}
+
+// Store takes tokens from buckets identified by keys.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// newBucket returns a full bucket.
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{tokens: float64(limit.Requests), updated: now}
+}
+
+// take refills the bucket for the time elapsed since its last update and takes a token if one is available.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed.Seconds()*limit.rate())
+		b.updated = now
+	}
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = tokenTime(1-b.tokens, limit)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = tokenTime(capacity-b.tokens, limit)
+	return res
+}
+
+// full reports whether the bucket would be full at the given time, so it can be forgotten.
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.rate() >= float64(limit.Requests)
+}
+
+// tokenTime returns the time needed to refill the given number of tokens.
+func tokenTime(tokens float64, limit Limit) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / limit.rate() * float64(time.Second)))
+}
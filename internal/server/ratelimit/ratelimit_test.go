@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Limit
+		wantErr  bool
+	}{
+		{"Per second", "10/s", Limit{Requests: 10, Period: time.Second}, false},
+		{"Per minute", "100/m", Limit{Requests: 100, Period: time.Minute}, false},
+		{"Per hour", "5/h", Limit{Requests: 5, Period: time.Hour}, false},
+		{"Duration period", "1000/15m", Limit{Requests: 1000, Period: 15 * time.Minute}, false},
+		{"Spaces", " 3/s ", Limit{Requests: 3, Period: time.Second}, false},
+		{"Empty disables", "", Limit{}, false},
+		{"Zero disables", "0", Limit{}, false},
+		{"Missing period", "100", Limit{}, true},
+		{"Zero requests", "0/m", Limit{}, true},
+		{"Negative requests", "-1/m", Limit{}, true},
+		{"Bad period", "10/week", Limit{}, true},
+		{"Negative period", "10/-1m", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := ParseLimit(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidLimit)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestLimit_Enabled(t *testing.T) {
+	assert.False(t, Limit{}.Enabled())
+	assert.True(t, Limit{Requests: 1, Period: time.Second}.Enabled())
+	assert.Equal(t, "0", Limit{}.String())
+	assert.Equal(t, "10/1m0s", Limit{Requests: 10, Period: time.Minute}.String())
+}
+
+func TestBucket_Take(t *testing.T) {
+	limit := Limit{Requests: 2, Period: 2 * time.Second}
+	now := time.Now()
+	b := newBucket(limit, now)
+
+	res := b.take(limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Limit)
+	assert.Equal(t, 1, res.Remaining)
+	assert.Equal(t, time.Second, res.Reset)
+
+	res = b.take(limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	res = b.take(limit, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// Half a second refills half a token, which is not enough.
+	res = b.take(limit, now.Add(500*time.Millisecond))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	res = b.take(limit, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+
+	// The bucket never holds more than its size.
+	res = b.take(limit, now.Add(time.Hour))
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+	now := time.Now()
+	ctx := context.Background()
+
+	res, err := store.Take(ctx, "a", limit, now)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = store.Take(ctx, "a", limit, now)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Minute, res.RetryAfter)
+
+	res, err = store.Take(ctx, "b", limit, now)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "keys have separate buckets")
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Now()
+
+	_, err := store.Take(context.Background(), "idle", limit, now)
+	require.NoError(t, err)
+
+	later := now.Add(time.Minute)
+	for range sweepInterval - 1 {
+		_, err = store.Take(context.Background(), "busy", limit, later)
+		require.NoError(t, err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthRepository handles the database queries of the readiness probe.
+type HealthRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewHealthRepository creates a new health repository instance.
+func NewHealthRepository(db *pgxpool.Pool) *HealthRepository {
+	return &HealthRepository{db: db}
+}
+
+// Ping checks that a connection to the database can be acquired and used.
+func (r *HealthRepository) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "HealthRepository.Ping")
+	defer func() { tracing.End(span, err) }()
+
+	return r.db.Ping(ctx)
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate and whether
+// the last migration failed halfway (dirty).
+func (r *HealthRepository) MigrationVersion(ctx context.Context) (_ uint, _ bool, err error) {
+	ctx, span := startSpan(ctx, "HealthRepository.MigrationVersion")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	var (
+		version int64
+		dirty   bool
+	)
+	if err := r.db.QueryRow(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+// SaveCanary stores the encrypted master key canary unless one is already stored.
+func (r *HealthRepository) SaveCanary(ctx context.Context, enc []byte) (err error) {
+	ctx, span := startSpan(ctx, "HealthRepository.SaveCanary")
+	defer func() { tracing.End(span, err) }()
+
+	query := `INSERT INTO master_key_canary (value_encrypted) VALUES ($1) ON CONFLICT (id) DO NOTHING`
+	if _, err := r.db.Exec(ctx, query, enc); err != nil {
+		return fmt.Errorf("failed to save master key canary: %w", err)
+	}
+	return nil
+}
+
+// LoadCanary retrieves the encrypted master key canary.
+// Returns nil, false, nil if no canary is stored yet.
+func (r *HealthRepository) LoadCanary(ctx context.Context) (_ []byte, _ bool, err error) {
+	ctx, span := startSpan(ctx, "HealthRepository.LoadCanary")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT value_encrypted FROM master_key_canary`
+	var enc []byte
+	if err := r.db.QueryRow(ctx, query).Scan(&enc); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to load master key canary: %w", err)
+	}
+	return enc, true, nil
+}
@@ -4,26 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // ItemRepository handles database operations for item and encrypted data entities.
+// It keeps the storage usage of every user up to date in the same transactions that change items
+// and rejects changes that would exceed the quota.
 type ItemRepository struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	quota models.Quota
 }
 
-// NewItemRepository creates a new item repository instance.
-func NewItemRepository(db *pgxpool.Pool) *ItemRepository {
-	return &ItemRepository{db: db}
+// NewItemRepository creates a new item repository instance enforcing the given storage quota.
+func NewItemRepository(db *pgxpool.Pool, quota models.Quota) *ItemRepository {
+	return &ItemRepository{db: db, quota: quota}
+}
+
+// querier is the subset of pgx functionality shared by connection pools and transactions.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
 // Create inserts a new item and its encrypted data into the database within a transaction.
 // The encrypted data is optional and can be nil.
-func (r *ItemRepository) Create(ctx context.Context, item *models.Item, encData *models.EncryptedData) error {
+// Returns models.ErrQuotaExceeded or models.ErrItemTooLarge if the item does not fit the user's quota.
+func (r *ItemRepository) Create(ctx context.Context, item *models.Item, encData *models.EncryptedData) (err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -34,32 +50,8 @@ func (r *ItemRepository) Create(ctx context.Context, item *models.Item, encData
 		}
 	}()
 
-	itemQuery := `
-		INSERT INTO items (id, user_id, type, title, metadata)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING created_at, updated_at
-	`
-
-	if err = tx.QueryRow(ctx, itemQuery,
-		item.ID, item.UserID, item.Type, item.Title, item.Metadata).
-		Scan(&item.CreatedAt, &item.UpdatedAt); err != nil {
-		return fmt.Errorf("failed to create item: %w", err)
-	}
-
-	if encData != nil {
-		encData.ItemID = item.ID
-
-		dataQuery := `
-			INSERT INTO encrypted_data (id, item_id, data_encrypted, data_key_encrypted)
-			VALUES ($1, $2, $3, $4)
-			RETURNING id
-		`
-
-		if err = tx.QueryRow(ctx, dataQuery,
-			encData.ID, encData.ItemID, encData.DataEncrypted, encData.DataKeyEncrypted).
-			Scan(&encData.ID); err != nil {
-			return fmt.Errorf("failed to create encrypted-data: %w", err)
-		}
+	if err = createItem(ctx, tx, item, encData, r.quota); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
@@ -71,8 +63,12 @@ func (r *ItemRepository) Create(ctx context.Context, item *models.Item, encData
 
 // Update modifies an existing item and optionally updates its encrypted data.
 // Only non-nil fields in the request are updated. Uses a transaction to ensure atomicity.
-// Returns models.ErrItemNotFound if the item doesn't exist or doesn't belong to the user.
-func (r *ItemRepository) Update(ctx context.Context, userID, itemID uuid.UUID, req *models.UpdateItemRequest, encData *models.EncryptedData) (*models.Item, error) {
+// Returns models.ErrItemNotFound if the item doesn't exist or doesn't belong to the user
+// and models.ErrQuotaExceeded or models.ErrItemTooLarge if the new data does not fit the user's quota.
+func (r *ItemRepository) Update(ctx context.Context, userID, itemID uuid.UUID, req *models.UpdateItemRequest, encData *models.EncryptedData) (_ *models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -83,66 +79,33 @@ func (r *ItemRepository) Update(ctx context.Context, userID, itemID uuid.UUID, r
 		}
 	}()
 
-	itemQuery := `
-		UPDATE items
-		SET
-			type = COALESCE($3::text, type),
-			title = COALESCE($4, title),
-			metadata = COALESCE($5, metadata),
-			updated_at = NOW()
-		WHERE id = $1 AND user_id = $2
-		RETURNING id, user_id, type, title, metadata, created_at, updated_at
-	`
-
-	var item models.Item
-	if err = tx.QueryRow(ctx, itemQuery,
-		itemID, userID, req.Type, req.Title, req.Metadata).
-		Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, models.ErrItemNotFound
-		}
-		return nil, fmt.Errorf("failed to update item: %w", err)
-	}
-
-	if encData != nil {
-		encData.ItemID = item.ID
-
-		dataQuery := `
-			INSERT INTO encrypted_data (id, item_id, data_encrypted, data_key_encrypted)
-			VALUES ($1, $2, $3, $4)
-			ON CONFLICT (item_id) DO UPDATE
-			SET 
-			    data_encrypted = EXCLUDED.data_encrypted,
-    			data_key_encrypted = EXCLUDED.data_key_encrypted
-			RETURNING id
-		`
-
-		if err = tx.QueryRow(ctx, dataQuery,
-			encData.ID, encData.ItemID, encData.DataEncrypted, encData.DataKeyEncrypted).
-			Scan(&encData.ID); err != nil {
-			return nil, fmt.Errorf("failed to update encrypted-data: %w", err)
-		}
+	item, err := updateItem(ctx, tx, userID, itemID, req, encData, r.quota)
+	if err != nil {
+		return nil, err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &item, nil
+	return item, nil
 }
 
 // GetByID retrieves an item and its encrypted data by ID for a specific user.
 // Returns the item and encrypted data (nil if no encrypted data exists).
 // Returns models.ErrItemNotFound if the item doesn't exist or doesn't belong to the user.
-func (r *ItemRepository) GetByID(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, *models.EncryptedData, error) {
+func (r *ItemRepository) GetByID(ctx context.Context, userID, itemID uuid.UUID) (_ *models.Item, _ *models.EncryptedData, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	itemQuery := `
-		SELECT id, user_id, type, title, metadata, created_at, updated_at
+		SELECT id, user_id, type, title, metadata, created_at, updated_at, expires_at
 		FROM items
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 	var item models.Item
 	if err := r.db.QueryRow(ctx, itemQuery, itemID, userID).
-		Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil, models.ErrItemNotFound
 		}
@@ -166,27 +129,109 @@ func (r *ItemRepository) GetByID(ctx context.Context, userID, itemID uuid.UUID)
 	return &item, &data, nil
 }
 
-// DeleteByID removes an item and its associated encrypted data from the database.
-// Returns models.ErrItemNotFound if the item doesn't exist or doesn't belong to the user.
-func (r *ItemRepository) DeleteByID(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error {
-	query := `DELETE FROM items WHERE id = $1 AND user_id = $2`
-	t, err := r.db.Exec(ctx, query, itemID, userID)
+// DeleteByID moves an item to the trash by setting its deletion timestamp.
+// The item and its encrypted data are kept until purged but no longer count against the quota.
+// Returns models.ErrItemNotFound if the item doesn't exist, is already deleted or doesn't belong to the user.
+func (r *ItemRepository) DeleteByID(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.DeleteByID")
+	defer func() { tracing.End(span, err) }()
+
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return deleteItem(ctx, tx, userID, itemID)
+	})
+}
+
+// ApplyBatch persists prepared item mutations in order.
+// In atomic mode all mutations share one transaction which is rolled back on the first failure.
+// Otherwise every mutation is applied in its own transaction regardless of earlier failures.
+// Returns one error slot per mutation and an error if the batch transaction itself fails.
+func (r *ItemRepository) ApplyBatch(ctx context.Context, userID uuid.UUID, muts []*models.ItemMutation, atomic bool) (_ []error, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.ApplyBatch")
+	defer func() { tracing.End(span, err) }()
+
+	errs := make([]error, len(muts))
+
+	if !atomic {
+		for i, m := range muts {
+			errs[i] = r.applyInOwnTx(ctx, userID, m)
+		}
+		return errs, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete item: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if t.RowsAffected() == 0 {
-		return models.ErrItemNotFound
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Lock the usage before any item, as single operations do, so batches cannot deadlock with them.
+	if _, err = lockUsage(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	for i, m := range muts {
+		if errs[i] = applyMutation(ctx, tx, userID, m, r.quota); errs[i] != nil {
+			return errs, nil
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return errs, nil
+}
+
+// applyInOwnTx applies a single mutation in its own transaction.
+func (r *ItemRepository) applyInOwnTx(ctx context.Context, userID uuid.UUID, m *models.ItemMutation) error {
+	switch m.Op {
+	case models.BatchOpCreate:
+		return r.Create(ctx, m.Item, m.EncData)
+	case models.BatchOpUpdate:
+		item, err := r.Update(ctx, userID, m.ItemID, m.Update, m.EncData)
+		if err != nil {
+			return err
+		}
+		m.Item = item
+		return nil
+	case models.BatchOpDelete:
+		return r.DeleteByID(ctx, userID, m.ItemID)
+	default:
+		return fmt.Errorf("unsupported batch operation %q", m.Op)
+	}
+}
+
+// applyMutation applies a single mutation within an existing transaction.
+func applyMutation(ctx context.Context, q querier, userID uuid.UUID, m *models.ItemMutation, quota models.Quota) error {
+	switch m.Op {
+	case models.BatchOpCreate:
+		return createItem(ctx, q, m.Item, m.EncData, quota)
+	case models.BatchOpUpdate:
+		item, err := updateItem(ctx, q, userID, m.ItemID, m.Update, m.EncData, quota)
+		if err != nil {
+			return err
+		}
+		m.Item = item
+		return nil
+	case models.BatchOpDelete:
+		return deleteItem(ctx, q, userID, m.ItemID)
+	default:
+		return fmt.Errorf("unsupported batch operation %q", m.Op)
 	}
-	return nil
 }
 
 // ListByUser retrieves all items belonging to a specific user.
 // Returns items sorted by update time in descending order.
-func (r *ItemRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Item, error) {
+func (r *ItemRepository) ListByUser(ctx context.Context, userID uuid.UUID) (_ []*models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.ListByUser")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		SELECT id, user_id, type, title, metadata, created_at, updated_at
+		SELECT id, user_id, type, title, metadata, created_at, updated_at, expires_at
 		FROM items
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 	`
 	rows, err := r.db.Query(ctx, query, userID)
@@ -198,7 +243,7 @@ func (r *ItemRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*m
 	var items []*models.Item
 	for rows.Next() {
 		var item models.Item
-		if err = rows.Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err = rows.Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt); err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
 		items = append(items, &item)
@@ -210,3 +255,304 @@ func (r *ItemRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*m
 
 	return items, nil
 }
+
+// ListDue retrieves items of a user that expire before expiresBefore or,
+// when updatedBefore is not nil, have not been updated since updatedBefore.
+// Returns items sorted by expiration time, items without expiration last.
+func (r *ItemRepository) ListDue(ctx context.Context, userID uuid.UUID, expiresBefore time.Time, updatedBefore *time.Time) (_ []*models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.ListDue")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, user_id, type, title, metadata, created_at, updated_at, expires_at
+		FROM items
+		WHERE user_id = $1 AND deleted_at IS NULL
+			AND (expires_at < $2 OR updated_at < $3)
+		ORDER BY expires_at ASC NULLS LAST, updated_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, expiresBefore, updatedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.Item
+	for rows.Next() {
+		var item models.Item
+		if err = rows.Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan due item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over due items: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListTrash retrieves all deleted items belonging to a specific user.
+// Returns items sorted by deletion time in descending order.
+func (r *ItemRepository) ListTrash(ctx context.Context, userID uuid.UUID) (_ []*models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.ListTrash")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT id, user_id, type, title, metadata, created_at, updated_at, expires_at, deleted_at
+		FROM items
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.Item
+	for rows.Next() {
+		var item models.Item
+		if err = rows.Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over deleted items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Restore moves a deleted item out of the trash, so it counts against the quota again.
+// Returns models.ErrItemNotFound if the item is not in the user's trash
+// and models.ErrQuotaExceeded or models.ErrItemTooLarge if it does not fit the user's quota.
+func (r *ItemRepository) Restore(ctx context.Context, userID, itemID uuid.UUID) (_ *models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	var item models.Item
+	err = pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		usage, err := lockUsage(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE items
+			SET deleted_at = NULL
+			WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+			RETURNING id, user_id, type, title, metadata, created_at, updated_at, expires_at
+		`
+		if err := tx.QueryRow(ctx, query, itemID, userID).
+			Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.ErrItemNotFound
+			}
+			return fmt.Errorf("failed to restore item: %w", err)
+		}
+
+		size, err := dataSize(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		return addUsage(ctx, tx, userID, usage, models.Usage{Items: 1, Bytes: size}, r.quota)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Purge permanently removes a deleted item and its encrypted data.
+// Returns models.ErrItemNotFound if the item is not in the user's trash.
+func (r *ItemRepository) Purge(ctx context.Context, userID, itemID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.Purge")
+	defer func() { tracing.End(span, err) }()
+
+	query := `DELETE FROM items WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL`
+	t, err := r.db.Exec(ctx, query, itemID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge item: %w", err)
+	}
+	if t.RowsAffected() == 0 {
+		return models.ErrItemNotFound
+	}
+	return nil
+}
+
+// PurgeAll permanently removes all deleted items of a user.
+// Returns the number of removed items.
+func (r *ItemRepository) PurgeAll(ctx context.Context, userID uuid.UUID) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.PurgeAll")
+	defer func() { tracing.End(span, err) }()
+
+	query := `DELETE FROM items WHERE user_id = $1 AND deleted_at IS NOT NULL`
+	t, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return t.RowsAffected(), nil
+}
+
+// PurgeDeletedBefore permanently removes items of all users deleted before the given time.
+// Returns the number of removed items.
+func (r *ItemRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.PurgeDeletedBefore")
+	defer func() { tracing.End(span, err) }()
+
+	query := `DELETE FROM items WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	t, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted items: %w", err)
+	}
+	return t.RowsAffected(), nil
+}
+
+// Usage returns the storage used by a user together with the quota.
+func (r *ItemRepository) Usage(ctx context.Context, userID uuid.UUID) (_ *models.UsageReport, err error) {
+	ctx, span := startSpan(ctx, "ItemRepository.Usage")
+	defer func() { tracing.End(span, err) }()
+
+	query := `SELECT items, bytes FROM user_usage WHERE user_id = $1`
+	report := &models.UsageReport{Quota: r.quota}
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&report.Usage.Items, &report.Usage.Bytes); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get usage: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// createItem inserts an item and its optional encrypted data using the given querier
+// and adds them to the usage of the owner.
+func createItem(ctx context.Context, q querier, item *models.Item, encData *models.EncryptedData, quota models.Quota) error {
+	usage, err := lockUsage(ctx, q, item.UserID)
+	if err != nil {
+		return err
+	}
+	if err = addUsage(ctx, q, item.UserID, usage, models.Usage{Items: 1, Bytes: encData.Size()}, quota); err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO items (id, user_id, type, title, metadata, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	if err := q.QueryRow(ctx, itemQuery,
+		item.ID, item.UserID, item.Type, item.Title, item.Metadata, item.ExpiresAt).
+		Scan(&item.CreatedAt, &item.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create item: %w", err)
+	}
+
+	if encData != nil {
+		encData.ItemID = item.ID
+
+		dataQuery := `
+			INSERT INTO encrypted_data (id, item_id, data_encrypted, data_key_encrypted)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`
+
+		if err := q.QueryRow(ctx, dataQuery,
+			encData.ID, encData.ItemID, encData.DataEncrypted, encData.DataKeyEncrypted).
+			Scan(&encData.ID); err != nil {
+			return fmt.Errorf("failed to create encrypted-data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updateItem modifies an item and upserts its optional encrypted data using the given querier.
+// New data changes the usage of the owner by the difference to the replaced data.
+// Returns models.ErrItemNotFound if the item doesn't exist or doesn't belong to the user.
+func updateItem(ctx context.Context, q querier, userID, itemID uuid.UUID, req *models.UpdateItemRequest, encData *models.EncryptedData, quota models.Quota) (*models.Item, error) {
+	var usage models.Usage
+	if encData != nil {
+		var err error
+		if usage, err = lockUsage(ctx, q, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	itemQuery := `
+		UPDATE items
+		SET
+			type = COALESCE($3::text, type),
+			title = COALESCE($4, title),
+			metadata = COALESCE($5, metadata),
+			expires_at = COALESCE($6, expires_at),
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING id, user_id, type, title, metadata, created_at, updated_at, expires_at
+	`
+
+	var item models.Item
+	if err := q.QueryRow(ctx, itemQuery,
+		itemID, userID, req.Type, req.Title, req.Metadata, req.ExpiresAt).
+		Scan(&item.ID, &item.UserID, &item.Type, &item.Title, &item.Metadata, &item.CreatedAt, &item.UpdatedAt, &item.ExpiresAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrItemNotFound
+		}
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	if encData != nil {
+		oldSize, err := dataSize(ctx, q, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err = addUsage(ctx, q, userID, usage, models.Usage{Bytes: encData.Size() - oldSize}, quota); err != nil {
+			return nil, err
+		}
+
+		encData.ItemID = item.ID
+
+		dataQuery := `
+			INSERT INTO encrypted_data (id, item_id, data_encrypted, data_key_encrypted)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (item_id) DO UPDATE
+			SET 
+			    data_encrypted = EXCLUDED.data_encrypted,
+    			data_key_encrypted = EXCLUDED.data_key_encrypted
+			RETURNING id
+		`
+
+		if err := q.QueryRow(ctx, dataQuery,
+			encData.ID, encData.ItemID, encData.DataEncrypted, encData.DataKeyEncrypted).
+			Scan(&encData.ID); err != nil {
+			return nil, fmt.Errorf("failed to update encrypted-data: %w", err)
+		}
+	}
+
+	return &item, nil
+}
+
+// deleteItem moves an item to the trash using the given querier and removes it from the usage of the owner.
+// Returns models.ErrItemNotFound if the item doesn't exist, is already deleted or doesn't belong to the user.
+func deleteItem(ctx context.Context, q querier, userID, itemID uuid.UUID) error {
+	usage, err := lockUsage(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE items SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+	t, err := q.Exec(ctx, query, itemID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete item: %w", err)
+	}
+	if t.RowsAffected() == 0 {
+		return models.ErrItemNotFound
+	}
+
+	size, err := dataSize(ctx, q, itemID)
+	if err != nil {
+		return err
+	}
+	return addUsage(ctx, q, userID, usage, models.Usage{Items: -1, Bytes: -size}, models.Quota{})
+}
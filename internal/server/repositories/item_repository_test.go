@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// returningPattern extracts the column list of a RETURNING clause.
+var returningPattern = regexp.MustCompile(`(?is)RETURNING\s+(.+?)\s*$`)
+
+// fakeQuerier answers queries without a database.
+// Like pgx, its rows fail to scan unless the destinations match the selected or returned columns.
+type fakeQuerier struct {
+	now time.Time
+}
+
+func (q *fakeQuerier) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (q *fakeQuerier) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	if strings.Contains(sql, "FROM user_usage") {
+		return fakeRow{values: []any{int64(0), int64(0)}}
+	}
+	m := returningPattern.FindStringSubmatch(sql)
+	if m == nil {
+		return fakeRow{err: fmt.Errorf("unexpected query: %s", sql)}
+	}
+
+	var values []any
+	for _, col := range strings.Split(m[1], ",") {
+		switch strings.TrimSpace(col) {
+		case "created_at", "updated_at":
+			values = append(values, q.now)
+		case "id":
+			values = append(values, uuid.New())
+		default:
+			return fakeRow{err: fmt.Errorf("unexpected column %q", col)}
+		}
+	}
+	return fakeRow{values: values}
+}
+
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d",
+			len(r.values), len(dest))
+	}
+	for i, v := range r.values {
+		switch d := dest[i].(type) {
+		case *time.Time:
+			*d = v.(time.Time)
+		case *uuid.UUID:
+			*d = v.(uuid.UUID)
+		case *int64:
+			*d = v.(int64)
+		default:
+			return fmt.Errorf("unsupported destination %T", d)
+		}
+	}
+	return nil
+}
+
+func testItem(expiresAt *time.Time) *models.Item {
+	return &models.Item{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Type:      models.ItemTypeText,
+		Title:     "note",
+		Metadata:  "{}",
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestCreateItem_ScansReturnedColumns(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Microsecond)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+	}{
+		{name: "without expiration"},
+		{name: "with expiration", expiresAt: &expiresAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQuerier{now: time.Now()}
+			item := testItem(tt.expiresAt)
+			encData := &models.EncryptedData{ID: uuid.New(), DataEncrypted: []byte("data"), DataKeyEncrypted: []byte("key")}
+
+			require.NoError(t, createItem(context.Background(), q, item, encData, models.Quota{}))
+
+			assert.Equal(t, q.now, item.CreatedAt)
+			assert.Equal(t, q.now, item.UpdatedAt)
+			assert.Equal(t, tt.expiresAt, item.ExpiresAt)
+			assert.Equal(t, item.ID, encData.ItemID)
+		})
+	}
+}
+
+// TestItemRepository_CreateRoundTrip runs against the database given in TEST_DATABASE_DSN.
+func TestItemRepository_CreateRoundTrip(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	m, err := migrate.New("file://../app/migrations", dsn)
+	require.NoError(t, err)
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+	_, _ = m.Close()
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	user := &models.User{ID: uuid.New(), Username: "repo-test-" + uuid.NewString()[:8], PasswordHash: "hash"}
+	require.NoError(t, NewUserRepository(db).CreateUser(ctx, user))
+	t.Cleanup(func() { _, _ = db.Exec(ctx, `DELETE FROM users WHERE id = $1`, user.ID) })
+
+	items := NewItemRepository(db, models.Quota{})
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Microsecond)
+
+	for _, exp := range []*time.Time{nil, &expiresAt} {
+		item := testItem(exp)
+		item.UserID = user.ID
+		encData := &models.EncryptedData{ID: uuid.New(), DataEncrypted: []byte("data"), DataKeyEncrypted: []byte("key")}
+
+		require.NoError(t, items.Create(ctx, item, encData))
+
+		got, gotData, err := items.GetByID(ctx, user.ID, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item.Title, got.Title)
+		assert.WithinDuration(t, item.CreatedAt, got.CreatedAt, 0)
+		if exp == nil {
+			assert.Nil(t, got.ExpiresAt)
+		} else {
+			require.NotNil(t, got.ExpiresAt)
+			assert.True(t, exp.Equal(*got.ExpiresAt))
+		}
+		assert.Equal(t, encData.DataEncrypted, gotData.DataEncrypted)
+	}
+}
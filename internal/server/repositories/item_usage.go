@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// lockUsage returns the storage usage of a user and locks it until the transaction ends.
+// Every change of the usage locks it before touching items, so concurrent changes of the same user
+// are serialized and always acquire their locks in the same order.
+func lockUsage(ctx context.Context, q querier, userID uuid.UUID) (models.Usage, error) {
+	insertQuery := `INSERT INTO user_usage (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`
+	if _, err := q.Exec(ctx, insertQuery, userID); err != nil {
+		return models.Usage{}, fmt.Errorf("failed to create usage: %w", err)
+	}
+
+	selectQuery := `SELECT items, bytes FROM user_usage WHERE user_id = $1 FOR UPDATE`
+	var usage models.Usage
+	if err := q.QueryRow(ctx, selectQuery, userID).Scan(&usage.Items, &usage.Bytes); err != nil {
+		return models.Usage{}, fmt.Errorf("failed to lock usage: %w", err)
+	}
+	return usage, nil
+}
+
+// addUsage checks a change of the locked usage against the quota and saves it.
+// Returns models.ErrQuotaExceeded or models.ErrItemTooLarge if the change does not fit the quota.
+func addUsage(ctx context.Context, q querier, userID uuid.UUID, usage, delta models.Usage, quota models.Quota) error {
+	if delta == (models.Usage{}) {
+		return nil
+	}
+	if err := quota.Check(usage, delta); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE user_usage
+		SET items = GREATEST(items + $2, 0), bytes = GREATEST(bytes + $3, 0), updated_at = NOW()
+		WHERE user_id = $1
+	`
+	if _, err := q.Exec(ctx, query, userID, delta.Items, delta.Bytes); err != nil {
+		return fmt.Errorf("failed to update usage: %w", err)
+	}
+	return nil
+}
+
+// dataSize returns the size of the encrypted data of an item, zero if the item has no data.
+func dataSize(ctx context.Context, q querier, itemID uuid.UUID) (int64, error) {
+	query := `SELECT OCTET_LENGTH(data_encrypted) FROM encrypted_data WHERE item_id = $1`
+	var size int64
+	if err := q.QueryRow(ctx, query, itemID).Scan(&size); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get data size: %w", err)
+	}
+	return size, nil
+}
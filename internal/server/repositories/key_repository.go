@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -22,7 +23,10 @@ func NewKeyRepository(db *pgxpool.Pool) *KeyRepository {
 
 // Save stores or updates an encrypted user key in the database.
 // Uses upsert to create or update the key for the specified user.
-func (r *KeyRepository) Save(ctx context.Context, userID uuid.UUID, enc []byte) error {
+func (r *KeyRepository) Save(ctx context.Context, userID uuid.UUID, enc []byte) (err error) {
+	ctx, span := startSpan(ctx, "KeyRepository.Save")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO encryption_keys (user_id, key_encrypted) 
 		VALUES ($1, $2)
@@ -37,7 +41,10 @@ func (r *KeyRepository) Save(ctx context.Context, userID uuid.UUID, enc []byte)
 // Load retrieves an encrypted user key from the database.
 // Returns the encrypted key, true if found, and any error.
 // Returns nil, false, nil if the key doesn't exist.
-func (r *KeyRepository) Load(ctx context.Context, userID uuid.UUID) ([]byte, bool, error) {
+func (r *KeyRepository) Load(ctx context.Context, userID uuid.UUID) (_ []byte, _ bool, err error) {
+	ctx, span := startSpan(ctx, "KeyRepository.Load")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		SELECT key_encrypted 
 		FROM encryption_keys 
@@ -8,14 +8,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName identifies the spans created by the repositories, the queries themselves are traced by pgx.
+const tracerName = "github.com/Pro100x3mal/gophkeeper/internal/server/repositories"
+
+// startSpan starts a span with the global tracer provider.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
 // UserRepository handles database operations for user entities.
 type UserRepository struct {
 	db *pgxpool.Pool
@@ -28,7 +40,10 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 
 // CreateUser inserts a new user into the database.
 // Returns models.ErrUserAlreadyExists if the username is already taken.
-func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
 		INSERT INTO users (id, username, password_hash)
 		VALUES ($1, $2, $3)
@@ -52,17 +67,30 @@ func isUniqueViolation(err error) bool {
 	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
 
+// userColumns are the columns of the users table scanned by userFields.
+const userColumns = `id, username, password_hash, role, disabled_at, tokens_valid_after, created_at, updated_at`
+
+// userFields returns the scan destinations for userColumns.
+func userFields(user *models.User) []any {
+	return []any{
+		&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.DisabledAt, &user.TokensValidAfter,
+		&user.CreatedAt, &user.UpdatedAt,
+	}
+}
+
 // GetUserByID retrieves a user by their ID.
 // Returns models.ErrUserNotFound if the user does not exist.
-func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetUserByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1
 	`
 	var user models.User
-	if err := r.db.QueryRow(ctx, query, id).
-		Scan(&user.ID, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err := r.db.QueryRow(ctx, query, id).Scan(userFields(&user)...); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
@@ -74,15 +102,17 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models
 
 // GetUserByUsername retrieves a user by their username.
 // Returns models.ErrUserNotFound if the user does not exist.
-func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetUserByUsername")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		SELECT id, username, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE username = $1
 	`
 	var user models.User
-	if err := r.db.QueryRow(ctx, query, username).
-		Scan(&user.ID, &user.Username, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt); err != nil {
+	if err := r.db.QueryRow(ctx, query, username).Scan(userFields(&user)...); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
@@ -91,3 +121,186 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
 
 	return &user, nil
 }
+
+// ListUsers returns all users with their storage usage, ordered by username.
+func (r *UserRepository) ListUsers(ctx context.Context) (_ []*models.UserInfo, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		SELECT u.id, u.username, u.password_hash, u.role, u.disabled_at, u.tokens_valid_after,
+		       u.created_at, u.updated_at, COALESCE(uu.items, 0), COALESCE(uu.bytes, 0)
+		FROM users u
+		LEFT JOIN user_usage uu ON uu.user_id = u.id
+		ORDER BY u.username
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.UserInfo, 0)
+	for rows.Next() {
+		var info models.UserInfo
+		dest := append(userFields(&info.User), &info.Usage.Items, &info.Usage.Bytes)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, nil
+}
+
+// DisableUser disables a user and rejects the tokens issued before tokensValidAfter.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) DisableUser(ctx context.Context, id uuid.UUID, tokensValidAfter time.Time) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.DisableUser")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET disabled_at = COALESCE(disabled_at, CURRENT_TIMESTAMP),
+		    tokens_valid_after = $2,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	return r.execUser(ctx, "disable user", query, id, tokensValidAfter)
+}
+
+// EnableUser re-enables a disabled user.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) EnableUser(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.EnableUser")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET disabled_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	return r.execUser(ctx, "enable user", query, id)
+}
+
+// RevokeUserTokens rejects the tokens of a user issued before validAfter.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) RevokeUserTokens(ctx context.Context, id uuid.UUID, validAfter time.Time) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.RevokeUserTokens")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET tokens_valid_after = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	return r.execUser(ctx, "revoke user tokens", query, id, validAfter)
+}
+
+// UpdatePassword replaces the password hash of a user and rejects the tokens issued before tokensValidAfter.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, tokensValidAfter time.Time) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.UpdatePassword")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET password_hash = $2, tokens_valid_after = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	return r.execUser(ctx, "update password", query, id, passwordHash, tokensValidAfter)
+}
+
+// execUser runs a statement that updates or deletes a single user.
+// Returns models.ErrUserNotFound if no row was affected.
+func (r *UserRepository) execUser(ctx context.Context, op, query string, args ...any) error {
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
+
+// RenameUser changes the username of a user and returns the updated user.
+// Returns models.ErrUserAlreadyExists if the username is taken and models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) RenameUser(ctx context.Context, id uuid.UUID, username string) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.RenameUser")
+	defer func() { tracing.End(span, err) }()
+
+	query := `
+		UPDATE users
+		SET username = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + userColumns
+	var user models.User
+	if err := r.db.QueryRow(ctx, query, id, username).Scan(userFields(&user)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, models.ErrUserAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to rename user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// DeleteUser removes a user. The keys, items and usage of the user are removed by the database cascade.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (r *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "UserRepository.DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	return r.execUser(ctx, "delete user", `DELETE FROM users WHERE id = $1`, id)
+}
+
+// SyncAdmins makes the users with the given usernames admins and demotes all other admins.
+// Returns the usernames that do not belong to any user.
+func (r *UserRepository) SyncAdmins(ctx context.Context, usernames []string) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "UserRepository.SyncAdmins")
+	defer func() { tracing.End(span, err) }()
+
+	if usernames == nil {
+		usernames = []string{}
+	}
+
+	var missing []string
+	err = pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `
+			UPDATE users
+			SET role = CASE WHEN username = ANY($1) THEN 'admin' ELSE 'user' END,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE (role = 'admin') <> (username = ANY($1))
+		`
+		if _, err := tx.Exec(ctx, query, usernames); err != nil {
+			return fmt.Errorf("failed to update roles: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT a.username
+			FROM unnest($1::TEXT[]) AS a(username)
+			WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.username = a.username)
+			ORDER BY a.username
+		`, usernames)
+		if err != nil {
+			return fmt.Errorf("failed to find missing admins: %w", err)
+		}
+		missing, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("failed to find missing admins: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
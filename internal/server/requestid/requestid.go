@@ -0,0 +1,57 @@
+// Package requestid carries the correlation ID of an HTTP request through the server.
+//
+// The ID is taken from the X-Request-ID header sent by the client or generated by the
+// middleware, stored in the request context and added to log lines with Field.
+package requestid
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Header is the HTTP header carrying the request ID in both directions.
+const Header = "X-Request-ID"
+
+// maxLength limits the length of IDs accepted from clients.
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in the context, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Field returns the zap field with the request ID of the context.
+// It is skipped when the context carries no ID, e.g. in background jobs.
+func Field(ctx context.Context) zap.Field {
+	id := FromContext(ctx)
+	if id == "" {
+		return zap.Skip()
+	}
+	return zap.String("request_id", id)
+}
+
+// Valid reports whether an ID received from a client can be used as is.
+// Only short IDs of letters, digits and -_.: are accepted, so they are safe to log and echo back.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
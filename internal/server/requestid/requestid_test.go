@@ -0,0 +1,40 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"5f0c6e2a-8f7d-4a51-9d1e-1b2c3d4e5f60", true},
+		{"req_42.retry:1", true},
+		{"", false},
+		{"has space", false},
+		{"line\nbreak", false},
+		{"ünïcode", false},
+		{strings.Repeat("a", maxLength), true},
+		{strings.Repeat("a", maxLength+1), false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, Valid(tt.id), tt.id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FromContext(ctx))
+	assert.Equal(t, zap.Skip(), Field(ctx))
+
+	ctx = NewContext(ctx, "abc-123")
+	assert.Equal(t, "abc-123", FromContext(ctx))
+	assert.Equal(t, zap.String("request_id", "abc-123"), Field(ctx))
+}
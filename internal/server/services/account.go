@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+)
+
+// ErrAdminRename is returned when an admin tries to change their username.
+// Admins are listed by username in the server configuration, a renamed admin would lose the role
+// and the old username could be registered by someone else.
+var ErrAdminRename = errors.New("admins cannot change their username")
+
+// GetUser returns a user by ID.
+// Returns models.ErrUserNotFound if the user does not exist.
+func (as *AuthService) GetUser(ctx context.Context, userID uuid.UUID) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.GetUser")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := as.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the password of a user after verifying the old one.
+// All tokens of the user issued before are revoked, the returned token replaces them.
+// Returns ErrInvalidCredentials if the old password is incorrect.
+func (as *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) (_ string, err error) {
+	ctx, span := startSpan(ctx, "AuthService.ChangePassword")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := as.reauthenticate(ctx, userID, oldPassword)
+	if err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := hashPassword(ctx, newPassword)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// Tokens carry the issue time in whole seconds: the new token is issued in the current second or later,
+	// so only the tokens issued before it are revoked.
+	validAfter := time.Now().Truncate(time.Second)
+	token, err := as.jwtGen.GenerateToken(user.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JWT token: %w", err)
+	}
+
+	if err = as.userRepo.UpdatePassword(ctx, user.ID, string(hashedPassword), validAfter); err != nil {
+		return "", fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return token, nil
+}
+
+// ChangeUsername renames a user and returns the updated user.
+// Returns models.ErrUserAlreadyExists if the username is taken, ErrUsernameReserved
+// for configured admin usernames and ErrAdminRename for admins.
+func (as *AuthService) ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.ChangeUsername")
+	defer func() { tracing.End(span, err) }()
+
+	if as.admins[username] {
+		return nil, ErrUsernameReserved
+	}
+
+	user, err := as.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsAdmin() {
+		return nil, ErrAdminRename
+	}
+
+	user, err = as.userRepo.RenameUser(ctx, userID, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename user: %w", err)
+	}
+	return user, nil
+}
+
+// DeleteAccount removes a user together with their keys and items after verifying the password.
+// Returns ErrInvalidCredentials if the password is incorrect.
+func (as *AuthService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.DeleteAccount")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err = as.reauthenticate(ctx, userID, password); err != nil {
+		return err
+	}
+	if err = as.userRepo.DeleteUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+// reauthenticate verifies the password of an authenticated user before a sensitive change.
+// Returns ErrInvalidCredentials if the password is incorrect.
+func (as *AuthService) reauthenticate(ctx context.Context, userID uuid.UUID, password string) (*models.User, error) {
+	user, err := as.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err = comparePasswordHash(ctx, user.PasswordHash, password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testAccount returns a user with the given password, hashed with the minimum cost to keep the tests fast.
+func testAccount(t *testing.T, password string) *models.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &models.User{ID: uuid.New(), Username: "testuser", PasswordHash: string(hash), Role: models.RoleUser}
+}
+
+func TestAuthService_GetUser(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	missingID := uuid.New()
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("GetUserByID", mock.Anything, missingID).Return(nil, models.ErrUserNotFound)
+
+	got, err := service.GetUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	_, err = service.GetUser(context.Background(), missingID)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	jwtGen := jwt.NewGenerator("secret", time.Hour)
+	service := NewAuthService(mockRepo, jwtGen)
+	user := testAccount(t, "old-password")
+
+	var newHash string
+	var validAfter time.Time
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, user.ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			newHash = args.String(2)
+			validAfter = args.Get(3).(time.Time)
+		}).Return(nil)
+
+	token, err := service.ChangePassword(context.Background(), user.ID, "old-password", "new-password")
+
+	require.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(newHash), []byte("new-password")))
+
+	claims, err := jwtGen.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.False(t, claims.IssuedAt.Before(validAfter), "the new token must survive the revocation")
+}
+
+func TestAuthService_ChangePassword_WrongPassword(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "old-password")
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	token, err := service.ChangePassword(context.Background(), user.ID, "wrong", "new-password")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Empty(t, token)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ChangeUsername(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	renamed := &models.User{ID: user.ID, Username: "newname"}
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("RenameUser", mock.Anything, user.ID, "newname").Return(renamed, nil)
+
+	got, err := service.ChangeUsername(context.Background(), user.ID, "newname")
+
+	require.NoError(t, err)
+	assert.Equal(t, renamed, got)
+}
+
+func TestAuthService_ChangeUsername_Taken(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("RenameUser", mock.Anything, user.ID, "taken").Return(nil, models.ErrUserAlreadyExists)
+
+	_, err := service.ChangeUsername(context.Background(), user.ID, "taken")
+
+	assert.ErrorIs(t, err, models.ErrUserAlreadyExists)
+}
+
+func TestAuthService_ChangeUsername_AdminUsername(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	service.SetAdminUsernames([]string{"boss"})
+	user := testAccount(t, "password")
+
+	_, err := service.ChangeUsername(context.Background(), user.ID, "boss")
+
+	require.ErrorIs(t, err, ErrUsernameReserved)
+	assert.ErrorIs(t, err, models.ErrUserAlreadyExists)
+	mockRepo.AssertNotCalled(t, "RenameUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_ChangeUsername_Admin(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	user.Role = models.RoleAdmin
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	_, err := service.ChangeUsername(context.Background(), user.ID, "newname")
+
+	assert.ErrorIs(t, err, ErrAdminRename)
+	mockRepo.AssertNotCalled(t, "RenameUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_DeleteAccount(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+	mockRepo.On("DeleteUser", mock.Anything, user.ID).Return(nil)
+
+	require.NoError(t, service.DeleteAccount(context.Background(), user.ID, "password"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_DeleteAccount_WrongPassword(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	user := testAccount(t, "password")
+	mockRepo.On("GetUserByID", mock.Anything, user.ID).Return(user, nil)
+
+	err := service.DeleteAccount(context.Background(), user.ID, "wrong")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	mockRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+}
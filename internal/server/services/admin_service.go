@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+)
+
+// ErrSelfAdminAction is returned when an admin tries to disable or delete their own account.
+var ErrSelfAdminAction = errors.New("admins cannot disable or delete their own account")
+
+// AdminRepo defines the repository contract for user management.
+type AdminRepo interface {
+	ListUsers(ctx context.Context) ([]*models.UserInfo, error)
+	DisableUser(ctx context.Context, id uuid.UUID, tokensValidAfter time.Time) error
+	EnableUser(ctx context.Context, id uuid.UUID) error
+	RevokeUserTokens(ctx context.Context, id uuid.UUID, validAfter time.Time) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	SyncAdmins(ctx context.Context, usernames []string) ([]string, error)
+}
+
+// AdminService handles the user management operations of admins.
+type AdminService struct {
+	repo AdminRepo
+}
+
+// NewAdminService creates a new admin service instance.
+func NewAdminService(repo AdminRepo) *AdminService {
+	return &AdminService{repo: repo}
+}
+
+// SyncAdmins gives the admin role to exactly the users with the given usernames.
+// Returns the usernames that do not belong to any user yet.
+func (s *AdminService) SyncAdmins(ctx context.Context, usernames []string) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "AdminService.SyncAdmins")
+	defer func() { tracing.End(span, err) }()
+
+	missing, err := s.repo.SyncAdmins(ctx, usernames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync admins: %w", err)
+	}
+	return missing, nil
+}
+
+// ListUsers returns all users with their storage usage.
+func (s *AdminService) ListUsers(ctx context.Context) (_ []*models.UserInfo, err error) {
+	ctx, span := startSpan(ctx, "AdminService.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	users, err := s.repo.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+// DisableUser disables a user and revokes their tokens.
+// Returns ErrSelfAdminAction if the admin targets their own account.
+func (s *AdminService) DisableUser(ctx context.Context, adminID, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "AdminService.DisableUser")
+	defer func() { tracing.End(span, err) }()
+
+	if adminID == userID {
+		return ErrSelfAdminAction
+	}
+	if err = s.repo.DisableUser(ctx, userID, revokeCutoff(time.Now())); err != nil {
+		return fmt.Errorf("failed to disable user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// EnableUser re-enables a disabled user. The tokens revoked when the user was disabled stay invalid.
+func (s *AdminService) EnableUser(ctx context.Context, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "AdminService.EnableUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.repo.EnableUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to enable user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// LogoutUser revokes all tokens issued to a user so far.
+func (s *AdminService) LogoutUser(ctx context.Context, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "AdminService.LogoutUser")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.repo.RevokeUserTokens(ctx, userID, revokeCutoff(time.Now())); err != nil {
+		return fmt.Errorf("failed to log out user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteUser removes a user together with their keys and items.
+// Returns ErrSelfAdminAction if the admin targets their own account.
+func (s *AdminService) DeleteUser(ctx context.Context, adminID, userID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "AdminService.DeleteUser")
+	defer func() { tracing.End(span, err) }()
+
+	if adminID == userID {
+		return ErrSelfAdminAction
+	}
+	if err = s.repo.DeleteUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// revokeCutoff returns the time before which tokens are rejected to revoke every token issued until now.
+// Tokens carry the issue time in whole seconds, so the tokens issued in the current second are revoked as well.
+func revokeCutoff(now time.Time) time.Time {
+	return now.Truncate(time.Second).Add(time.Second)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAdminRepo is a mock implementation of AdminRepo
+type MockAdminRepo struct {
+	mock.Mock
+}
+
+func (m *MockAdminRepo) ListUsers(ctx context.Context) ([]*models.UserInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UserInfo), args.Error(1)
+}
+
+func (m *MockAdminRepo) DisableUser(ctx context.Context, id uuid.UUID, tokensValidAfter time.Time) error {
+	args := m.Called(ctx, id, tokensValidAfter)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepo) EnableUser(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepo) RevokeUserTokens(ctx context.Context, id uuid.UUID, validAfter time.Time) error {
+	args := m.Called(ctx, id, validAfter)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepo) SyncAdmins(ctx context.Context, usernames []string) ([]string, error) {
+	args := m.Called(ctx, usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	users := []*models.UserInfo{{User: models.User{ID: uuid.New(), Username: "alice"}}}
+	mockRepo.On("ListUsers", mock.Anything).Return(users, nil)
+
+	got, err := service.ListUsers(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, users, got)
+}
+
+func TestAdminService_DisableUser(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	adminID, userID := uuid.New(), uuid.New()
+	var cutoff time.Time
+	mockRepo.On("DisableUser", mock.Anything, userID, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		cutoff = args.Get(2).(time.Time)
+	}).Return(nil)
+
+	require.NoError(t, service.DisableUser(context.Background(), adminID, userID))
+	mockRepo.AssertExpectations(t)
+	assert.True(t, cutoff.After(time.Now()), "tokens issued in the current second are revoked")
+}
+
+func TestAdminService_EnableUser_NotFound(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	userID := uuid.New()
+	mockRepo.On("EnableUser", mock.Anything, userID).Return(models.ErrUserNotFound)
+
+	err := service.EnableUser(context.Background(), userID)
+
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestAdminService_LogoutUser(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	userID := uuid.New()
+	mockRepo.On("RevokeUserTokens", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(errors.New("database error"))
+
+	err := service.LogoutUser(context.Background(), userID)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to log out user")
+}
+
+func TestAdminService_DeleteUser(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	adminID, userID := uuid.New(), uuid.New()
+	mockRepo.On("DeleteUser", mock.Anything, userID).Return(nil)
+
+	require.NoError(t, service.DeleteUser(context.Background(), adminID, userID))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAdminService_SelfAction(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	adminID := uuid.New()
+
+	assert.ErrorIs(t, service.DisableUser(context.Background(), adminID, adminID), ErrSelfAdminAction)
+	assert.ErrorIs(t, service.DeleteUser(context.Background(), adminID, adminID), ErrSelfAdminAction)
+	mockRepo.AssertNotCalled(t, "DisableUser", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+}
+
+func TestAdminService_SyncAdmins(t *testing.T) {
+	mockRepo := new(MockAdminRepo)
+	service := NewAdminService(mockRepo)
+	mockRepo.On("SyncAdmins", mock.Anything, []string{"alice", "bob"}).Return([]string{"bob"}, nil)
+
+	missing, err := service.SyncAdmins(context.Background(), []string{"alice", "bob"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, missing)
+}
+
+func TestRevokeCutoff(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC), revokeCutoff(now))
+}
@@ -8,27 +8,54 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/Pro100x3mal/gophkeeper/pkg/jwt"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// ErrInvalidCredentials is returned when username or password is incorrect.
-var ErrInvalidCredentials = errors.New("invalid credentials")
+var (
+	// ErrInvalidCredentials is returned when username or password is incorrect.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrSessionRevoked is returned for tokens of deleted users and tokens revoked by an admin.
+	ErrSessionRevoked = errors.New("session revoked")
+
+	// ErrUsernameReserved is returned for usernames listed as admins in the server configuration.
+	// It wraps models.ErrUserAlreadyExists, so a reserved username looks taken to the client.
+	ErrUsernameReserved = fmt.Errorf("username is reserved for an admin: %w", models.ErrUserAlreadyExists)
+)
+
+// tracerName identifies the spans created by the services.
+const tracerName = "github.com/Pro100x3mal/gophkeeper/internal/server/services"
+
+// startSpan starts a span with the global tracer provider.
+// The tracer is looked up on every call, so a provider installed after start-up is used.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
 
 // UserRepo defines the user repository contract.
 type UserRepo interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, tokensValidAfter time.Time) error
+	RenameUser(ctx context.Context, id uuid.UUID, username string) (*models.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
 
 // AuthService handles user authentication and registration operations.
 type AuthService struct {
 	userRepo UserRepo
 	jwtGen   *jwt.Generator
+	observer Observer
+	admins   map[string]bool
 }
 
 // NewAuthService creates a new authentication service instance.
@@ -36,12 +63,40 @@ func NewAuthService(userRepo UserRepo, jwtGen *jwt.Generator) *AuthService {
 	return &AuthService{
 		userRepo: userRepo,
 		jwtGen:   jwtGen,
+		observer: nopObserver{},
+	}
+}
+
+// SetObserver sets the observer that receives the results of login and registration attempts.
+func (as *AuthService) SetObserver(o Observer) {
+	as.observer = o
+}
+
+// SetAdminUsernames reserves the configured admin usernames.
+// The admin role is given on start only to users that already hold these usernames,
+// so nobody can register them and become an admin on the next start.
+func (as *AuthService) SetAdminUsernames(usernames []string) {
+	as.admins = make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		as.admins[username] = true
 	}
 }
 
 // Register creates a new user account with hashed password and returns a JWT token.
 func (as *AuthService) Register(ctx context.Context, username, password string) (*models.User, string, error) {
-	hashedPassword, err := hashPassword(password)
+	ctx, span := startSpan(ctx, "AuthService.Register")
+	user, token, err := as.register(ctx, username, password)
+	tracing.End(span, err)
+	as.observer.ObserveAuth(AuthOpRegister, authResult(err))
+	return user, token, err
+}
+
+func (as *AuthService) register(ctx context.Context, username, password string) (*models.User, string, error) {
+	if as.admins[username] {
+		return nil, "", ErrUsernameReserved
+	}
+
+	hashedPassword, err := hashPassword(ctx, password)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -50,6 +105,7 @@ func (as *AuthService) Register(ctx context.Context, username, password string)
 		ID:           uuid.New(),
 		Username:     username,
 		PasswordHash: string(hashedPassword),
+		Role:         models.RoleUser,
 	}
 
 	if err = as.userRepo.CreateUser(ctx, user); err != nil {
@@ -68,8 +124,17 @@ func (as *AuthService) Register(ctx context.Context, username, password string)
 }
 
 // Login authenticates a user by username and password, returning a JWT token.
-// Returns ErrInvalidCredentials if username or password is incorrect.
+// Returns ErrInvalidCredentials if username or password is incorrect
+// and models.ErrUserDisabled if the user was disabled by an admin.
 func (as *AuthService) Login(ctx context.Context, username, password string) (*models.User, string, error) {
+	ctx, span := startSpan(ctx, "AuthService.Login")
+	user, token, err := as.login(ctx, username, password)
+	tracing.End(span, err)
+	as.observer.ObserveAuth(AuthOpLogin, authResult(err))
+	return user, token, err
+}
+
+func (as *AuthService) login(ctx context.Context, username, password string) (*models.User, string, error) {
 	user, err := as.userRepo.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, models.ErrUserNotFound) {
@@ -78,9 +143,12 @@ func (as *AuthService) Login(ctx context.Context, username, password string) (*m
 		return nil, "", fmt.Errorf("failed to get user: %w", err)
 	}
 
-	if err = comparePasswordHash(user.PasswordHash, password); err != nil {
+	if err = comparePasswordHash(ctx, user.PasswordHash, password); err != nil {
 		return nil, "", ErrInvalidCredentials
 	}
+	if user.DisabledAt != nil {
+		return nil, "", models.ErrUserDisabled
+	}
 
 	token, err := as.jwtGen.GenerateToken(user.ID)
 	if err != nil {
@@ -90,12 +158,41 @@ func (as *AuthService) Login(ctx context.Context, username, password string) (*m
 	return user, token, nil
 }
 
+// CheckSession verifies that a token issued to the user at issuedAt may still be used.
+// Returns the user, ErrSessionRevoked if the user was deleted or the token revoked,
+// and models.ErrUserDisabled if the user is disabled.
+func (as *AuthService) CheckSession(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (_ *models.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.CheckSession")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := as.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.DisabledAt != nil {
+		return nil, models.ErrUserDisabled
+	}
+	if user.TokensValidAfter != nil && issuedAt.Before(*user.TokensValidAfter) {
+		return nil, ErrSessionRevoked
+	}
+
+	return user, nil
+}
+
 // hashPassword hashes a password using bcrypt with default cost.
-func hashPassword(password string) ([]byte, error) {
+func hashPassword(ctx context.Context, password string) ([]byte, error) {
+	_, span := startSpan(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
 	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 }
 
 // comparePasswordHash verifies a password against its bcrypt hash.
-func comparePasswordHash(hashedPassword string, password string) error {
+// A mismatch is an expected outcome and is not recorded as a span error.
+func comparePasswordHash(ctx context.Context, hashedPassword string, password string) error {
+	_, span := startSpan(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
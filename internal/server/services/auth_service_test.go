@@ -41,6 +41,24 @@ func (m *MockUserRepo) GetUserByUsername(ctx context.Context, username string) (
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string, tokensValidAfter time.Time) error {
+	args := m.Called(ctx, id, passwordHash, tokensValidAfter)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) RenameUser(ctx context.Context, id uuid.UUID, username string) (*models.User, error) {
+	args := m.Called(ctx, id, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestNewAuthService(t *testing.T) {
 	mockRepo := new(MockUserRepo)
 	jwtGen := jwt.NewGenerator("secret", time.Hour)
@@ -61,7 +79,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 	username := "testuser"
 	password := "password123"
 
-	mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*models.User")).Return(nil)
+	mockRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
 
 	user, token, err := service.Register(ctx, username, password)
 
@@ -87,7 +105,7 @@ func TestAuthService_Register_UserAlreadyExists(t *testing.T) {
 	username := "existinguser"
 	password := "password123"
 
-	mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*models.User")).
+	mockRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).
 		Return(models.ErrUserAlreadyExists)
 
 	user, token, err := service.Register(ctx, username, password)
@@ -100,6 +118,20 @@ func TestAuthService_Register_UserAlreadyExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Register_AdminUsername(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	service.SetAdminUsernames([]string{"boss"})
+
+	user, token, err := service.Register(context.Background(), "boss", "password123")
+
+	require.ErrorIs(t, err, ErrUsernameReserved)
+	assert.ErrorIs(t, err, models.ErrUserAlreadyExists)
+	assert.Nil(t, user)
+	assert.Empty(t, token)
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
 func TestAuthService_Register_CreateUserError(t *testing.T) {
 	mockRepo := new(MockUserRepo)
 	jwtGen := jwt.NewGenerator("secret", time.Hour)
@@ -109,7 +141,7 @@ func TestAuthService_Register_CreateUserError(t *testing.T) {
 	username := "testuser"
 	password := "password123"
 
-	mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*models.User")).
+	mockRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).
 		Return(errors.New("database error"))
 
 	user, token, err := service.Register(ctx, username, password)
@@ -140,7 +172,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 		UpdatedAt:    time.Now(),
 	}
 
-	mockRepo.On("GetUserByUsername", ctx, username).Return(existingUser, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(existingUser, nil)
 
 	user, token, err := service.Login(ctx, username, password)
 
@@ -161,7 +193,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	username := "nonexistent"
 	password := "password123"
 
-	mockRepo.On("GetUserByUsername", ctx, username).Return(nil, models.ErrUserNotFound)
+	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(nil, models.ErrUserNotFound)
 
 	user, token, err := service.Login(ctx, username, password)
 
@@ -192,7 +224,7 @@ func TestAuthService_Login_WrongPassword(t *testing.T) {
 		UpdatedAt:    time.Now(),
 	}
 
-	mockRepo.On("GetUserByUsername", ctx, username).Return(existingUser, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(existingUser, nil)
 
 	user, token, err := service.Login(ctx, username, wrongPassword)
 
@@ -213,7 +245,7 @@ func TestAuthService_Login_GetUserError(t *testing.T) {
 	username := "testuser"
 	password := "password123"
 
-	mockRepo.On("GetUserByUsername", ctx, username).Return(nil, errors.New("database error"))
+	mockRepo.On("GetUserByUsername", mock.Anything, username).Return(nil, errors.New("database error"))
 
 	user, token, err := service.Login(ctx, username, password)
 
@@ -225,10 +257,83 @@ func TestAuthService_Login_GetUserError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Login_Disabled(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	disabledAt := time.Now()
+	mockRepo.On("GetUserByUsername", mock.Anything, "testuser").Return(&models.User{
+		ID:           uuid.New(),
+		Username:     "testuser",
+		PasswordHash: string(hashedPassword),
+		DisabledAt:   &disabledAt,
+	}, nil)
+
+	user, token, err := service.Login(context.Background(), "testuser", "password123")
+
+	assert.ErrorIs(t, err, models.ErrUserDisabled)
+	assert.Nil(t, user)
+	assert.Empty(t, token)
+}
+
+func TestAuthService_CheckSession(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	later := now.Add(time.Second)
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		repoErr error
+		wantErr error
+	}{
+		{name: "active", user: &models.User{Role: models.RoleUser}},
+		{name: "token issued at cutoff", user: &models.User{TokensValidAfter: &now}},
+		{name: "token revoked", user: &models.User{TokensValidAfter: &later}, wantErr: ErrSessionRevoked},
+		{name: "disabled", user: &models.User{DisabledAt: &now}, wantErr: models.ErrUserDisabled},
+		{name: "deleted", repoErr: models.ErrUserNotFound, wantErr: ErrSessionRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepo)
+			service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+			userID := uuid.New()
+			if tt.repoErr != nil {
+				mockRepo.On("GetUserByID", mock.Anything, userID).Return(nil, tt.repoErr)
+			} else {
+				mockRepo.On("GetUserByID", mock.Anything, userID).Return(tt.user, nil)
+			}
+
+			user, err := service.CheckSession(context.Background(), userID, now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, user)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.user, user)
+		})
+	}
+}
+
+func TestAuthService_CheckSession_RepoError(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	userID := uuid.New()
+	mockRepo.On("GetUserByID", mock.Anything, userID).Return(nil, errors.New("database error"))
+
+	_, err := service.CheckSession(context.Background(), userID, time.Now())
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrSessionRevoked)
+}
+
 func TestHashPassword(t *testing.T) {
 	password := "testpassword"
 
-	hashed, err := hashPassword(password)
+	hashed, err := hashPassword(context.Background(), password)
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, hashed)
@@ -243,7 +348,7 @@ func TestComparePasswordHash_Success(t *testing.T) {
 	password := "testpassword"
 	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
-	err := comparePasswordHash(string(hashed), password)
+	err := comparePasswordHash(context.Background(), string(hashed), password)
 
 	assert.NoError(t, err)
 }
@@ -253,7 +358,7 @@ func TestComparePasswordHash_Failure(t *testing.T) {
 	wrongPassword := "wrongpassword"
 	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
-	err := comparePasswordHash(string(hashed), wrongPassword)
+	err := comparePasswordHash(context.Background(), string(hashed), wrongPassword)
 
 	assert.Error(t, err)
 }
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+)
+
+// ListDue retrieves items of a user that are expired, expire within the given period or,
+// when olderThan is positive, have not been updated for longer than olderThan.
+func (s *ItemService) ListDue(ctx context.Context, userID uuid.UUID, within, olderThan time.Duration) (_ []models.DueItem, err error) {
+	ctx, span := startSpan(ctx, "ItemService.ListDue")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	var updatedBefore *time.Time
+	if olderThan > 0 {
+		t := now.Add(-olderThan)
+		updatedBefore = &t
+	}
+
+	items, err := s.itemRepo.ListDue(ctx, userID, now.Add(within), updatedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due items: %w", err)
+	}
+
+	due := make([]models.DueItem, 0, len(items))
+	for _, item := range items {
+		due = append(due, models.DueItem{Item: item, Reason: dueReason(item, now, within)})
+	}
+	return due, nil
+}
+
+// dueReason reports why an item returned by ListDue needs attention.
+func dueReason(item *models.Item, now time.Time, within time.Duration) models.DueReason {
+	switch {
+	case item.ExpiresAt == nil:
+		return models.DueReasonStale
+	case !item.ExpiresAt.After(now):
+		return models.DueReasonExpired
+	case item.ExpiresAt.Before(now.Add(within)):
+		return models.DueReasonExpiring
+	default:
+		return models.DueReasonStale
+	}
+}
+
+// deriveExpiry extracts the expiration time from a plaintext payload.
+// Cards use their expiry date and binaries their X.509 certificate validity.
+// Returns nil for other types, an unknown type and payloads without expiry.
+func deriveExpiry(typ models.ItemType, payload []byte) *time.Time {
+	switch typ {
+	case models.ItemTypeCard:
+		return cardExpiry(payload)
+	case models.ItemTypeBinary:
+		return certificateExpiry(payload)
+	default:
+		return nil
+	}
+}
+
+// cardExpiry returns the expiry of a JSON-encoded card payload.
+func cardExpiry(payload []byte) *time.Time {
+	var card models.CardPayload
+	if err := json.Unmarshal(payload, &card); err != nil || card.Expiry == "" {
+		return nil
+	}
+	t, err := card.ExpiresAt()
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// certificateExpiry returns the earliest NotAfter of the certificates in a PEM or DER payload.
+func certificateExpiry(payload []byte) *time.Time {
+	var certs []*x509.Certificate
+	rest := payload
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		if cert, err := x509.ParseCertificate(payload); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+
+	var earliest *time.Time
+	for _, cert := range certs {
+		if earliest == nil || cert.NotAfter.Before(*earliest) {
+			t := cert.NotAfter
+			earliest = &t
+		}
+	}
+	return earliest
+}
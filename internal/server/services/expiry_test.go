@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// selfSignedCert returns a DER-encoded self-signed certificate valid until notAfter.
+func selfSignedCert(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+func TestDeriveExpiry_Card(t *testing.T) {
+	payload := []byte(`{"number":"4111111111111111","holder":"John Doe","expiry":"03/27","cvv":"123"}`)
+
+	got := deriveExpiry(models.ItemTypeCard, payload)
+
+	require.NotNil(t, got)
+	assert.Equal(t, time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC), *got)
+}
+
+func TestDeriveExpiry_CardWithoutExpiry(t *testing.T) {
+	assert.Nil(t, deriveExpiry(models.ItemTypeCard, []byte(`{"number":"4111111111111111"}`)))
+	assert.Nil(t, deriveExpiry(models.ItemTypeCard, []byte("not json")))
+}
+
+func TestDeriveExpiry_Certificate(t *testing.T) {
+	notAfter := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	earlier := notAfter.Add(-48 * time.Hour)
+	chain := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: selfSignedCert(t, notAfter)}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: selfSignedCert(t, earlier)})...,
+	)
+
+	got := deriveExpiry(models.ItemTypeBinary, chain)
+	require.NotNil(t, got)
+	assert.True(t, earlier.Equal(*got))
+
+	der := deriveExpiry(models.ItemTypeBinary, selfSignedCert(t, notAfter))
+	require.NotNil(t, der)
+	assert.True(t, notAfter.Equal(*der))
+}
+
+func TestDeriveExpiry_UnknownType(t *testing.T) {
+	assert.Nil(t, deriveExpiry("", []byte(`{"expiry":"12/30"}`)))
+	assert.Nil(t, deriveExpiry("", []byte("plain text")))
+	assert.Nil(t, deriveExpiry(models.ItemTypeText, []byte(`{"expiry":"12/30"}`)))
+}
+
+func TestItemService_CreateItem_DerivesCardExpiry(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("12345678901234567890123456789012")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	card := `{"number":"4111111111111111","expiry":"11/28"}`
+
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item"), mock.AnythingOfType("*models.EncryptedData")).Return(nil)
+
+	item, err := service.CreateItem(ctx, &models.CreateItemRequest{
+		Type:       models.ItemTypeCard,
+		Title:      "Visa",
+		DataBase64: base64.StdEncoding.EncodeToString([]byte(card)),
+	}, userID)
+
+	require.NoError(t, err)
+	require.NotNil(t, item.ExpiresAt)
+	assert.Equal(t, time.Date(2028, time.December, 1, 0, 0, 0, 0, time.UTC), *item.ExpiresAt)
+}
+
+func TestItemService_CreateItem_ExplicitExpiryWins(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("12345678901234567890123456789012")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	explicit := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item"), mock.AnythingOfType("*models.EncryptedData")).Return(nil)
+
+	item, err := service.CreateItem(ctx, &models.CreateItemRequest{
+		Type:       models.ItemTypeCard,
+		Title:      "Visa",
+		DataBase64: base64.StdEncoding.EncodeToString([]byte(`{"expiry":"11/28"}`)),
+		ExpiresAt:  &explicit,
+	}, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, &explicit, item.ExpiresAt)
+}
+
+func TestItemService_UpdateItem_DerivesExpiry(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("12345678901234567890123456789012")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	itemID := uuid.New()
+	data := base64.StdEncoding.EncodeToString([]byte(`{"expiry":"01/29"}`))
+	typ := models.ItemTypeCard
+	req := &models.UpdateItemRequest{Type: &typ, DataBase64: &data}
+
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("Update", mock.Anything, userID, itemID, mock.MatchedBy(func(r *models.UpdateItemRequest) bool {
+		return r.ExpiresAt != nil && r.ExpiresAt.Equal(time.Date(2029, time.February, 1, 0, 0, 0, 0, time.UTC))
+	}), mock.AnythingOfType("*models.EncryptedData")).Return(&models.Item{ID: itemID}, nil)
+
+	_, err := service.UpdateItem(ctx, userID, itemID, req)
+
+	require.NoError(t, err)
+	assert.Nil(t, req.ExpiresAt)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_UpdateItem_WithoutTypeKeepsExpiry(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("12345678901234567890123456789012")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	itemID := uuid.New()
+	data := base64.StdEncoding.EncodeToString([]byte(`{"expiry":"01/29"}`))
+
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("Update", mock.Anything, userID, itemID, mock.MatchedBy(func(r *models.UpdateItemRequest) bool {
+		return r.ExpiresAt == nil
+	}), mock.AnythingOfType("*models.EncryptedData")).Return(&models.Item{ID: itemID}, nil)
+
+	_, err := service.UpdateItem(ctx, userID, itemID, &models.UpdateItemRequest{DataBase64: &data})
+
+	require.NoError(t, err)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_ListDue(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("master-key-32-bytes-for-aes256!")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	expiring := now.Add(48 * time.Hour)
+
+	items := []*models.Item{
+		{ID: uuid.New(), Title: "Expired", ExpiresAt: &expired},
+		{ID: uuid.New(), Title: "Expiring", ExpiresAt: &expiring},
+		{ID: uuid.New(), Title: "Stale", UpdatedAt: now.Add(-200 * 24 * time.Hour)},
+	}
+
+	mockItemRepo.On("ListDue", mock.Anything, userID, mock.AnythingOfType("time.Time"), mock.MatchedBy(func(before *time.Time) bool {
+		return before != nil && before.Before(now.Add(-89*24*time.Hour))
+	})).Return(items, nil)
+
+	due, err := service.ListDue(ctx, userID, 30*24*time.Hour, 90*24*time.Hour)
+
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+	assert.Equal(t, models.DueReasonExpired, due[0].Reason)
+	assert.Equal(t, models.DueReasonExpiring, due[1].Reason)
+	assert.Equal(t, models.DueReasonStale, due[2].Reason)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_ListDue_WithoutAge(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("master-key-32-bytes-for-aes256!")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockItemRepo.On("ListDue", mock.Anything, userID, mock.AnythingOfType("time.Time"), (*time.Time)(nil)).Return([]*models.Item{}, nil)
+
+	due, err := service.ListDue(ctx, userID, 30*24*time.Hour, 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	mockItemRepo.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/crypto"
+)
+
+// Components checked by the readiness probe.
+const (
+	ComponentDatabase   = "database"
+	ComponentMigrations = "migrations"
+	ComponentMasterKey  = "master_key"
+)
+
+// healthCheckTimeout bounds every readiness check, so a hanging dependency is reported as down.
+const healthCheckTimeout = 2 * time.Second
+
+// masterKeyCanary is the plaintext of the canary encrypted with the master key.
+var masterKeyCanary = []byte("gophkeeper master key canary")
+
+// HealthRepo defines the contract of the queries used by the readiness probe.
+type HealthRepo interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+	SaveCanary(ctx context.Context, enc []byte) error
+	LoadCanary(ctx context.Context) ([]byte, bool, error)
+}
+
+// HealthService checks whether the dependencies of the server are usable.
+type HealthService struct {
+	repo             HealthRepo
+	masterKey        []byte
+	migrationVersion uint
+}
+
+// NewHealthService creates a new health service instance.
+// migrationVersion is the latest migration embedded in the binary.
+func NewHealthService(repo HealthRepo, masterKey []byte, migrationVersion uint) *HealthService {
+	return &HealthService{
+		repo:             repo,
+		masterKey:        masterKey,
+		migrationVersion: migrationVersion,
+	}
+}
+
+// EnsureCanary stores a value encrypted with the master key if none is stored yet.
+// Later readiness checks unwrap it to detect a server started with a different master key.
+func (s *HealthService) EnsureCanary(ctx context.Context) error {
+	enc, err := crypto.Encrypt(s.masterKey, masterKeyCanary)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt master key canary: %w", err)
+	}
+	return s.repo.SaveCanary(ctx, enc)
+}
+
+// Readiness runs all checks concurrently and reports the status and latency of every component.
+func (s *HealthService) Readiness(ctx context.Context) *models.ReadinessReport {
+	checks := map[string]func(context.Context) error{
+		ComponentDatabase:   s.repo.Ping,
+		ComponentMigrations: s.checkMigrations,
+		ComponentMasterKey:  s.checkMasterKey,
+	}
+
+	report := &models.ReadinessReport{
+		Status:     models.ReadinessReady,
+		Components: make(map[string]models.ComponentStatus, len(checks)),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Go(func() {
+			status := runCheck(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = status
+			if status.Status != models.ComponentUp {
+				report.Status = models.ReadinessNotReady
+			}
+		})
+	}
+	wg.Wait()
+	return report
+}
+
+// runCheck runs a single check with a timeout and measures its latency.
+func runCheck(ctx context.Context, check func(context.Context) error) models.ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	status := models.ComponentStatus{
+		Status:    models.ComponentUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = models.ComponentDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// checkMigrations verifies that the database schema matches the migrations embedded in the binary.
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, dirty, err := s.repo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("migration %d failed and left the schema dirty", version)
+	}
+	if version != s.migrationVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version, s.migrationVersion)
+	}
+	return nil
+}
+
+// checkMasterKey verifies that the master key unwraps the stored canary.
+func (s *HealthService) checkMasterKey(ctx context.Context) error {
+	enc, ok, err := s.repo.LoadCanary(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("master key canary is missing")
+	}
+	plain, err := crypto.Decrypt(s.masterKey, enc)
+	if err != nil || !bytes.Equal(plain, masterKeyCanary) {
+		return errors.New("master key does not unwrap the canary")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHealthRepo is a mock implementation of HealthRepo
+type MockHealthRepo struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepo) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockHealthRepo) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(uint), args.Bool(1), args.Error(2)
+}
+
+func (m *MockHealthRepo) SaveCanary(ctx context.Context, enc []byte) error {
+	args := m.Called(ctx, enc)
+	return args.Error(0)
+}
+
+func (m *MockHealthRepo) LoadCanary(ctx context.Context) ([]byte, bool, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]byte), args.Bool(1), args.Error(2)
+}
+
+var testMasterKey = []byte("master-key-32-bytes-for-aes256!!")
+
+func TestHealthService_EnsureCanary(t *testing.T) {
+	mockRepo := new(MockHealthRepo)
+	service := NewHealthService(mockRepo, testMasterKey, 5)
+
+	var saved []byte
+	mockRepo.On("SaveCanary", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).([]byte)
+	}).Return(nil)
+
+	require.NoError(t, service.EnsureCanary(context.Background()))
+	plain, err := crypto.Decrypt(testMasterKey, saved)
+	require.NoError(t, err)
+	assert.Equal(t, masterKeyCanary, plain)
+}
+
+func TestHealthService_Readiness_Ready(t *testing.T) {
+	mockRepo := new(MockHealthRepo)
+	service := NewHealthService(mockRepo, testMasterKey, 5)
+
+	canary, err := crypto.Encrypt(testMasterKey, masterKeyCanary)
+	require.NoError(t, err)
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("MigrationVersion", mock.Anything).Return(uint(5), false, nil)
+	mockRepo.On("LoadCanary", mock.Anything).Return(canary, true, nil)
+
+	report := service.Readiness(context.Background())
+
+	assert.Equal(t, models.ReadinessReady, report.Status)
+	require.Len(t, report.Components, 3)
+	for name, status := range report.Components {
+		assert.Equal(t, models.ComponentUp, status.Status, name)
+		assert.Empty(t, status.Error, name)
+		assert.GreaterOrEqual(t, status.LatencyMS, 0.0, name)
+	}
+}
+
+func TestHealthService_Readiness_NotReady(t *testing.T) {
+	otherKey := []byte("another-master-key-for-aes-256!!")
+	canary, err := crypto.Encrypt(otherKey, masterKeyCanary)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		setup     func(m *MockHealthRepo)
+		component string
+		error     string
+	}{
+		{
+			name: "database down",
+			setup: func(m *MockHealthRepo) {
+				m.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+			},
+			component: ComponentDatabase,
+			error:     "connection refused",
+		},
+		{
+			name: "pending migrations",
+			setup: func(m *MockHealthRepo) {
+				m.On("MigrationVersion", mock.Anything).Return(uint(4), false, nil)
+			},
+			component: ComponentMigrations,
+			error:     "schema version is 4, expected 5",
+		},
+		{
+			name: "dirty migration",
+			setup: func(m *MockHealthRepo) {
+				m.On("MigrationVersion", mock.Anything).Return(uint(5), true, nil)
+			},
+			component: ComponentMigrations,
+			error:     "migration 5 failed and left the schema dirty",
+		},
+		{
+			name: "wrong master key",
+			setup: func(m *MockHealthRepo) {
+				m.On("LoadCanary", mock.Anything).Return(canary, true, nil)
+			},
+			component: ComponentMasterKey,
+			error:     "master key does not unwrap the canary",
+		},
+		{
+			name: "missing canary",
+			setup: func(m *MockHealthRepo) {
+				m.On("LoadCanary", mock.Anything).Return([]byte(nil), false, nil)
+			},
+			component: ComponentMasterKey,
+			error:     "master key canary is missing",
+		},
+	}
+
+	goodCanary, err := crypto.Encrypt(testMasterKey, masterKeyCanary)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockHealthRepo)
+			tt.setup(mockRepo)
+			// Healthy defaults for the components not under test.
+			mockRepo.On("Ping", mock.Anything).Return(nil).Maybe()
+			mockRepo.On("MigrationVersion", mock.Anything).Return(uint(5), false, nil).Maybe()
+			mockRepo.On("LoadCanary", mock.Anything).Return(goodCanary, true, nil).Maybe()
+			service := NewHealthService(mockRepo, testMasterKey, 5)
+
+			report := service.Readiness(context.Background())
+
+			assert.Equal(t, models.ReadinessNotReady, report.Status)
+			for name, status := range report.Components {
+				if name == tt.component {
+					assert.Equal(t, models.ComponentDown, status.Status)
+					assert.Equal(t, tt.error, status.Error)
+					continue
+				}
+				assert.Equal(t, models.ComponentUp, status.Status, name)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/Pro100x3mal/gophkeeper/pkg/crypto"
 	"github.com/google/uuid"
@@ -13,6 +15,37 @@ import (
 // ErrInvalidItemType is returned when an invalid item type is provided.
 var ErrInvalidItemType = fmt.Errorf("invalid item type")
 
+// ErrInvalidInput is returned when a request carries malformed data, e.g. a payload that is not valid base64.
+var ErrInvalidInput = fmt.Errorf("invalid input")
+
+// BatchError is returned when an atomic batch is aborted by a failing operation.
+type BatchError struct {
+	// Index is the position of the failing operation in the batch.
+	Index int
+	// Err is the error that caused the failure.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d failed: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchOutcome holds the result of a single operation of a non-atomic batch.
+type BatchOutcome struct {
+	// Op is the kind of operation.
+	Op models.BatchOpType
+	// Item is the resulting item for successful create and update operations.
+	Item *models.Item
+	// Err is the error that caused the operation to fail (nil on success).
+	Err error
+}
+
 // KeyRepo defines the encryption key repository contract.
 type KeyRepo interface {
 	Save(ctx context.Context, userID uuid.UUID, enc []byte) error
@@ -25,6 +58,14 @@ type ItemRepoInterface interface {
 	GetByID(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, *models.EncryptedData, error)
 	DeleteByID(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error
 	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Item, error)
+	ListDue(ctx context.Context, userID uuid.UUID, expiresBefore time.Time, updatedBefore *time.Time) ([]*models.Item, error)
+	ApplyBatch(ctx context.Context, userID uuid.UUID, muts []*models.ItemMutation, atomic bool) ([]error, error)
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.Item, error)
+	Restore(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, error)
+	Purge(ctx context.Context, userID, itemID uuid.UUID) error
+	PurgeAll(ctx context.Context, userID uuid.UUID) (int64, error)
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	Usage(ctx context.Context, userID uuid.UUID) (*models.UsageReport, error)
 	Update(
 		ctx context.Context,
 		userID, itemID uuid.UUID,
@@ -39,6 +80,7 @@ type ItemService struct {
 	keyRepo   KeyRepo
 	itemRepo  ItemRepoInterface
 	masterKey []byte
+	observer  Observer
 }
 
 // NewItemService creates a new item service instance with the specified master key.
@@ -47,12 +89,35 @@ func NewItemService(keyRepo KeyRepo, itemRepo ItemRepoInterface, masterKey []byt
 		keyRepo:   keyRepo,
 		itemRepo:  itemRepo,
 		masterKey: masterKey,
+		observer:  nopObserver{},
 	}
 }
 
+// SetObserver sets the observer that receives encryption and decryption timings.
+func (s *ItemService) SetObserver(o Observer) {
+	s.observer = o
+}
+
+// encrypt encrypts data with the key and reports the duration to the observer.
+func (s *ItemService) encrypt(key, data []byte) ([]byte, error) {
+	start := time.Now()
+	defer func() { s.observer.ObserveCrypto(CryptoOpEncrypt, time.Since(start)) }()
+	return crypto.Encrypt(key, data)
+}
+
+// decrypt decrypts data with the key and reports the duration to the observer.
+func (s *ItemService) decrypt(key, data []byte) ([]byte, error) {
+	start := time.Now()
+	defer func() { s.observer.ObserveCrypto(CryptoOpDecrypt, time.Since(start)) }()
+	return crypto.Decrypt(key, data)
+}
+
 // CreateItem creates a new encrypted item with the provided data.
 // Uses envelope encryption: data is encrypted with a data key, which is encrypted with a user key.
-func (s *ItemService) CreateItem(ctx context.Context, req *models.CreateItemRequest, userID uuid.UUID) (*models.Item, error) {
+func (s *ItemService) CreateItem(ctx context.Context, req *models.CreateItemRequest, userID uuid.UUID) (_ *models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.CreateItem")
+	defer func() { tracing.End(span, err) }()
+
 	if !isValidType(req.Type) {
 		return nil, ErrInvalidItemType
 	}
@@ -63,40 +128,22 @@ func (s *ItemService) CreateItem(ctx context.Context, req *models.CreateItemRequ
 	}
 
 	item := &models.Item{
-		ID:       uuid.New(),
-		UserID:   userID,
-		Type:     req.Type,
-		Title:    req.Title,
-		Metadata: req.Metadata,
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      req.Type,
+		Title:     req.Title,
+		Metadata:  req.Metadata,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if item.ExpiresAt == nil {
+		item.ExpiresAt = deriveExpiry(item.Type, payload)
 	}
 
 	var encData *models.EncryptedData
 	if len(payload) > 0 {
-		userKey, err := s.loadOrCreateKey(ctx, userID)
+		encData, err = s.sealPayload(ctx, userID, item.ID, payload)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load or create key: %w", err)
-		}
-
-		dataKey, err := crypto.KeyGen()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate data key: %w", err)
-		}
-
-		dataEncrypted, err := crypto.Encrypt(dataKey, payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt data: %w", err)
-		}
-
-		dataKeyEncrypted, err := crypto.Encrypt(userKey, dataKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt data key: %w", err)
-		}
-
-		encData = &models.EncryptedData{
-			ID:               uuid.New(),
-			ItemID:           item.ID,
-			DataEncrypted:    dataEncrypted,
-			DataKeyEncrypted: dataKeyEncrypted,
+			return nil, err
 		}
 	}
 
@@ -109,7 +156,11 @@ func (s *ItemService) CreateItem(ctx context.Context, req *models.CreateItemRequ
 
 // UpdateItem updates an existing item's metadata and/or encrypted data.
 // Only provided fields are updated. Uses envelope encryption for new data.
-func (s *ItemService) UpdateItem(ctx context.Context, userID, itemID uuid.UUID, req *models.UpdateItemRequest) (*models.Item, error) {
+// When new data is provided without an expiration time, it is derived from the data.
+func (s *ItemService) UpdateItem(ctx context.Context, userID, itemID uuid.UUID, req *models.UpdateItemRequest) (_ *models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.UpdateItem")
+	defer func() { tracing.End(span, err) }()
+
 	if req.Type != nil && !isValidType(*req.Type) {
 		return nil, ErrInvalidItemType
 	}
@@ -121,32 +172,11 @@ func (s *ItemService) UpdateItem(ctx context.Context, userID, itemID uuid.UUID,
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode base64 data: %w", err)
 		}
-		userKey, err := s.loadOrCreateKey(ctx, userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load or create key: %w", err)
-		}
-
-		dataKey, err := crypto.KeyGen()
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate data key: %w", err)
-		}
-
-		dataEncrypted, err := crypto.Encrypt(dataKey, payload)
-		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt data: %w", err)
-		}
-
-		dataKeyEncrypted, err := crypto.Encrypt(userKey, dataKey)
+		encData, err = s.sealPayload(ctx, userID, itemID, payload)
 		if err != nil {
-			return nil, fmt.Errorf("failed to encrypt data key: %w", err)
-		}
-
-		encData = &models.EncryptedData{
-			ID:               uuid.New(),
-			ItemID:           itemID,
-			DataEncrypted:    dataEncrypted,
-			DataKeyEncrypted: dataKeyEncrypted,
+			return nil, err
 		}
+		req = withDerivedExpiry(req, payload)
 	}
 
 	item, err := s.itemRepo.Update(ctx, userID, itemID, req, encData)
@@ -157,7 +187,10 @@ func (s *ItemService) UpdateItem(ctx context.Context, userID, itemID uuid.UUID,
 }
 
 // ListItems retrieves all items for a user without decrypting their data.
-func (s *ItemService) ListItems(ctx context.Context, userID uuid.UUID) ([]*models.Item, error) {
+func (s *ItemService) ListItems(ctx context.Context, userID uuid.UUID) (_ []*models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.ListItems")
+	defer func() { tracing.End(span, err) }()
+
 	items, err := s.itemRepo.ListByUser(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list items: %w", err)
@@ -167,7 +200,10 @@ func (s *ItemService) ListItems(ctx context.Context, userID uuid.UUID) ([]*model
 
 // GetItem retrieves an item and decrypts its data using envelope encryption.
 // Returns the item metadata and decrypted data.
-func (s *ItemService) GetItem(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, []byte, error) {
+func (s *ItemService) GetItem(ctx context.Context, userID, itemID uuid.UUID) (_ *models.Item, _ []byte, err error) {
+	ctx, span := startSpan(ctx, "ItemService.GetItem")
+	defer func() { tracing.End(span, err) }()
+
 	item, encData, err := s.itemRepo.GetByID(ctx, userID, itemID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get item: %w", err)
@@ -175,38 +211,291 @@ func (s *ItemService) GetItem(ctx context.Context, userID, itemID uuid.UUID) (*m
 
 	var plainData []byte
 	if encData != nil && len(encData.DataEncrypted) > 0 {
-		userKey, err := s.loadOrCreateKey(ctx, userID)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to load or create key: %w", err)
-		}
-		dataKey, err := crypto.Decrypt(userKey, encData.DataKeyEncrypted)
+		plainData, err = s.openPayload(ctx, userID, encData)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to decrypt data key: %w", err)
-		}
-		plainData, err = crypto.Decrypt(dataKey, encData.DataEncrypted)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to decrypt data: %w", err)
+			return nil, nil, err
 		}
 	}
 	return item, plainData, nil
 }
 
-// DeleteItem removes an item and its encrypted data from the database.
-func (s *ItemService) DeleteItem(ctx context.Context, userID, itemID uuid.UUID) error {
+// DeleteItem moves an item to the trash. It can be restored until it is purged.
+func (s *ItemService) DeleteItem(ctx context.Context, userID, itemID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.DeleteItem")
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.itemRepo.DeleteByID(ctx, userID, itemID); err != nil {
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
 	return nil
 }
 
+// Usage returns the storage used by a user together with the quota.
+func (s *ItemService) Usage(ctx context.Context, userID uuid.UUID) (_ *models.UsageReport, err error) {
+	ctx, span := startSpan(ctx, "ItemService.Usage")
+	defer func() { tracing.End(span, err) }()
+
+	report, err := s.itemRepo.Usage(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	return report, nil
+}
+
+// ListTrash retrieves all deleted items of a user that have not been purged yet.
+func (s *ItemService) ListTrash(ctx context.Context, userID uuid.UUID) (_ []*models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.ListTrash")
+	defer func() { tracing.End(span, err) }()
+
+	items, err := s.itemRepo.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	return items, nil
+}
+
+// RestoreItem moves a deleted item out of the trash.
+func (s *ItemService) RestoreItem(ctx context.Context, userID, itemID uuid.UUID) (_ *models.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.RestoreItem")
+	defer func() { tracing.End(span, err) }()
+
+	item, err := s.itemRepo.Restore(ctx, userID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore item: %w", err)
+	}
+	return item, nil
+}
+
+// PurgeItem permanently removes a deleted item and its encrypted data.
+func (s *ItemService) PurgeItem(ctx context.Context, userID, itemID uuid.UUID) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.PurgeItem")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.itemRepo.Purge(ctx, userID, itemID); err != nil {
+		return fmt.Errorf("failed to purge item: %w", err)
+	}
+	return nil
+}
+
+// EmptyTrash permanently removes all deleted items of a user.
+// Returns the number of removed items.
+func (s *ItemService) EmptyTrash(ctx context.Context, userID uuid.UUID) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ItemService.EmptyTrash")
+	defer func() { tracing.End(span, err) }()
+
+	n, err := s.itemRepo.PurgeAll(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return n, nil
+}
+
+// PurgeExpired permanently removes items of all users that have been in the trash
+// for longer than the retention period. Returns the number of removed items.
+func (s *ItemService) PurgeExpired(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ItemService.PurgeExpired")
+	defer func() { tracing.End(span, err) }()
+
+	n, err := s.itemRepo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired items: %w", err)
+	}
+	return n, nil
+}
+
+// ExecuteBatch executes several item operations for a user.
+// Payloads are encrypted before anything is persisted. In atomic mode the first failing
+// operation aborts the whole batch and a *BatchError is returned; otherwise each operation
+// is applied independently and reported in its own BatchOutcome.
+func (s *ItemService) ExecuteBatch(ctx context.Context, userID uuid.UUID, req *models.BatchRequest) (_ []BatchOutcome, err error) {
+	ctx, span := startSpan(ctx, "ItemService.ExecuteBatch")
+	defer func() { tracing.End(span, err) }()
+
+	outcomes := make([]BatchOutcome, len(req.Operations))
+	muts := make([]*models.ItemMutation, 0, len(req.Operations))
+	indexes := make([]int, 0, len(req.Operations))
+
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		outcomes[i].Op = op.Op
+
+		m, err := s.prepareMutation(ctx, userID, op)
+		if err != nil {
+			if req.Atomic {
+				return nil, &BatchError{Index: i, Err: err}
+			}
+			outcomes[i].Err = err
+			continue
+		}
+		muts = append(muts, m)
+		indexes = append(indexes, i)
+	}
+
+	errs, err := s.itemRepo.ApplyBatch(ctx, userID, muts, req.Atomic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply batch: %w", err)
+	}
+
+	for j, m := range muts {
+		i := indexes[j]
+		if errs[j] != nil {
+			if req.Atomic {
+				return nil, &BatchError{Index: i, Err: errs[j]}
+			}
+			outcomes[i].Err = errs[j]
+			continue
+		}
+		outcomes[i].Item = m.Item
+	}
+
+	return outcomes, nil
+}
+
+// prepareMutation validates a batch operation and encrypts its payload.
+func (s *ItemService) prepareMutation(ctx context.Context, userID uuid.UUID, op *models.BatchOperation) (*models.ItemMutation, error) {
+	switch op.Op {
+	case models.BatchOpCreate:
+		if op.Create == nil || !isValidType(op.Create.Type) {
+			return nil, ErrInvalidItemType
+		}
+		payload, err := decodeBase64(op.Create.DataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 data: %w", err)
+		}
+		m := &models.ItemMutation{
+			Op: op.Op,
+			Item: &models.Item{
+				ID:        uuid.New(),
+				UserID:    userID,
+				Type:      op.Create.Type,
+				Title:     op.Create.Title,
+				Metadata:  op.Create.Metadata,
+				ExpiresAt: op.Create.ExpiresAt,
+			},
+		}
+		m.ItemID = m.Item.ID
+		if m.Item.ExpiresAt == nil {
+			m.Item.ExpiresAt = deriveExpiry(m.Item.Type, payload)
+		}
+		if len(payload) > 0 {
+			if m.EncData, err = s.sealPayload(ctx, userID, m.ItemID, payload); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case models.BatchOpUpdate:
+		if op.ID == nil || op.Update == nil {
+			return nil, fmt.Errorf("%w: update operation requires id and changes", ErrInvalidInput)
+		}
+		if op.Update.Type != nil && !isValidType(*op.Update.Type) {
+			return nil, ErrInvalidItemType
+		}
+		m := &models.ItemMutation{Op: op.Op, ItemID: *op.ID, Update: op.Update}
+		if op.Update.DataBase64 != nil && len(*op.Update.DataBase64) > 0 {
+			payload, err := decodeBase64(*op.Update.DataBase64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode base64 data: %w", err)
+			}
+			if m.EncData, err = s.sealPayload(ctx, userID, m.ItemID, payload); err != nil {
+				return nil, err
+			}
+			m.Update = withDerivedExpiry(op.Update, payload)
+		}
+		return m, nil
+	case models.BatchOpDelete:
+		if op.ID == nil {
+			return nil, fmt.Errorf("%w: delete operation requires id", ErrInvalidInput)
+		}
+		return &models.ItemMutation{Op: op.Op, ItemID: *op.ID}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported batch operation %q", ErrInvalidInput, op.Op)
+	}
+}
+
+// withDerivedExpiry returns the update request with the expiration time derived from the
+// new payload when the request does not set one. The payload is only inspected when the
+// request sets the item type. The original request is not modified.
+func withDerivedExpiry(req *models.UpdateItemRequest, payload []byte) *models.UpdateItemRequest {
+	if req.ExpiresAt != nil {
+		return req
+	}
+	var typ models.ItemType
+	if req.Type != nil {
+		typ = *req.Type
+	}
+	expiresAt := deriveExpiry(typ, payload)
+	if expiresAt == nil {
+		return req
+	}
+	updated := *req
+	updated.ExpiresAt = expiresAt
+	return &updated
+}
+
+// sealPayload encrypts a payload with a fresh data key wrapped by the user's key.
+func (s *ItemService) sealPayload(ctx context.Context, userID, itemID uuid.UUID, payload []byte) (_ *models.EncryptedData, err error) {
+	ctx, span := startSpan(ctx, "ItemService.sealPayload")
+	defer func() { tracing.End(span, err) }()
+
+	userKey, err := s.loadOrCreateKey(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or create key: %w", err)
+	}
+
+	dataKey, err := crypto.KeyGen()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	dataEncrypted, err := s.encrypt(dataKey, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	dataKeyEncrypted, err := s.encrypt(userKey, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data key: %w", err)
+	}
+
+	return &models.EncryptedData{
+		ID:               uuid.New(),
+		ItemID:           itemID,
+		DataEncrypted:    dataEncrypted,
+		DataKeyEncrypted: dataKeyEncrypted,
+	}, nil
+}
+
+// openPayload decrypts a payload by unwrapping its data key with the user's key.
+func (s *ItemService) openPayload(ctx context.Context, userID uuid.UUID, encData *models.EncryptedData) (_ []byte, err error) {
+	ctx, span := startSpan(ctx, "ItemService.openPayload")
+	defer func() { tracing.End(span, err) }()
+
+	userKey, err := s.loadOrCreateKey(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load or create key: %w", err)
+	}
+	dataKey, err := s.decrypt(userKey, encData.DataKeyEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	plainData, err := s.decrypt(dataKey, encData.DataEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+	return plainData, nil
+}
+
 // loadOrCreateKey retrieves a user's encryption key or generates a new one if it doesn't exist.
 // The user key is encrypted with the master key before storage.
-func (s *ItemService) loadOrCreateKey(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+func (s *ItemService) loadOrCreateKey(ctx context.Context, userID uuid.UUID) (_ []byte, err error) {
+	ctx, span := startSpan(ctx, "ItemService.loadOrCreateKey")
+	defer func() { tracing.End(span, err) }()
+
 	keyEncrypted, ok, err := s.keyRepo.Load(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load key: %w", err)
 	} else if ok && len(keyEncrypted) > 0 {
-		return crypto.Decrypt(s.masterKey, keyEncrypted)
+		return s.decrypt(s.masterKey, keyEncrypted)
 	}
 
 	key, err := crypto.KeyGen()
@@ -214,7 +503,7 @@ func (s *ItemService) loadOrCreateKey(ctx context.Context, userID uuid.UUID) ([]
 		return nil, fmt.Errorf("failed to generate key: %w", err)
 	}
 
-	enc, err := crypto.Encrypt(s.masterKey, key)
+	enc, err := s.encrypt(s.masterKey, key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt key: %w", err)
 	}
@@ -229,7 +518,7 @@ func (s *ItemService) loadOrCreateKey(ctx context.Context, userID uuid.UUID) ([]
 // isValidType checks if an item type is one of the supported types.
 func isValidType(t models.ItemType) bool {
 	switch t {
-	case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard:
+	case models.ItemTypeCredential, models.ItemTypeText, models.ItemTypeBinary, models.ItemTypeCard, models.ItemTypeSSHKey:
 		return true
 	default:
 		return false
@@ -244,7 +533,7 @@ func decodeBase64(data string) ([]byte, error) {
 	}
 	payload, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
 	}
 	return payload, nil
 }
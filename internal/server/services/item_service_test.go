@@ -5,12 +5,16 @@ import (
 	"encoding/base64"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
 	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/crypto"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // MockKeyRepo is a mock implementation of KeyRepo
@@ -63,6 +67,61 @@ func (m *MockItemRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]*mod
 	return args.Get(0).([]*models.Item), args.Error(1)
 }
 
+func (m *MockItemRepo) ListDue(ctx context.Context, userID uuid.UUID, expiresBefore time.Time, updatedBefore *time.Time) ([]*models.Item, error) {
+	args := m.Called(ctx, userID, expiresBefore, updatedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepo) ApplyBatch(ctx context.Context, userID uuid.UUID, muts []*models.ItemMutation, atomic bool) ([]error, error) {
+	args := m.Called(ctx, userID, muts, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockItemRepo) ListTrash(ctx context.Context, userID uuid.UUID) ([]*models.Item, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepo) Restore(ctx context.Context, userID, itemID uuid.UUID) (*models.Item, error) {
+	args := m.Called(ctx, userID, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepo) Purge(ctx context.Context, userID, itemID uuid.UUID) error {
+	args := m.Called(ctx, userID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockItemRepo) PurgeAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockItemRepo) Usage(ctx context.Context, userID uuid.UUID) (*models.UsageReport, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UsageReport), args.Error(1)
+}
+
 func (m *MockItemRepo) Update(
 	ctx context.Context,
 	userID, itemID uuid.UUID,
@@ -99,6 +158,7 @@ func TestIsValidType(t *testing.T) {
 		{"Valid text type", models.ItemTypeText, true},
 		{"Valid binary type", models.ItemTypeBinary, true},
 		{"Valid card type", models.ItemTypeCard, true},
+		{"Valid SSH key type", models.ItemTypeSSHKey, true},
 		{"Invalid type", models.ItemType("invalid"), false},
 		{"Empty type", models.ItemType(""), false},
 	}
@@ -131,7 +191,7 @@ func TestDecodeBase64_EmptyString(t *testing.T) {
 func TestDecodeBase64_InvalidBase64(t *testing.T) {
 	decoded, err := decodeBase64("not-valid-base64!!!")
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidInput)
 	assert.Nil(t, decoded)
 }
 
@@ -171,7 +231,7 @@ func TestItemService_CreateItem_WithoutData(t *testing.T) {
 		DataBase64: "",
 	}
 
-	mockItemRepo.On("Create", ctx, mock.AnythingOfType("*models.Item"), (*models.EncryptedData)(nil)).Return(nil)
+	mockItemRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item"), (*models.EncryptedData)(nil)).Return(nil)
 
 	item, err := service.CreateItem(ctx, req, userID)
 
@@ -218,7 +278,7 @@ func TestItemService_ListItems_Success(t *testing.T) {
 		{ID: uuid.New(), UserID: userID, Type: models.ItemTypeCredential, Title: "Item 2"},
 	}
 
-	mockItemRepo.On("ListByUser", ctx, userID).Return(expectedItems, nil)
+	mockItemRepo.On("ListByUser", mock.Anything, userID).Return(expectedItems, nil)
 
 	items, err := service.ListItems(ctx, userID)
 
@@ -238,7 +298,7 @@ func TestItemService_ListItems_Error(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 
-	mockItemRepo.On("ListByUser", ctx, userID).Return(nil, errors.New("database error"))
+	mockItemRepo.On("ListByUser", mock.Anything, userID).Return(nil, errors.New("database error"))
 
 	items, err := service.ListItems(ctx, userID)
 
@@ -259,7 +319,7 @@ func TestItemService_DeleteItem_Success(t *testing.T) {
 	userID := uuid.New()
 	itemID := uuid.New()
 
-	mockItemRepo.On("DeleteByID", ctx, userID, itemID).Return(nil)
+	mockItemRepo.On("DeleteByID", mock.Anything, userID, itemID).Return(nil)
 
 	err := service.DeleteItem(ctx, userID, itemID)
 
@@ -277,7 +337,7 @@ func TestItemService_DeleteItem_Error(t *testing.T) {
 	userID := uuid.New()
 	itemID := uuid.New()
 
-	mockItemRepo.On("DeleteByID", ctx, userID, itemID).Return(errors.New("database error"))
+	mockItemRepo.On("DeleteByID", mock.Anything, userID, itemID).Return(errors.New("database error"))
 
 	err := service.DeleteItem(ctx, userID, itemID)
 
@@ -328,7 +388,7 @@ func TestItemService_UpdateItem_WithoutData(t *testing.T) {
 		Title:  newTitle,
 	}
 
-	mockItemRepo.On("Update", ctx, userID, itemID, req, (*models.EncryptedData)(nil)).Return(updatedItem, nil)
+	mockItemRepo.On("Update", mock.Anything, userID, itemID, req, (*models.EncryptedData)(nil)).Return(updatedItem, nil)
 
 	item, err := service.UpdateItem(ctx, userID, itemID, req)
 
@@ -361,10 +421,10 @@ func TestItemService_CreateItem_WithEncryptedData(t *testing.T) {
 	}
 
 	// Mock key repository - no existing key
-	mockKeyRepo.On("Load", ctx, userID).Return([]byte{}, false, nil)
-	mockKeyRepo.On("Save", ctx, userID, mock.Anything).Return(nil)
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
 
-	mockItemRepo.On("Create", ctx, mock.AnythingOfType("*models.Item"), mock.AnythingOfType("*models.EncryptedData")).Return(nil)
+	mockItemRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Item"), mock.AnythingOfType("*models.EncryptedData")).Return(nil)
 
 	item, err := service.CreateItem(ctx, req, userID)
 
@@ -389,7 +449,7 @@ func TestItemService_CreateItem_KeyLoadError(t *testing.T) {
 		DataBase64: "dGVzdA==",
 	}
 
-	mockKeyRepo.On("Load", ctx, userID).Return([]byte{}, false, errors.New("db error"))
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, errors.New("db error"))
 
 	item, err := service.CreateItem(ctx, req, userID)
 
@@ -410,7 +470,7 @@ func TestItemService_GetItem_WithoutEncryptedData(t *testing.T) {
 
 	item := &models.Item{ID: itemID, UserID: userID, Title: "Test"}
 
-	mockItemRepo.On("GetByID", ctx, userID, itemID).Return(item, nil, nil)
+	mockItemRepo.On("GetByID", mock.Anything, userID, itemID).Return(item, nil, nil)
 
 	gotItem, gotData, err := service.GetItem(ctx, userID, itemID)
 
@@ -430,7 +490,7 @@ func TestItemService_GetItem_Error(t *testing.T) {
 	userID := uuid.New()
 	itemID := uuid.New()
 
-	mockItemRepo.On("GetByID", ctx, userID, itemID).
+	mockItemRepo.On("GetByID", mock.Anything, userID, itemID).
 		Return(nil, nil, errors.New("database error"))
 
 	item, data, err := service.GetItem(ctx, userID, itemID)
@@ -478,7 +538,7 @@ func TestItemService_UpdateItem_UpdateError(t *testing.T) {
 		Title: &newTitle,
 	}
 
-	mockItemRepo.On("Update", ctx, userID, itemID, req, (*models.EncryptedData)(nil)).
+	mockItemRepo.On("Update", mock.Anything, userID, itemID, req, (*models.EncryptedData)(nil)).
 		Return(nil, errors.New("update failed"))
 
 	item, err := service.UpdateItem(ctx, userID, itemID, req)
@@ -487,3 +547,245 @@ func TestItemService_UpdateItem_UpdateError(t *testing.T) {
 	assert.Nil(t, item)
 	mockItemRepo.AssertExpectations(t)
 }
+
+func TestItemService_ExecuteBatch_NonAtomic(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	userID := uuid.New()
+	existingID := uuid.New()
+	missingID := uuid.New()
+	data := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte{}, false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("ApplyBatch", mock.Anything, userID, mock.MatchedBy(func(muts []*models.ItemMutation) bool {
+		return len(muts) == 2 &&
+			muts[0].Op == models.BatchOpCreate && muts[0].EncData != nil && muts[0].Item.UserID == userID &&
+			muts[1].Op == models.BatchOpDelete && muts[1].ItemID == missingID
+	}), false).Return([]error{nil, models.ErrItemNotFound}, nil)
+
+	outcomes, err := service.ExecuteBatch(context.Background(), userID, &models.BatchRequest{
+		Operations: []models.BatchOperation{
+			{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText, Title: "Note", DataBase64: data}},
+			{Op: models.BatchOpUpdate, ID: &existingID, Update: &models.UpdateItemRequest{Type: ptrItemType("bogus")}},
+			{Op: models.BatchOpDelete, ID: &missingID},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, outcomes, 3)
+	assert.NoError(t, outcomes[0].Err)
+	assert.NotNil(t, outcomes[0].Item)
+	assert.ErrorIs(t, outcomes[1].Err, ErrInvalidItemType)
+	assert.ErrorIs(t, outcomes[2].Err, models.ErrItemNotFound)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_ExecuteBatch_AtomicPrepareFailure(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	itemID := uuid.New()
+	_, err := service.ExecuteBatch(context.Background(), uuid.New(), &models.BatchRequest{
+		Atomic: true,
+		Operations: []models.BatchOperation{
+			{Op: models.BatchOpDelete, ID: &itemID},
+			{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: "bogus", Title: "x"}},
+		},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.ErrorIs(t, err, ErrInvalidItemType)
+	mockItemRepo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemService_ExecuteBatch_AtomicApplyFailure(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	userID := uuid.New()
+	firstID, secondID := uuid.New(), uuid.New()
+	mockItemRepo.On("ApplyBatch", mock.Anything, userID, mock.Anything, true).
+		Return([]error{nil, models.ErrItemNotFound}, nil)
+
+	_, err := service.ExecuteBatch(context.Background(), userID, &models.BatchRequest{
+		Atomic: true,
+		Operations: []models.BatchOperation{
+			{Op: models.BatchOpDelete, ID: &firstID},
+			{Op: models.BatchOpDelete, ID: &secondID},
+		},
+	})
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.ErrorIs(t, err, models.ErrItemNotFound)
+}
+
+func TestItemService_ExecuteBatch_InvalidInput(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	userID := uuid.New()
+	itemID := uuid.New()
+	bad := "not-valid-base64!!!"
+	mockItemRepo.On("ApplyBatch", mock.Anything, userID, mock.Anything, false).Return([]error{}, nil)
+
+	outcomes, err := service.ExecuteBatch(context.Background(), userID, &models.BatchRequest{
+		Operations: []models.BatchOperation{
+			{Op: models.BatchOpCreate, Create: &models.CreateItemRequest{Type: models.ItemTypeText, Title: "Note", DataBase64: bad}},
+			{Op: models.BatchOpUpdate, ID: &itemID, Update: &models.UpdateItemRequest{DataBase64: &bad}},
+			{Op: models.BatchOpUpdate, ID: &itemID},
+			{Op: models.BatchOpDelete},
+			{Op: "rename"},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, outcomes, 5)
+	for i, o := range outcomes {
+		assert.ErrorIs(t, o.Err, ErrInvalidInput, "operation %d", i)
+	}
+}
+
+func ptrItemType(t models.ItemType) *models.ItemType {
+	return &t
+}
+
+func TestItemService_RestoreItem_NotFound(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("master-key-32-bytes-for-aes256!")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	mockItemRepo.On("Restore", mock.Anything, userID, itemID).Return(nil, models.ErrItemNotFound)
+
+	item, err := service.RestoreItem(ctx, userID, itemID)
+
+	assert.Nil(t, item)
+	assert.ErrorIs(t, err, models.ErrItemNotFound)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_EmptyTrash_Success(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("master-key-32-bytes-for-aes256!")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockItemRepo.On("PurgeAll", mock.Anything, userID).Return(int64(2), nil)
+
+	n, err := service.EmptyTrash(ctx, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_PurgeExpired(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	masterKey := []byte("master-key-32-bytes-for-aes256!")
+	service := NewItemService(mockKeyRepo, mockItemRepo, masterKey)
+
+	ctx := context.Background()
+	retention := 24 * time.Hour
+	start := time.Now()
+
+	mockItemRepo.On("PurgeDeletedBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		cutoff := start.Add(-retention)
+		return !before.Before(cutoff) && before.Before(cutoff.Add(time.Minute))
+	})).Return(int64(5), nil)
+
+	n, err := service.PurgeExpired(ctx, retention)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	mockItemRepo.AssertExpectations(t)
+}
+
+func TestItemService_GetItem_Spans(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, []byte("master-key-32-bytes-for-aes256!!"))
+
+	ctx := context.Background()
+	userID := uuid.New()
+	itemID := uuid.New()
+
+	userKey, err := crypto.KeyGen()
+	require.NoError(t, err)
+	userKeyEncrypted, err := crypto.Encrypt(service.masterKey, userKey)
+	require.NoError(t, err)
+	mockKeyRepo.On("Load", mock.Anything, userID).Return(userKeyEncrypted, true, nil)
+	encData, err := service.sealPayload(ctx, userID, itemID, []byte("secret"))
+	require.NoError(t, err)
+	mockItemRepo.On("GetByID", mock.Anything, userID, itemID).Return(&models.Item{ID: itemID}, encData, nil)
+
+	exporter, restore := tracing.SetupInMemory()
+	defer restore()
+
+	_, data, err := service.GetItem(ctx, userID, itemID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), data)
+
+	// Spans end innermost first.
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "ItemService.loadOrCreateKey", spans[0].Name)
+	assert.Equal(t, "ItemService.openPayload", spans[1].Name)
+	assert.Equal(t, "ItemService.GetItem", spans[2].Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, spans[2].SpanContext.SpanID(), spans[1].Parent.SpanID())
+
+	mockItemRepo.On("ListByUser", mock.Anything, userID).Return(nil, errors.New("database error"))
+	_, err = service.ListItems(ctx, userID)
+	require.Error(t, err)
+	spans = exporter.GetSpans()
+	assert.Equal(t, codes.Error, spans[len(spans)-1].Status.Code)
+}
+
+func TestItemService_Usage(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	userID := uuid.New()
+	report := &models.UsageReport{
+		Usage: models.Usage{Items: 3, Bytes: 1024},
+		Quota: models.Quota{MaxItems: 10},
+	}
+	mockItemRepo.On("Usage", mock.Anything, userID).Return(report, nil)
+
+	got, err := service.Usage(context.Background(), userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, report, got)
+}
+
+func TestItemService_Usage_Error(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, make([]byte, 32))
+
+	userID := uuid.New()
+	mockItemRepo.On("Usage", mock.Anything, userID).Return(nil, errors.New("db down"))
+
+	_, err := service.Usage(context.Background(), userID)
+
+	assert.Error(t, err)
+}
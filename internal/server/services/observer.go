@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+)
+
+// Operations and results reported to an Observer.
+const (
+	// AuthOpLogin is a login attempt.
+	AuthOpLogin = "login"
+	// AuthOpRegister is a registration attempt.
+	AuthOpRegister = "register"
+
+	// AuthResultSuccess means the user was authenticated or registered.
+	AuthResultSuccess = "success"
+	// AuthResultFailure means the credentials were rejected, the user is disabled or the username is taken.
+	AuthResultFailure = "failure"
+	// AuthResultError means the attempt failed for an internal reason.
+	AuthResultError = "error"
+
+	// CryptoOpEncrypt is an encryption of data or a key.
+	CryptoOpEncrypt = "encrypt"
+	// CryptoOpDecrypt is a decryption of data or a key.
+	CryptoOpDecrypt = "decrypt"
+)
+
+// Observer receives operational measurements of the services, e.g. to export them as metrics.
+type Observer interface {
+	// ObserveAuth records the result of a login or registration attempt.
+	ObserveAuth(operation, result string)
+	// ObserveCrypto records the duration of an encryption or decryption.
+	ObserveCrypto(operation string, d time.Duration)
+}
+
+// nopObserver discards all measurements.
+type nopObserver struct{}
+
+func (nopObserver) ObserveAuth(string, string)          {}
+func (nopObserver) ObserveCrypto(string, time.Duration) {}
+
+// authResult classifies the error of a login or registration attempt.
+func authResult(err error) string {
+	switch {
+	case err == nil:
+		return AuthResultSuccess
+	case errors.Is(err, ErrInvalidCredentials), errors.Is(err, models.ErrUserAlreadyExists),
+		errors.Is(err, models.ErrUserDisabled):
+		return AuthResultFailure
+	default:
+		return AuthResultError
+	}
+}
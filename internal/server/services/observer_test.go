@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordingObserver collects the measurements reported by the services.
+type recordingObserver struct {
+	mu     sync.Mutex
+	auth   []string
+	crypto map[string]int
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{crypto: make(map[string]int)}
+}
+
+func (o *recordingObserver) ObserveAuth(operation, result string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.auth = append(o.auth, operation+":"+result)
+}
+
+func (o *recordingObserver) ObserveCrypto(operation string, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.crypto[operation]++
+}
+
+func TestAuthService_Observer(t *testing.T) {
+	mockRepo := new(MockUserRepo)
+	service := NewAuthService(mockRepo, jwt.NewGenerator("secret", time.Hour))
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+	ctx := context.Background()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(&models.User{ID: uuid.New(), Username: "alice", PasswordHash: string(hash)}, nil)
+	mockRepo.On("GetUserByUsername", mock.Anything, "ghost").Return(nil, models.ErrUserNotFound)
+	mockRepo.On("GetUserByUsername", mock.Anything, "broken").Return(nil, errors.New("connection reset"))
+	mockRepo.On("CreateUser", mock.Anything, mock.Anything).Return(models.ErrUserAlreadyExists)
+
+	_, _, _ = service.Login(ctx, "alice", "password123")
+	_, _, _ = service.Login(ctx, "alice", "wrong")
+	_, _, _ = service.Login(ctx, "ghost", "password123")
+	_, _, _ = service.Login(ctx, "broken", "password123")
+	_, _, _ = service.Register(ctx, "alice", "password123")
+
+	assert.Equal(t, []string{
+		"login:success",
+		"login:failure",
+		"login:failure",
+		"login:error",
+		"register:failure",
+	}, observer.auth)
+}
+
+func TestItemService_ObserverCrypto(t *testing.T) {
+	mockKeyRepo := new(MockKeyRepo)
+	mockItemRepo := new(MockItemRepo)
+	service := NewItemService(mockKeyRepo, mockItemRepo, []byte("master-key-32-bytes-for-aes256!!"))
+	observer := newRecordingObserver()
+	service.SetObserver(observer)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	mockKeyRepo.On("Load", mock.Anything, userID).Return([]byte(nil), false, nil)
+	mockKeyRepo.On("Save", mock.Anything, userID, mock.Anything).Return(nil)
+	mockItemRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := service.CreateItem(ctx, &models.CreateItemRequest{
+		Type:       models.ItemTypeText,
+		Title:      "Note",
+		DataBase64: base64.StdEncoding.EncodeToString([]byte("secret")),
+	}, userID)
+	require.NoError(t, err)
+
+	// The user key, the data key and the data are encrypted.
+	assert.Equal(t, 3, observer.crypto[CryptoOpEncrypt])
+	assert.Zero(t, observer.crypto[CryptoOpDecrypt])
+}
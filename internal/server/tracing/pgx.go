@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer creates a span for every query executed by pgx.
+// Set it as ConnConfig.Tracer of the pool configuration.
+//
+// Only the SQL text is recorded, never the arguments: they carry encrypted secrets and password hashes.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
+	ctx, _ = otel.Tracer(instrumentationName).Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(strings.TrimSpace(data.SQL)),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+	}
+	End(span, data.Err)
+}
+
+// queryOperation returns the SQL keyword the query starts with, such as SELECT or INSERT.
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,129 @@
+// Package tracing provides OpenTelemetry tracing for the GophKeeper server.
+//
+// Setup installs the global tracer provider and the W3C trace-context propagator.
+// Spans are created by the middleware, the services and the repositories through the
+// global provider, and every PostgreSQL query gets its own span from QueryTracer.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service name reported in the resource of every span.
+const ServiceName = "gophkeeper-server"
+
+// instrumentationName identifies the spans created by this package.
+const instrumentationName = "github.com/Pro100x3mal/gophkeeper/internal/server/tracing"
+
+// Supported exporters.
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+	// ExporterOTLP sends spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to the standard output, useful for debugging.
+	ExporterStdout = "stdout"
+)
+
+// Options configures the tracer provider.
+type Options struct {
+	// Exporter is one of ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g. http://localhost:4318.
+	// When empty, the standard OTEL_EXPORTER_OTLP_* environment variables apply.
+	Endpoint string
+	// SampleRatio is the fraction of new traces that are recorded.
+	// Traces started by the client follow the client's sampling decision.
+	SampleRatio float64
+	// ServiceVersion is reported in the resource of every span.
+	ServiceVersion string
+}
+
+// ShutdownFunc flushes the pending spans and stops the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup creates the exporter selected in the options and installs the global tracer provider
+// and propagator. With ExporterNone the propagator is still installed, so incoming trace
+// context is passed on, but no spans are recorded.
+func Setup(ctx context.Context, opts Options) (ShutdownFunc, error) {
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, errors.New("trace sample ratio must be between 0 and 1")
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch opts.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var clientOpts []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := NewProvider(sdktrace.NewBatchSpanProcessor(exporter), opts.SampleRatio, opts.ServiceVersion)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider that passes spans to the processor.
+// Tests use it with a synchronous processor and an in-memory exporter.
+func NewProvider(processor sdktrace.SpanProcessor, sampleRatio float64, serviceVersion string) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(serviceVersion),
+	)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(res),
+	)
+}
+
+// SetupInMemory installs a tracer provider that keeps every finished span in memory
+// and returns the exporter holding them. It is meant for tests, the returned function
+// restores the previous global provider and propagator.
+func SetupInMemory() (*tracetest.InMemoryExporter, func()) {
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(NewProvider(sdktrace.NewSimpleSpanProcessor(exporter), 1, "test"))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return exporter, func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}
+}
+
+// End records the error on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
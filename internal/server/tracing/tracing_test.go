@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func TestSetup(t *testing.T) {
+	_, restore := SetupInMemory()
+	defer restore()
+
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone, SampleRatio: 1})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	shutdown, err = Setup(context.Background(), Options{Exporter: ExporterStdout, SampleRatio: 0.5, ServiceVersion: "1.0.0"})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Options{Exporter: "zipkin", SampleRatio: 1})
+	assert.ErrorContains(t, err, "unsupported trace exporter")
+
+	_, err = Setup(context.Background(), Options{Exporter: ExporterStdout, SampleRatio: 1.5})
+	assert.Error(t, err)
+}
+
+func TestQueryTracer(t *testing.T) {
+	exporter, restore := SetupInMemory()
+	defer restore()
+
+	var tracer QueryTracer
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "\n\t\tselect id from items where user_id = $1\n",
+		Args: []any{"secret-argument"},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 3")})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "DELETE FROM items"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "SELECT", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, semconv.DBSystemNamePostgreSQL)
+	assert.Contains(t, spans[0].Attributes, semconv.DBQueryText("select id from items where user_id = $1"))
+	assert.Contains(t, spans[0].Attributes, semconv.DBResponseReturnedRows(3))
+	for _, attr := range spans[0].Attributes {
+		assert.NotEqual(t, attribute.StringValue("secret-argument"), attr.Value, "query arguments must not be recorded")
+	}
+
+	assert.Equal(t, "DELETE", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "connection reset", spans[1].Status.Description)
+}
+
+func TestQueryOperation(t *testing.T) {
+	assert.Equal(t, "INSERT", queryOperation("  insert into users values ($1)"))
+	assert.Equal(t, "WITH", queryOperation("WITH due AS (SELECT 1) SELECT * FROM due"))
+	assert.Equal(t, "query", queryOperation(" \n"))
+}
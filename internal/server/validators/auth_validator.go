@@ -2,24 +2,231 @@
 // It ensures data integrity and correctness before processing business logic.
 package validators
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
-// ErrEmptyCredentials is returned when login or password is empty during authentication.
-var ErrEmptyCredentials = errors.New("login and password cannot be empty")
+	"github.com/Pro100x3mal/gophkeeper/models"
+	"github.com/Pro100x3mal/gophkeeper/pkg/strength"
+)
+
+const (
+	// MinUsernameLength is the minimum number of characters in a new username.
+	MinUsernameLength = 3
+	// MaxUsernameLength is the maximum number of characters in a username, matching the users.username column.
+	MaxUsernameLength = 255
+	// MaxPasswordBytes is the maximum size of a new password in bytes, bcrypt rejects longer passwords.
+	MaxPasswordBytes = 72
+	// passwordClasses is the number of character classes: lowercase and uppercase letters, digits and other characters.
+	passwordClasses = 4
+)
+
+// reservedUsernames are names that cannot be registered because users may mistake them for the service staff.
+var reservedUsernames = map[string]bool{
+	"admin":         true,
+	"administrator": true,
+	"root":          true,
+	"system":        true,
+	"support":       true,
+	"security":      true,
+	"api":           true,
+	"gophkeeper":    true,
+	"null":          true,
+	"nobody":        true,
+}
+
+// PasswordPolicy holds the rules new passwords must follow.
+// Passwords on the built-in list of common passwords and passwords containing the username are always rejected.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters.
+	MinLength int
+	// MinClasses is the minimum number of character classes: lowercase and uppercase letters, digits and other characters.
+	MinClasses int
+}
+
+// DefaultPasswordPolicy is the password policy of NewAuthValidator.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8, MinClasses: 2}
+
+// Validate checks that the policy can be satisfied.
+func (p PasswordPolicy) Validate() error {
+	if p.MinLength < 1 || p.MinLength > MaxPasswordBytes {
+		return fmt.Errorf("minimum password length must be between 1 and %d", MaxPasswordBytes)
+	}
+	if p.MinClasses < 1 || p.MinClasses > passwordClasses {
+		return fmt.Errorf("minimum number of password character classes must be between 1 and %d", passwordClasses)
+	}
+	return nil
+}
+
+var (
+	// ErrEmptyCredentials is returned when login or password is empty during authentication.
+	ErrEmptyCredentials = errors.New("login and password cannot be empty")
+
+	// ErrEmptyPassword is returned when a password is empty.
+	ErrEmptyPassword = errors.New("password cannot be empty")
+
+	// ErrUsernameTooLong is returned when login exceeds MaxUsernameLength characters.
+	ErrUsernameTooLong = fmt.Errorf("login cannot be longer than %d characters", MaxUsernameLength)
+)
 
 // AuthValidator handles validation of authentication-related requests.
-type AuthValidator struct{}
+type AuthValidator struct {
+	policy PasswordPolicy
+}
 
-// NewAuthValidator creates a new instance of AuthValidator.
+// NewAuthValidator creates a new instance of AuthValidator with DefaultPasswordPolicy.
 func NewAuthValidator() *AuthValidator {
-	return &AuthValidator{}
+	return &AuthValidator{policy: DefaultPasswordPolicy}
 }
 
-// ValidateCredentials validates that both login and password are non-empty.
-// Returns ErrEmptyCredentials if either field is empty.
+// SetPasswordPolicy replaces the password policy. The policy must be valid, see PasswordPolicy.Validate.
+func (v *AuthValidator) SetPasswordPolicy(policy PasswordPolicy) {
+	v.policy = policy
+}
+
+// ValidateCredentials validates that both login and password are non-empty and the login fits MaxUsernameLength.
+// Returns ErrEmptyCredentials if either field is empty or ErrUsernameTooLong if the login is too long.
 func (v *AuthValidator) ValidateCredentials(login, password string) error {
 	if login == "" || password == "" {
 		return ErrEmptyCredentials
 	}
+	if utf8.RuneCountInString(login) > MaxUsernameLength {
+		return ErrUsernameTooLong
+	}
 	return nil
 }
+
+// ValidateRegistration validates the username and password of a new account
+// against the username rules and the password policy.
+// Returns a *models.ValidationError listing every violated rule of the username and password fields.
+func (v *AuthValidator) ValidateRegistration(username, password string) error {
+	var verr models.ValidationError
+	for _, msg := range usernameViolations(username) {
+		verr.Add("username", msg)
+	}
+	for _, msg := range v.passwordViolations(password, username) {
+		verr.Add("password", msg)
+	}
+	return verr.Err()
+}
+
+// ValidateUsername validates a new username for an existing account against the username rules.
+// Returns a *models.ValidationError listing every violated rule of the username field.
+func (v *AuthValidator) ValidateUsername(username string) error {
+	var verr models.ValidationError
+	for _, msg := range usernameViolations(username) {
+		verr.Add("username", msg)
+	}
+	return verr.Err()
+}
+
+// ValidateNewPassword validates a password replacing the current one of the user with the given username
+// against the password policy.
+// Returns a *models.ValidationError listing every violated rule of the new_password field.
+func (v *AuthValidator) ValidateNewPassword(password, username string) error {
+	var verr models.ValidationError
+	for _, msg := range v.passwordViolations(password, username) {
+		verr.Add("new_password", msg)
+	}
+	return verr.Err()
+}
+
+// ValidatePassword validates a password given to re-authenticate.
+// Returns ErrEmptyPassword if it is empty.
+func (v *AuthValidator) ValidatePassword(password string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+	return nil
+}
+
+// usernameViolations returns the username rules the username violates.
+// Usernames consist of ASCII letters, digits, dots, underscores and hyphens, start with a letter or digit
+// and must not be reserved.
+func usernameViolations(username string) []string {
+	if username == "" {
+		return []string{"is required"}
+	}
+
+	var violations []string
+	if n := utf8.RuneCountInString(username); n < MinUsernameLength || n > MaxUsernameLength {
+		violations = append(violations, fmt.Sprintf("must be between %d and %d characters long", MinUsernameLength, MaxUsernameLength))
+	}
+	for i, r := range username {
+		if !isUsernameRune(r) {
+			violations = append(violations, "may contain only letters, digits, dots, underscores and hyphens")
+			break
+		}
+		if i == 0 && !isAlnum(r) {
+			violations = append(violations, "must start with a letter or digit")
+			break
+		}
+	}
+	if reservedUsernames[strings.ToLower(username)] {
+		violations = append(violations, "is reserved")
+	}
+	return violations
+}
+
+// passwordViolations returns the policy rules the password violates.
+// The username rule is skipped when the username is empty.
+func (v *AuthValidator) passwordViolations(password, username string) []string {
+	if password == "" {
+		return []string{"is required"}
+	}
+
+	var violations []string
+	if utf8.RuneCountInString(password) < v.policy.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", v.policy.MinLength))
+	}
+	if len(password) > MaxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("must not be longer than %d bytes", MaxPasswordBytes))
+	}
+	if characterClasses(password) < v.policy.MinClasses {
+		violations = append(violations, fmt.Sprintf(
+			"must contain at least %d of lowercase letters, uppercase letters, digits and other characters", v.policy.MinClasses))
+	}
+	if strength.IsCommon(password) {
+		violations = append(violations, "is too common")
+	}
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		violations = append(violations, "must not contain the username")
+	}
+	return violations
+}
+
+// characterClasses returns the number of character classes used in the password.
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	n := 0
+	for _, used := range []bool{lower, upper, digit, other} {
+		if used {
+			n++
+		}
+	}
+	return n
+}
+
+func isUsernameRune(r rune) bool {
+	return isAlnum(r) || r == '.' || r == '_' || r == '-'
+}
+
+func isAlnum(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
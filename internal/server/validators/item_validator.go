@@ -2,6 +2,9 @@ package validators
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/Pro100x3mal/gophkeeper/models"
 	"github.com/google/uuid"
@@ -14,11 +17,43 @@ var (
 	// ErrEmptyTitle is returned when item title field is empty during creation.
 	ErrEmptyTitle = errors.New("item title cannot be empty")
 
+	// ErrTitleTooLong is returned when item title exceeds MaxTitleLength characters.
+	ErrTitleTooLong = fmt.Errorf("item title cannot be longer than %d characters", MaxTitleLength)
+
+	// ErrMetadataTooLong is returned when item metadata exceeds MaxMetadataSize bytes.
+	ErrMetadataTooLong = fmt.Errorf("item metadata cannot be larger than %d bytes", MaxMetadataSize)
+
 	// ErrInvalidUUID is returned when provided UUID string cannot be parsed.
 	ErrInvalidUUID = errors.New("invalid UUID format")
 
 	// ErrNoFieldsToUpdate is returned when update request contains no fields to update.
 	ErrNoFieldsToUpdate = errors.New("no fields to update")
+
+	// ErrEmptyBatch is returned when a batch request contains no operations.
+	ErrEmptyBatch = errors.New("batch must contain at least one operation")
+
+	// ErrBatchTooLarge is returned when a batch request contains more than MaxBatchSize operations.
+	ErrBatchTooLarge = fmt.Errorf("batch cannot contain more than %d operations", MaxBatchSize)
+
+	// ErrInvalidBatchOperation is returned when a batch operation is malformed.
+	ErrInvalidBatchOperation = errors.New("invalid batch operation")
+
+	// ErrInvalidDays is returned when a number of days is not an integer between 0 and MaxDays.
+	ErrInvalidDays = fmt.Errorf("number of days must be an integer between 0 and %d", MaxDays)
+)
+
+const (
+	// MaxTitleLength is the maximum number of characters in an item title, matching the items.title column.
+	MaxTitleLength = 255
+
+	// MaxMetadataSize is the maximum size of item metadata in bytes.
+	MaxMetadataSize = 64 << 10
+
+	// MaxBatchSize is the maximum number of operations accepted in a single batch request.
+	MaxBatchSize = 1000
+
+	// MaxDays is the maximum number of days accepted in query parameters.
+	MaxDays = 3650
 )
 
 // ItemValidator handles validation of item management requests.
@@ -30,8 +65,8 @@ func NewItemValidator() *ItemValidator {
 }
 
 // ValidateCreateItemRequest validates item creation request.
-// Ensures that type and title fields are non-empty.
-// Returns ErrEmptyType or ErrEmptyTitle if validation fails.
+// Ensures that type and title fields are non-empty and title and metadata fit their limits.
+// Returns ErrEmptyType, ErrEmptyTitle, ErrTitleTooLong or ErrMetadataTooLong if validation fails.
 func (v *ItemValidator) ValidateCreateItemRequest(req *models.CreateItemRequest) error {
 	if req.Type == "" {
 		return ErrEmptyType
@@ -41,19 +76,75 @@ func (v *ItemValidator) ValidateCreateItemRequest(req *models.CreateItemRequest)
 		return ErrEmptyTitle
 	}
 
-	return nil
+	return validateTitleAndMetadata(req.Title, req.Metadata)
 }
 
 // ValidateUpdateItemRequest validates item update request.
-// Ensures that at least one field is provided for update.
+// Ensures that at least one field is provided for update and title and metadata fit their limits.
 // Returns ErrNoFieldsToUpdate if no fields are provided.
 func (v *ItemValidator) ValidateUpdateItemRequest(req *models.UpdateItemRequest) error {
-	if req.Type == nil && req.Title == nil && req.Metadata == nil && req.DataBase64 == nil {
+	if req.Type == nil && req.Title == nil && req.Metadata == nil && req.DataBase64 == nil && req.ExpiresAt == nil {
 		return ErrNoFieldsToUpdate
 	}
+
+	var title, metadata string
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Metadata != nil {
+		metadata = *req.Metadata
+	}
+	return validateTitleAndMetadata(title, metadata)
+}
+
+// validateTitleAndMetadata checks the title and metadata against MaxTitleLength and MaxMetadataSize.
+func validateTitleAndMetadata(title, metadata string) error {
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return ErrTitleTooLong
+	}
+	if len(metadata) > MaxMetadataSize {
+		return ErrMetadataTooLong
+	}
+	return nil
+}
+
+// ValidateBatchRequest validates the envelope of a batch request: it must contain
+// between one and MaxBatchSize operations. Operations are checked with ValidateBatchOperation.
+func (v *ItemValidator) ValidateBatchRequest(req *models.BatchRequest) error {
+	if len(req.Operations) == 0 {
+		return ErrEmptyBatch
+	}
+	if len(req.Operations) > MaxBatchSize {
+		return ErrBatchTooLarge
+	}
 	return nil
 }
 
+// ValidateBatchOperation validates a single operation of a batch request.
+// Create operations require an item, update operations require an ID and changes,
+// and delete operations require an ID.
+func (v *ItemValidator) ValidateBatchOperation(op *models.BatchOperation) error {
+	switch op.Op {
+	case models.BatchOpCreate:
+		if op.Create == nil {
+			return ErrInvalidBatchOperation
+		}
+		return v.ValidateCreateItemRequest(op.Create)
+	case models.BatchOpUpdate:
+		if op.ID == nil || op.Update == nil {
+			return ErrInvalidBatchOperation
+		}
+		return v.ValidateUpdateItemRequest(op.Update)
+	case models.BatchOpDelete:
+		if op.ID == nil {
+			return ErrInvalidBatchOperation
+		}
+		return nil
+	default:
+		return ErrInvalidBatchOperation
+	}
+}
+
 // ValidateUUID validates and parses UUID string.
 // Returns parsed UUID or ErrInvalidUUID if parsing fails.
 func (v *ItemValidator) ValidateUUID(id string) (uuid.UUID, error) {
@@ -63,3 +154,16 @@ func (v *ItemValidator) ValidateUUID(id string) (uuid.UUID, error) {
 	}
 	return parsed, nil
 }
+
+// ValidateDays validates and parses a number of days.
+// Returns def when raw is empty or ErrInvalidDays if the value is out of range.
+func (v *ItemValidator) ValidateDays(raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 || days > MaxDays {
+		return 0, ErrInvalidDays
+	}
+	return days, nil
+}
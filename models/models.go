@@ -6,6 +6,9 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,8 +25,67 @@ var (
 
 	// ErrItemNotFound is returned when an item cannot be found.
 	ErrItemNotFound = errors.New("item not found")
+
+	// ErrQuotaExceeded is returned when a change would take a user over the storage quota.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+	// ErrItemTooLarge is returned when the data of a single item is larger than the whole storage quota.
+	ErrItemTooLarge = errors.New("item data exceeds storage quota")
+
+	// ErrUserDisabled is returned when a disabled user tries to sign in or use a token.
+	ErrUserDisabled = errors.New("user is disabled")
 )
 
+// ValidationError lists the rules a request violates, by request field.
+// It is the JSON body of 400 Bad Request responses to requests with invalid fields.
+type ValidationError struct {
+	// Message summarizes the error.
+	Message string `json:"error"`
+	// Fields maps request fields to the rules they violate.
+	Fields map[string][]string `json:"fields"`
+}
+
+// Add records a violated rule of a field.
+func (e *ValidationError) Add(field, msg string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string][]string)
+	}
+	e.Fields[field] = append(e.Fields[field], msg)
+}
+
+// Err returns e if a rule was violated and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	if e.Message == "" {
+		e.Message = "validation failed"
+	}
+	return e
+}
+
+// Error returns the message followed by the violated rules, ordered by field.
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	msg := e.Message
+	if msg == "" {
+		msg = "validation failed"
+	}
+	for i, field := range fields {
+		sep := "; "
+		if i == 0 {
+			sep = ": "
+		}
+		msg += sep + field + " " + strings.Join(e.Fields[field], ", ")
+	}
+	return msg
+}
+
 // User represents a registered user in the system.
 type User struct {
 	// ID is the unique identifier for the user.
@@ -32,12 +94,41 @@ type User struct {
 	Username string `json:"username"`
 	// PasswordHash is the hashed password (never exposed in JSON).
 	PasswordHash string `json:"-"`
+	// Role grants access to the admin API when set to RoleAdmin.
+	Role Role `json:"role"`
+	// DisabledAt is the timestamp when the user was disabled (nil for active users).
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	// TokensValidAfter rejects the tokens issued before it, it is set when the user is logged out by an admin (optional).
+	TokensValidAfter *time.Time `json:"-"`
 	// CreatedAt is the timestamp when the user was created.
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the timestamp when the user was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Role defines the permissions of a user.
+type Role string
+
+// User roles.
+const (
+	// RoleUser can manage only their own items.
+	RoleUser Role = "user"
+	// RoleAdmin can also manage other users through the admin API.
+	RoleAdmin Role = "admin"
+)
+
+// IsAdmin reports whether the user has the admin role.
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == RoleAdmin
+}
+
+// UserInfo is a user as seen by admins, together with their storage usage.
+type UserInfo struct {
+	User
+	// Usage is the storage currently used by the user.
+	Usage Usage `json:"usage"`
+}
+
 // ItemType represents the type of stored item.
 type ItemType string
 
@@ -50,6 +141,8 @@ const (
 	ItemTypeBinary ItemType = "binary"
 	// ItemTypeCard represents credit card information.
 	ItemTypeCard ItemType = "card"
+	// ItemTypeSSHKey represents an SSH private key.
+	ItemTypeSSHKey ItemType = "ssh-key"
 )
 
 // Item represents a stored item with metadata.
@@ -68,6 +161,10 @@ type Item struct {
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the timestamp when the item was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
+	// ExpiresAt is the timestamp when the item expires and needs to be rotated (optional).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// DeletedAt is the timestamp when the item was moved to the trash (nil if not deleted).
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // EncryptedData represents encrypted data associated with an item.
@@ -82,6 +179,15 @@ type EncryptedData struct {
 	DataKeyEncrypted []byte `json:"data_key_encrypted"`
 }
 
+// Size returns the number of bytes of encrypted data counted against the storage quota.
+// A nil EncryptedData has size zero.
+func (d *EncryptedData) Size() int64 {
+	if d == nil {
+		return 0
+	}
+	return int64(len(d.DataEncrypted))
+}
+
 // CreateItemRequest represents a request to create a new item.
 type CreateItemRequest struct {
 	// Type is the type of item to create.
@@ -92,6 +198,9 @@ type CreateItemRequest struct {
 	Metadata string `json:"metadata"`
 	// DataBase64 is the base64-encoded data content (optional).
 	DataBase64 string `json:"data_base64,omitempty"`
+	// ExpiresAt is the expiration time of the item (optional).
+	// Derived from the payload of cards and certificates when not provided.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // UpdateItemRequest represents a request to update an existing item.
@@ -105,4 +214,171 @@ type UpdateItemRequest struct {
 	Metadata *string `json:"metadata,omitempty"`
 	// DataBase64 is the new base64-encoded data content (optional).
 	DataBase64 *string `json:"data_base64,omitempty"`
+	// ExpiresAt is the new expiration time of the item (optional).
+	// Derived from the new payload of cards and certificates when not provided.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// DueReason describes why an item needs attention.
+type DueReason string
+
+const (
+	// DueReasonExpired means the item has already expired.
+	DueReasonExpired DueReason = "expired"
+	// DueReasonExpiring means the item expires within the requested period.
+	DueReasonExpiring DueReason = "expiring"
+	// DueReasonStale means the item has not been updated for longer than the requested age.
+	DueReasonStale DueReason = "stale"
+)
+
+// DueItem represents an item that is expired, expiring soon or not rotated for too long.
+type DueItem struct {
+	// Item is the item that needs attention.
+	Item *Item `json:"item"`
+	// Reason describes why the item is due.
+	Reason DueReason `json:"reason"`
+}
+
+// BatchOpType represents the kind of operation in a batch request.
+type BatchOpType string
+
+const (
+	// BatchOpCreate creates a new item.
+	BatchOpCreate BatchOpType = "create"
+	// BatchOpUpdate updates an existing item.
+	BatchOpUpdate BatchOpType = "update"
+	// BatchOpDelete deletes an existing item.
+	BatchOpDelete BatchOpType = "delete"
+)
+
+// BatchOperation represents a single operation within a batch request.
+type BatchOperation struct {
+	// Op is the kind of operation.
+	Op BatchOpType `json:"op"`
+	// ID is the target item ID for update and delete operations.
+	ID *uuid.UUID `json:"id,omitempty"`
+	// Create is the item to create for create operations.
+	Create *CreateItemRequest `json:"create,omitempty"`
+	// Update contains the changes for update operations.
+	Update *UpdateItemRequest `json:"update,omitempty"`
+}
+
+// BatchRequest represents a request to execute several item operations at once.
+type BatchRequest struct {
+	// Atomic makes all operations succeed or fail together when true.
+	// Otherwise each operation is applied independently and reported separately.
+	Atomic bool `json:"atomic"`
+	// Operations are the operations to execute in order.
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult represents the outcome of a single batch operation.
+type BatchResult struct {
+	// Index is the position of the operation in the request.
+	Index int `json:"index"`
+	// Op is the kind of operation.
+	Op BatchOpType `json:"op"`
+	// Status is the HTTP status code describing the outcome of the operation.
+	Status int `json:"status"`
+	// Item is the resulting item for successful create and update operations.
+	Item *Item `json:"item,omitempty"`
+	// Error describes why the operation failed (empty on success).
+	Error string `json:"error,omitempty"`
+}
+
+// BatchResponse represents the response to a batch request.
+type BatchResponse struct {
+	// Results contains one entry per executed operation.
+	Results []BatchResult `json:"results"`
+}
+
+// ItemMutation represents a prepared batch operation ready to be persisted.
+// Payloads are already encrypted; Item holds the resulting item once applied.
+type ItemMutation struct {
+	// Op is the kind of operation.
+	Op BatchOpType
+	// ItemID is the target item ID.
+	ItemID uuid.UUID
+	// Item is the item to insert for create operations and the resulting item after applying.
+	Item *Item
+	// Update contains the changes for update operations.
+	Update *UpdateItemRequest
+	// EncData is the encrypted payload to store (optional).
+	EncData *EncryptedData
+}
+
+// ComponentState is the state of a server dependency checked by the readiness probe.
+type ComponentState string
+
+const (
+	// ComponentUp means the dependency is usable.
+	ComponentUp ComponentState = "up"
+	// ComponentDown means the dependency failed its check.
+	ComponentDown ComponentState = "down"
+)
+
+// ComponentStatus is the result of a single readiness check.
+type ComponentStatus struct {
+	// Status is the state of the component.
+	Status ComponentState `json:"status"`
+	// LatencyMS is the duration of the check in milliseconds.
+	LatencyMS float64 `json:"latency_ms"`
+	// Error describes why the check failed (empty when the component is up).
+	Error string `json:"error,omitempty"`
+}
+
+// Overall states reported by the readiness probe.
+const (
+	// ReadinessReady means every component is up.
+	ReadinessReady = "ready"
+	// ReadinessNotReady means at least one component is down.
+	ReadinessNotReady = "not_ready"
+)
+
+// ReadinessReport is the response of the readiness probe.
+type ReadinessReport struct {
+	// Status is ReadinessReady or ReadinessNotReady.
+	Status string `json:"status"`
+	// Components holds the result of each check by component name.
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Quota limits the storage used by a single user. Zero fields are not limited.
+type Quota struct {
+	// MaxItems is the maximum number of items outside the trash.
+	MaxItems int64 `json:"max_items"`
+	// MaxBytes is the maximum total size of encrypted data of items outside the trash in bytes.
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+// Check reports whether the usage after a change by delta stays within the quota.
+// Changes that do not grow the usage are always allowed, so users over a lowered quota can still free space.
+// Returns ErrItemTooLarge if the added bytes alone exceed MaxBytes and ErrQuotaExceeded if the new usage does.
+func (q Quota) Check(usage, delta Usage) error {
+	if q.MaxBytes > 0 && delta.Bytes > q.MaxBytes {
+		return fmt.Errorf("%w: limit is %d bytes", ErrItemTooLarge, q.MaxBytes)
+	}
+	if q.MaxItems > 0 && delta.Items > 0 && usage.Items+delta.Items > q.MaxItems {
+		return fmt.Errorf("%w: limit is %d items", ErrQuotaExceeded, q.MaxItems)
+	}
+	if q.MaxBytes > 0 && delta.Bytes > 0 && usage.Bytes+delta.Bytes > q.MaxBytes {
+		return fmt.Errorf("%w: limit is %d bytes", ErrQuotaExceeded, q.MaxBytes)
+	}
+	return nil
+}
+
+// Usage is the storage used by a single user.
+type Usage struct {
+	// Items is the number of items outside the trash.
+	Items int64 `json:"items"`
+	// Bytes is the total size of encrypted data of items outside the trash in bytes.
+	Bytes int64 `json:"bytes"`
+}
+
+// UsageReport represents the storage usage of a user together with the quota.
+type UsageReport struct {
+	// Usage is the storage currently used.
+	Usage Usage `json:"usage"`
+	// Quota is the storage limit (zero fields are not limited).
+	Quota Quota `json:"quota"`
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestItemType_Constants(t *testing.T) {
@@ -18,6 +19,7 @@ func TestItemType_Constants(t *testing.T) {
 		{"Text type", ItemTypeText, "text"},
 		{"Binary type", ItemTypeBinary, "binary"},
 		{"Card type", ItemTypeCard, "card"},
+		{"SSH key type", ItemTypeSSHKey, "ssh-key"},
 	}
 
 	for _, tt := range tests {
@@ -134,3 +136,63 @@ func TestUpdateItemRequest_NilFields(t *testing.T) {
 	assert.Nil(t, req.Metadata)
 	assert.Nil(t, req.DataBase64)
 }
+
+func TestQuota_Check(t *testing.T) {
+	quota := Quota{MaxItems: 2, MaxBytes: 100}
+	usage := Usage{Items: 1, Bytes: 60}
+
+	tests := []struct {
+		name    string
+		quota   Quota
+		delta   Usage
+		wantErr error
+	}{
+		{"Fits", quota, Usage{Items: 1, Bytes: 40}, nil},
+		{"Too many items", quota, Usage{Items: 2}, ErrQuotaExceeded},
+		{"Too many bytes", quota, Usage{Bytes: 41}, ErrQuotaExceeded},
+		{"Item larger than quota", quota, Usage{Items: 1, Bytes: 101}, ErrItemTooLarge},
+		{"Release", quota, Usage{Items: -1, Bytes: -60}, nil},
+		{"Unlimited", Quota{}, Usage{Items: 1 << 20, Bytes: 1 << 40}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.Check(usage, tt.delta)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestQuota_Check_OverLoweredQuota(t *testing.T) {
+	quota := Quota{MaxItems: 1, MaxBytes: 10}
+	usage := Usage{Items: 5, Bytes: 500}
+
+	assert.NoError(t, quota.Check(usage, Usage{Items: -1, Bytes: -100}), "freeing space is allowed over the quota")
+	assert.NoError(t, quota.Check(usage, Usage{}), "metadata changes are allowed over the quota")
+	assert.ErrorIs(t, quota.Check(usage, Usage{Bytes: 1}), ErrQuotaExceeded)
+}
+
+func TestUser_IsAdmin(t *testing.T) {
+	assert.True(t, (&User{Role: RoleAdmin}).IsAdmin())
+	assert.False(t, (&User{Role: RoleUser}).IsAdmin())
+	assert.False(t, (&User{}).IsAdmin())
+	assert.False(t, (*User)(nil).IsAdmin())
+}
+
+func TestValidationError(t *testing.T) {
+	var verr ValidationError
+	assert.NoError(t, verr.Err())
+
+	verr.Add("username", "is reserved")
+	verr.Add("password", "must be at least 8 characters long")
+	verr.Add("password", "must not contain the username")
+
+	err := verr.Err()
+	require.Error(t, err)
+	assert.Equal(t, "validation failed: password must be at least 8 characters long, must not contain the username; "+
+		"username is reserved", err.Error())
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCardExpiry is returned when a card expiry is not in MM/YY or MM/YYYY format.
+var ErrInvalidCardExpiry = errors.New("invalid card expiry, expected MM/YY")
+
+// CredentialPayload represents the decrypted data of a credential item.
+type CredentialPayload struct {
+	// Username is the login name for the account.
+	Username string `json:"username"`
+	// Password is the secret used to authenticate.
+	Password string `json:"password"`
+	// URL is the address of the service the credential belongs to (optional).
+	URL string `json:"url,omitempty"`
+	// Notes contains free-form notes attached to the credential (optional).
+	Notes string `json:"notes,omitempty"`
+	// OTP is an otpauth:// URI or a base32 TOTP secret for two-factor codes (optional).
+	OTP string `json:"otp,omitempty"`
+}
+
+// CardPayload represents the decrypted data of a card item.
+type CardPayload struct {
+	// Number is the card number.
+	Number string `json:"number"`
+	// Holder is the cardholder name.
+	Holder string `json:"holder"`
+	// Expiry is the expiration date in MM/YY format.
+	Expiry string `json:"expiry"`
+	// CVV is the card verification value.
+	CVV string `json:"cvv"`
+	// Brand is the card brand, e.g. Visa or Mastercard (optional).
+	Brand string `json:"brand,omitempty"`
+	// Notes contains free-form notes attached to the card (optional).
+	Notes string `json:"notes,omitempty"`
+}
+
+// SSHKeyPayload represents the decrypted data of an SSH key item.
+type SSHKeyPayload struct {
+	// PrivateKey is the private key in PEM or OpenSSH format.
+	PrivateKey string `json:"private_key"`
+	// Passphrase decrypts the private key if it is encrypted (optional).
+	Passphrase string `json:"passphrase,omitempty"`
+	// Comment is shown by ssh-add -l instead of the item title (optional).
+	Comment string `json:"comment,omitempty"`
+}
+
+// ExpiresAt returns the moment the card stops being valid.
+// Cards are valid through the last day of the expiry month, so this is the
+// start of the following month in UTC.
+func (p CardPayload) ExpiresAt() (time.Time, error) {
+	month, year, ok := strings.Cut(strings.TrimSpace(p.Expiry), "/")
+	if !ok {
+		return time.Time{}, ErrInvalidCardExpiry
+	}
+
+	m, err := strconv.Atoi(strings.TrimSpace(month))
+	if err != nil || m < 1 || m > 12 {
+		return time.Time{}, ErrInvalidCardExpiry
+	}
+
+	year = strings.TrimSpace(year)
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return time.Time{}, ErrInvalidCardExpiry
+	}
+	switch len(year) {
+	case 2:
+		y += 2000
+	case 4:
+	default:
+		return time.Time{}, ErrInvalidCardExpiry
+	}
+
+	return time.Date(y, time.Month(m)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// ItemMetadata represents the structured form of Item.Metadata.
+// Items created before structured metadata was introduced keep their text in Notes.
+type ItemMetadata struct {
+	// Folder is the slash-separated folder the item belongs to (optional).
+	Folder string `json:"folder,omitempty"`
+	// URL is the address the item is associated with (optional).
+	URL string `json:"url,omitempty"`
+	// Source describes where the item was imported from (optional).
+	Source string `json:"source,omitempty"`
+	// Notes contains free-form text (optional).
+	Notes string `json:"notes,omitempty"`
+}
+
+// ParseItemMetadata decodes item metadata stored as JSON.
+// Metadata that is not a JSON object is returned as Notes.
+func ParseItemMetadata(raw string) ItemMetadata {
+	var meta ItemMetadata
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return meta
+	}
+	if strings.HasPrefix(trimmed, "{") && json.Unmarshal([]byte(trimmed), &meta) == nil {
+		return meta
+	}
+	return ItemMetadata{Notes: raw}
+}
+
+// String encodes the metadata as JSON.
+// Returns an empty string when no fields are set.
+func (m ItemMetadata) String() string {
+	if m == (ItemMetadata{}) {
+		return ""
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseItemMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected ItemMetadata
+	}{
+		{"Empty", "", ItemMetadata{}},
+		{"JSON", `{"folder":"Work","url":"https://example.com"}`, ItemMetadata{Folder: "Work", URL: "https://example.com"}},
+		{"Plain text", "My secret notes", ItemMetadata{Notes: "My secret notes"}},
+		{"Invalid JSON", "{not json", ItemMetadata{Notes: "{not json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseItemMetadata(tt.raw))
+		})
+	}
+}
+
+func TestItemMetadata_String(t *testing.T) {
+	assert.Equal(t, "", ItemMetadata{}.String())
+
+	meta := ItemMetadata{Folder: "Work", Source: "keepass-xml"}
+	assert.Equal(t, `{"folder":"Work","source":"keepass-xml"}`, meta.String())
+	assert.Equal(t, meta, ParseItemMetadata(meta.String()))
+}
+
+func TestCardPayload_ExpiresAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		expiry   string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"Short year", "03/27", time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Long year", "12/2026", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Spaces", " 1 / 30 ", time.Date(2030, time.February, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Empty", "", time.Time{}, true},
+		{"Invalid month", "13/27", time.Time{}, true},
+		{"Invalid year", "03/7", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CardPayload{Expiry: tt.expiry}.ExpiresAt()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCardExpiry)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package breach checks passwords against a corpus of breached password hashes.
+//
+// The check uses the k-anonymity model of the Have I Been Pwned range API: a password
+// is hashed with SHA-1 and only the first five hex characters of the hash are used to
+// look up candidate suffixes. The corpus is a local file, so no data leaves the machine.
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrefixLength is the number of hex characters of the hash used for range lookups.
+const PrefixLength = 5
+
+// hashLength is the length of a hex-encoded SHA-1 hash.
+const hashLength = sha1.Size * 2
+
+// ErrInvalidPrefix is returned when a range prefix is not five hex characters.
+var ErrInvalidPrefix = errors.New("hash prefix must be 5 hex characters")
+
+// Corpus looks up breached hashes by prefix.
+type Corpus interface {
+	// Range returns the hash suffixes starting with prefix mapped to the number
+	// of times the password was seen in breaches.
+	Range(prefix string) (map[string]int, error)
+}
+
+// HashPrefix returns the upper-case SHA-1 hash of the password split into
+// the range prefix and the remaining suffix.
+func HashPrefix(password string) (prefix, suffix string) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return hash[:PrefixLength], hash[PrefixLength:]
+}
+
+// Check returns how many times the password was seen in the corpus.
+// Zero means the password was not found.
+func Check(c Corpus, password string) (int, error) {
+	prefix, suffix := HashPrefix(password)
+	suffixes, err := c.Range(prefix)
+	if err != nil {
+		return 0, err
+	}
+	return suffixes[suffix], nil
+}
+
+// FileCorpus is a corpus stored in a text file with one "HASH[:COUNT]" entry per line,
+// sorted by hash, as in the ordered-by-hash SHA-1 downloads of Have I Been Pwned.
+// Lookups binary search the file, so it is never loaded into memory.
+type FileCorpus struct {
+	file *os.File
+	size int64
+}
+
+// OpenFile opens a corpus file.
+func OpenFile(path string) (*FileCorpus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach corpus: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to stat breach corpus: %w", err)
+	}
+	return &FileCorpus{file: f, size: info.Size()}, nil
+}
+
+// Close closes the corpus file.
+func (c *FileCorpus) Close() error {
+	return c.file.Close()
+}
+
+// Range returns the hash suffixes starting with prefix.
+func (c *FileCorpus) Range(prefix string) (map[string]int, error) {
+	prefix = strings.ToUpper(prefix)
+	if len(prefix) != PrefixLength {
+		return nil, ErrInvalidPrefix
+	}
+	if _, err := hex.DecodeString(prefix + "0"); err != nil {
+		return nil, ErrInvalidPrefix
+	}
+
+	// Find the first line at or after each offset and search for the first one not below the prefix.
+	var searchErr error
+	start := sort.Search(int(c.size), func(off int) bool {
+		if searchErr != nil {
+			return true
+		}
+		_, line, err := c.lineAfter(int64(off))
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				searchErr = err
+			}
+			return true
+		}
+		return strings.ToUpper(line) >= prefix
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	pos, _, err := c.lineAfter(int64(start))
+	if errors.Is(err, io.EOF) {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := make(map[string]int)
+	scanner := bufio.NewScanner(io.NewSectionReader(c.file, pos, c.size-pos))
+	for scanner.Scan() {
+		hash, count, ok := parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if !strings.HasPrefix(hash, prefix) {
+			if hash > prefix {
+				break
+			}
+			continue
+		}
+		suffixes[hash[PrefixLength:]] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breach corpus: %w", err)
+	}
+	return suffixes, nil
+}
+
+// lineAfter returns the position and content of the first line starting at or after off.
+// A line starts at off if off is zero or the preceding byte is a newline.
+func (c *FileCorpus) lineAfter(off int64) (int64, string, error) {
+	pos := off
+	if off > 0 {
+		pos = off - 1
+	}
+	r := bufio.NewReader(io.NewSectionReader(c.file, pos, c.size-pos))
+	if off > 0 {
+		skipped, err := r.ReadString('\n')
+		if err != nil {
+			return 0, "", io.EOF
+		}
+		pos += int64(len(skipped))
+	}
+	line, err := r.ReadString('\n')
+	if line == "" && err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, "", io.EOF
+		}
+		return 0, "", fmt.Errorf("failed to read breach corpus: %w", err)
+	}
+	return pos, strings.TrimRight(line, "\r\n"), nil
+}
+
+// parseLine parses a "HASH[:COUNT]" line. Lines without a count are counted once.
+func parseLine(line string) (string, int, bool) {
+	hash, rawCount, hasCount := strings.Cut(strings.TrimSpace(line), ":")
+	if len(hash) != hashLength {
+		return "", 0, false
+	}
+	count := 1
+	if hasCount {
+		n, err := strconv.Atoi(rawCount)
+		if err != nil || n < 1 {
+			return "", 0, false
+		}
+		count = n
+	}
+	return strings.ToUpper(hash), count, true
+}
@@ -0,0 +1,128 @@
+package breach
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCorpus writes a sorted corpus file with the given passwords and filler hashes.
+func writeCorpus(t *testing.T, counts map[string]int, filler int) string {
+	t.Helper()
+
+	var lines []string
+	for password, count := range counts {
+		prefix, suffix := HashPrefix(password)
+		lines = append(lines, prefix+suffix+":"+strconv.Itoa(count))
+	}
+	for i := range filler {
+		prefix, suffix := HashPrefix("filler-" + strconv.Itoa(i))
+		lines = append(lines, prefix+suffix+":1")
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600))
+	return path
+}
+
+func TestHashPrefix(t *testing.T) {
+	prefix, suffix := HashPrefix("password")
+	assert.Equal(t, "5BAA6", prefix)
+	assert.Equal(t, "1E4C9B93F3F0682250B6CF8331B7EE68FD8", suffix)
+}
+
+func TestCheck(t *testing.T) {
+	path := writeCorpus(t, map[string]int{"password": 9545824, "123456": 37359195, "letmein": 3}, 2000)
+	corpus, err := OpenFile(path)
+	require.NoError(t, err)
+	defer corpus.Close()
+
+	tests := []struct {
+		password string
+		expected int
+	}{
+		{"password", 9545824},
+		{"123456", 37359195},
+		{"letmein", 3},
+		{"filler-0", 1},
+		{"filler-1999", 1},
+		{"not in the corpus", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			count, err := Check(corpus, tt.password)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, count)
+		})
+	}
+}
+
+func TestFileCorpus_Range(t *testing.T) {
+	t.Run("returns all suffixes of a prefix", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "corpus.txt")
+		zeros, as := strings.Repeat("0", 35), strings.Repeat("A", 35)
+		content := "00000" + as + ":2\n" +
+			"5BAA6" + zeros + "\n" +
+			"5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:5\n" +
+			"FFFFF" + as + ":1"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		corpus, err := OpenFile(path)
+		require.NoError(t, err)
+		defer corpus.Close()
+
+		suffixes, err := corpus.Range("5baa6")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			strings.Repeat("0", 35):               1,
+			"1E4C9B93F3F0682250B6CF8331B7EE68FD8": 5,
+		}, suffixes)
+
+		suffixes, err = corpus.Range("FFFFF")
+		require.NoError(t, err)
+		assert.Len(t, suffixes, 1)
+
+		suffixes, err = corpus.Range("12345")
+		require.NoError(t, err)
+		assert.Empty(t, suffixes)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "corpus.txt")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		corpus, err := OpenFile(path)
+		require.NoError(t, err)
+		defer corpus.Close()
+
+		suffixes, err := corpus.Range("5BAA6")
+		require.NoError(t, err)
+		assert.Empty(t, suffixes)
+	})
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		path := writeCorpus(t, nil, 1)
+		corpus, err := OpenFile(path)
+		require.NoError(t, err)
+		defer corpus.Close()
+
+		_, err = corpus.Range("5BAA")
+		assert.ErrorIs(t, err, ErrInvalidPrefix)
+		_, err = corpus.Range("XYZXY")
+		assert.ErrorIs(t, err, ErrInvalidPrefix)
+	})
+}
+
+func TestOpenFile_NotFound(t *testing.T) {
+	_, err := OpenFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -56,6 +56,14 @@ func (g *Generator) GenerateToken(userID uuid.UUID) (string, error) {
 	return token.SignedString(g.secret)
 }
 
+// Claims are the verified claims of a token.
+type Claims struct {
+	// UserID is the user the token was issued to.
+	UserID uuid.UUID
+	// IssuedAt is the time the token was issued, truncated to seconds.
+	IssuedAt time.Time
+}
+
 // ValidateToken validates a JWT token string and extracts the user ID.
 // Checks the signature, expiration, and extracts the subject claim.
 //
@@ -64,6 +72,16 @@ func (g *Generator) GenerateToken(userID uuid.UUID) (string, error) {
 //
 // Returns the user ID from the token or an error if validation fails.
 func (g *Generator) ValidateToken(tokenString string) (uuid.UUID, error) {
+	claims, err := g.ParseToken(tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return claims.UserID, nil
+}
+
+// ParseToken validates a JWT token string like ValidateToken and returns its claims.
+// Tokens without the issued at claim are rejected.
+func (g *Generator) ParseToken(tokenString string) (*Claims, error) {
 	claims := &jwt.RegisteredClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -72,17 +90,17 @@ func (g *Generator) ValidateToken(tokenString string) (uuid.UUID, error) {
 		return g.secret, nil
 	})
 	if err != nil {
-		return uuid.Nil, err
+		return nil, err
 	}
 
-	if !token.Valid {
-		return uuid.Nil, ErrInvalidToken
+	if !token.Valid || claims.IssuedAt == nil {
+		return nil, ErrInvalidToken
 	}
 
 	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	return userID, nil
+	return &Claims{UserID: userID, IssuedAt: claims.IssuedAt.Time}, nil
 }
@@ -186,3 +186,35 @@ func TestGenerateAndValidate_MultipleTokens(t *testing.T) {
 		assert.Equal(t, userID, validatedUserID)
 	}
 }
+
+func TestParseToken_Success(t *testing.T) {
+	gen := NewGenerator("test_secret", time.Hour)
+	userID := uuid.New()
+	before := time.Now().Truncate(time.Second)
+
+	token, err := gen.GenerateToken(userID)
+	require.NoError(t, err)
+
+	claims, err := gen.ParseToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.False(t, claims.IssuedAt.Before(before))
+	assert.False(t, claims.IssuedAt.After(time.Now()))
+}
+
+func TestParseToken_MissingIssuedAt(t *testing.T) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test_secret"))
+	require.NoError(t, err)
+
+	gen := NewGenerator("test_secret", time.Hour)
+	parsed, err := gen.ParseToken(tokenString)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, parsed)
+}
@@ -0,0 +1,267 @@
+// Package otp implements HOTP (RFC 4226) and TOTP (RFC 6238) one-time passwords.
+//
+// Keys are parsed from otpauth:// URIs as used by authenticator apps or from
+// bare base32 secrets, which are treated as TOTP keys with default parameters.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the kind of one-time password.
+type Type string
+
+const (
+	// TypeTOTP is a time-based one-time password.
+	TypeTOTP Type = "totp"
+	// TypeHOTP is a counter-based one-time password.
+	TypeHOTP Type = "hotp"
+)
+
+// Algorithm is the HMAC hash function used to compute codes.
+type Algorithm string
+
+const (
+	// AlgorithmSHA1 is HMAC-SHA1, the default of most authenticators.
+	AlgorithmSHA1 Algorithm = "SHA1"
+	// AlgorithmSHA256 is HMAC-SHA256.
+	AlgorithmSHA256 Algorithm = "SHA256"
+	// AlgorithmSHA512 is HMAC-SHA512.
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+// Default key parameters.
+const (
+	// DefaultDigits is the default number of digits in a code.
+	DefaultDigits = 6
+	// DefaultPeriod is the default TOTP time step.
+	DefaultPeriod = 30 * time.Second
+)
+
+var (
+	// ErrInvalidURI is returned when an otpauth URI is malformed.
+	ErrInvalidURI = errors.New("invalid otpauth URI")
+
+	// ErrInvalidSecret is returned when the secret is missing or not valid base32.
+	ErrInvalidSecret = errors.New("invalid OTP secret, expected base32")
+
+	// ErrUnsupportedAlgorithm is returned for algorithms other than SHA1, SHA256 and SHA512.
+	ErrUnsupportedAlgorithm = errors.New("unsupported OTP algorithm")
+
+	// ErrInvalidDigits is returned when the number of digits is not 6 or 8.
+	ErrInvalidDigits = errors.New("OTP digits must be 6 or 8")
+
+	// ErrInvalidPeriod is returned when the TOTP period is not a positive number of seconds.
+	ErrInvalidPeriod = errors.New("OTP period must be a positive number of seconds")
+
+	// ErrInvalidCounter is returned when the HOTP counter is missing or not a number.
+	ErrInvalidCounter = errors.New("HOTP counter must be a non-negative number")
+)
+
+// Key holds the parameters of a one-time password generator.
+type Key struct {
+	// Type is TOTP or HOTP.
+	Type Type
+	// Issuer is the provider of the account (optional).
+	Issuer string
+	// Account is the account name, usually the username or e-mail (optional).
+	Account string
+	// Secret is the decoded shared secret.
+	Secret []byte
+	// Algorithm is the HMAC hash function.
+	Algorithm Algorithm
+	// Digits is the number of digits in a code.
+	Digits int
+	// Period is the TOTP time step.
+	Period time.Duration
+	// Counter is the next HOTP counter value.
+	Counter uint64
+}
+
+// Parse parses an otpauth:// URI or a bare base32 secret.
+func Parse(s string) (*Key, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return parseURI(s)
+	}
+
+	secret, err := decodeSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		Type:      TypeTOTP,
+		Secret:    secret,
+		Algorithm: AlgorithmSHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}, nil
+}
+
+// parseURI parses a key URI in the format
+// otpauth://TYPE/LABEL?secret=SECRET&issuer=ISSUER&algorithm=ALG&digits=N&period=N&counter=N.
+func parseURI(s string) (*Key, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+
+	key := &Key{
+		Type:      Type(strings.ToLower(u.Host)),
+		Algorithm: AlgorithmSHA1,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	if key.Type != TypeTOTP && key.Type != TypeHOTP {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidURI, u.Host)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		key.Issuer, key.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		key.Account = strings.TrimSpace(label)
+	}
+
+	q := u.Query()
+	if key.Secret, err = decodeSecret(q.Get("secret")); err != nil {
+		return nil, err
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if alg := q.Get("algorithm"); alg != "" {
+		key.Algorithm = Algorithm(strings.ToUpper(alg))
+		if _, err = key.Algorithm.hash(); err != nil {
+			return nil, err
+		}
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil || (key.Digits != 6 && key.Digits != 8) {
+			return nil, ErrInvalidDigits
+		}
+	}
+	if period := q.Get("period"); period != "" {
+		seconds, err := strconv.Atoi(period)
+		if err != nil || seconds <= 0 {
+			return nil, ErrInvalidPeriod
+		}
+		key.Period = time.Duration(seconds) * time.Second
+	}
+	if key.Type == TypeHOTP {
+		if key.Counter, err = strconv.ParseUint(q.Get("counter"), 10, 64); err != nil {
+			return nil, ErrInvalidCounter
+		}
+	}
+	return key, nil
+}
+
+// decodeSecret decodes a base32 secret ignoring case, spaces and padding.
+func decodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	if s == "" {
+		return nil, ErrInvalidSecret
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return secret, nil
+}
+
+// URI encodes the key as an otpauth:// URI.
+func (k *Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+
+	q := url.Values{}
+	q.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(k.Secret))
+	if k.Issuer != "" {
+		q.Set("issuer", k.Issuer)
+	}
+	q.Set("algorithm", string(k.Algorithm))
+	q.Set("digits", strconv.Itoa(k.Digits))
+	if k.Type == TypeHOTP {
+		q.Set("counter", strconv.FormatUint(k.Counter, 10))
+	} else {
+		q.Set("period", strconv.Itoa(int(k.Period/time.Second)))
+	}
+
+	u := url.URL{Scheme: "otpauth", Host: string(k.Type), Path: "/" + label, RawQuery: q.Encode()}
+	return u.String()
+}
+
+// Code returns the TOTP code for the time t and how long it stays valid.
+// For HOTP keys the code for the current counter is returned with zero remaining time.
+func (k *Key) Code(t time.Time) (string, time.Duration, error) {
+	if k.Type == TypeHOTP {
+		code, err := HOTP(k.Secret, k.Counter, k.Digits, k.Algorithm)
+		return code, 0, err
+	}
+
+	if k.Period <= 0 {
+		return "", 0, ErrInvalidPeriod
+	}
+	step := int64(k.Period / time.Second)
+	unix := t.Unix()
+	code, err := HOTP(k.Secret, uint64(unix/step), k.Digits, k.Algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+	remaining := time.Duration(step-unix%step) * time.Second
+	return code, remaining, nil
+}
+
+// HOTP computes the code for a counter value as defined in RFC 4226.
+func HOTP(secret []byte, counter uint64, digits int, alg Algorithm) (string, error) {
+	if digits != 6 && digits != 8 {
+		return "", ErrInvalidDigits
+	}
+	h, err := alg.hash()
+	if err != nil {
+		return "", err
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(h, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+// hash returns the hash constructor of the algorithm.
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case AlgorithmSHA1, "":
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, a)
+	}
+}
@@ -0,0 +1,120 @@
+package otp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHOTP_RFC4226(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, want := range expected {
+		code, err := HOTP(secret, uint64(counter), 6, AlgorithmSHA1)
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "counter %d", counter)
+	}
+}
+
+func TestKeyCode_RFC6238(t *testing.T) {
+	secrets := map[Algorithm][]byte{
+		AlgorithmSHA1:   []byte("12345678901234567890"),
+		AlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		AlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	tests := []struct {
+		unix int64
+		alg  Algorithm
+		code string
+	}{
+		{59, AlgorithmSHA1, "94287082"},
+		{59, AlgorithmSHA256, "46119246"},
+		{59, AlgorithmSHA512, "90693936"},
+		{1111111109, AlgorithmSHA1, "07081804"},
+		{1111111109, AlgorithmSHA256, "68084774"},
+		{1111111109, AlgorithmSHA512, "25091201"},
+		{20000000000, AlgorithmSHA1, "65353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.alg), func(t *testing.T) {
+			key := &Key{Type: TypeTOTP, Secret: secrets[tt.alg], Algorithm: tt.alg, Digits: 8, Period: DefaultPeriod}
+			code, remaining, err := key.Code(time.Unix(tt.unix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, time.Duration(30-tt.unix%30)*time.Second, remaining)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("totp uri", func(t *testing.T) {
+		key, err := Parse("otpauth://totp/Example:alice@example.com?secret=" + secret + "&issuer=Example&algorithm=SHA256&digits=8&period=60")
+		require.NoError(t, err)
+		assert.Equal(t, TypeTOTP, key.Type)
+		assert.Equal(t, "Example", key.Issuer)
+		assert.Equal(t, "alice@example.com", key.Account)
+		assert.Equal(t, []byte("12345678901234567890"), key.Secret)
+		assert.Equal(t, AlgorithmSHA256, key.Algorithm)
+		assert.Equal(t, 8, key.Digits)
+		assert.Equal(t, time.Minute, key.Period)
+	})
+
+	t.Run("hotp uri", func(t *testing.T) {
+		key, err := Parse("otpauth://hotp/alice?secret=" + strings.ToLower(secret) + "&counter=3")
+		require.NoError(t, err)
+		assert.Equal(t, TypeHOTP, key.Type)
+		assert.Equal(t, uint64(3), key.Counter)
+
+		code, remaining, err := key.Code(time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "969429", code)
+		assert.Zero(t, remaining)
+	})
+
+	t.Run("bare secret with spaces", func(t *testing.T) {
+		key, err := Parse("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+		require.NoError(t, err)
+		assert.Equal(t, TypeTOTP, key.Type)
+		assert.Equal(t, []byte("12345678901234567890"), key.Secret)
+		assert.Equal(t, AlgorithmSHA1, key.Algorithm)
+		assert.Equal(t, DefaultDigits, key.Digits)
+		assert.Equal(t, DefaultPeriod, key.Period)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		key, err := Parse("otpauth://hotp/My%20Bank:bob?secret=" + secret + "&counter=7&digits=8&algorithm=sha512")
+		require.NoError(t, err)
+
+		parsed, err := Parse(key.URI())
+		require.NoError(t, err)
+		assert.Equal(t, key, parsed)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			input string
+			err   error
+		}{
+			{"", ErrInvalidSecret},
+			{"not base32!", ErrInvalidSecret},
+			{"otpauth://totp/alice", ErrInvalidSecret},
+			{"otpauth://push/alice?secret=" + secret, ErrInvalidURI},
+			{"otpauth://totp/alice?secret=" + secret + "&algorithm=MD5", ErrUnsupportedAlgorithm},
+			{"otpauth://totp/alice?secret=" + secret + "&digits=7", ErrInvalidDigits},
+			{"otpauth://totp/alice?secret=" + secret + "&period=0", ErrInvalidPeriod},
+			{"otpauth://hotp/alice?secret=" + secret, ErrInvalidCounter},
+		}
+		for _, tt := range tests {
+			_, err := Parse(tt.input)
+			assert.ErrorIs(t, err, tt.err, tt.input)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+// Package passgen provides generation of random passwords and passphrases.
+//
+// Passwords are built from configurable character classes and passphrases from
+// the embedded EFF large wordlist. All randomness comes from crypto/rand.
+package passgen
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// Character classes used to build passwords.
+const (
+	// LowerChars contains lowercase ASCII letters.
+	LowerChars = "abcdefghijklmnopqrstuvwxyz"
+	// UpperChars contains uppercase ASCII letters.
+	UpperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// DigitChars contains decimal digits.
+	DigitChars = "0123456789"
+	// SymbolChars contains printable ASCII symbols.
+	SymbolChars = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	// AmbiguousChars contains characters that are easily confused with each other.
+	AmbiguousChars = "0O1lI|`'\""
+)
+
+// Length limits for passwords and passphrases.
+const (
+	// MinLength is the minimum password length.
+	MinLength = 4
+	// MaxLength is the maximum password length.
+	MaxLength = 1024
+	// MinWords is the minimum number of words in a passphrase.
+	MinWords = 3
+	// MaxWords is the maximum number of words in a passphrase.
+	MaxWords = 64
+)
+
+var (
+	// ErrInvalidLength is returned when the password length is out of range.
+	ErrInvalidLength = fmt.Errorf("password length must be between %d and %d", MinLength, MaxLength)
+
+	// ErrInvalidWords is returned when the number of passphrase words is out of range.
+	ErrInvalidWords = fmt.Errorf("number of words must be between %d and %d", MinWords, MaxWords)
+
+	// ErrNoCharacters is returned when no characters are left after applying the options.
+	ErrNoCharacters = errors.New("no characters available, enable at least one character class")
+
+	// ErrTooFewCharacters is returned when the length is too short to include every enabled class.
+	ErrTooFewCharacters = errors.New("password length is shorter than the number of enabled character classes")
+)
+
+// Options configures password generation.
+type Options struct {
+	// Length is the number of characters in the password.
+	Length int
+	// Lower enables lowercase letters.
+	Lower bool
+	// Upper enables uppercase letters.
+	Upper bool
+	// Digits enables decimal digits.
+	Digits bool
+	// Symbols enables printable ASCII symbols.
+	Symbols bool
+	// ExcludeAmbiguous removes characters listed in AmbiguousChars.
+	ExcludeAmbiguous bool
+	// Exclude lists additional characters that must not appear in the password.
+	Exclude string
+}
+
+// DefaultOptions returns options for a 20 character password using all character classes.
+func DefaultOptions() Options {
+	return Options{
+		Length:  20,
+		Lower:   true,
+		Upper:   true,
+		Digits:  true,
+		Symbols: true,
+	}
+}
+
+// classes returns the enabled character classes with excluded characters removed.
+// Classes that become empty are dropped.
+func (o Options) classes() []string {
+	exclude := o.Exclude
+	if o.ExcludeAmbiguous {
+		exclude += AmbiguousChars
+	}
+
+	var classes []string
+	for _, c := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{o.Lower, LowerChars},
+		{o.Upper, UpperChars},
+		{o.Digits, DigitChars},
+		{o.Symbols, SymbolChars},
+	} {
+		if !c.enabled {
+			continue
+		}
+		filtered := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(exclude, r) {
+				return -1
+			}
+			return r
+		}, c.chars)
+		if filtered != "" {
+			classes = append(classes, filtered)
+		}
+	}
+	return classes
+}
+
+// Password generates a random password.
+// The password contains at least one character of every enabled character class.
+func Password(opts Options) (string, error) {
+	if opts.Length < MinLength || opts.Length > MaxLength {
+		return "", ErrInvalidLength
+	}
+
+	classes := opts.classes()
+	if len(classes) == 0 {
+		return "", ErrNoCharacters
+	}
+	if opts.Length < len(classes) {
+		return "", ErrTooFewCharacters
+	}
+
+	pool := strings.Join(classes, "")
+	password := make([]byte, opts.Length)
+	for i := range password {
+		// The first characters are drawn from each class to guarantee coverage.
+		chars := pool
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		n, err := randInt(len(chars))
+		if err != nil {
+			return "", err
+		}
+		password[i] = chars[n]
+	}
+
+	if err := shuffle(password); err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// PasswordEntropy estimates the entropy in bits of a password generated with the given options.
+// Returns 0 if the options are invalid.
+func PasswordEntropy(opts Options) float64 {
+	pool := len(strings.Join(opts.classes(), ""))
+	if pool == 0 || opts.Length <= 0 {
+		return 0
+	}
+	return float64(opts.Length) * math.Log2(float64(pool))
+}
+
+// PassphraseOptions configures passphrase generation.
+type PassphraseOptions struct {
+	// Words is the number of words in the passphrase.
+	Words int
+	// Separator is placed between words.
+	Separator string
+	// Capitalize upper-cases the first letter of every word.
+	Capitalize bool
+	// Number appends a random digit to one of the words.
+	Number bool
+}
+
+// DefaultPassphraseOptions returns options for a six word passphrase separated by dashes.
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{
+		Words:     6,
+		Separator: "-",
+	}
+}
+
+//go:embed wordlist.txt
+var rawWordlist string
+
+// wordlist returns the embedded EFF large wordlist (7776 words).
+var wordlist = sync.OnceValue(func() []string {
+	return strings.Fields(rawWordlist)
+})
+
+// Words returns a copy of the embedded wordlist.
+func Words() []string {
+	list := wordlist()
+	words := make([]string, len(list))
+	copy(words, list)
+	return words
+}
+
+// Passphrase generates a random diceware-style passphrase from the embedded wordlist.
+func Passphrase(opts PassphraseOptions) (string, error) {
+	if opts.Words < MinWords || opts.Words > MaxWords {
+		return "", ErrInvalidWords
+	}
+
+	list := wordlist()
+	words := make([]string, opts.Words)
+	for i := range words {
+		n, err := randInt(len(list))
+		if err != nil {
+			return "", err
+		}
+		words[i] = list[n]
+		if opts.Capitalize {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+
+	if opts.Number {
+		i, err := randInt(len(words))
+		if err != nil {
+			return "", err
+		}
+		d, err := randInt(len(DigitChars))
+		if err != nil {
+			return "", err
+		}
+		words[i] += DigitChars[d : d+1]
+	}
+
+	return strings.Join(words, opts.Separator), nil
+}
+
+// PassphraseEntropy estimates the entropy in bits of a passphrase generated with the given options.
+// Returns 0 if the options are invalid.
+func PassphraseEntropy(opts PassphraseOptions) float64 {
+	if opts.Words <= 0 {
+		return 0
+	}
+	bits := float64(opts.Words) * math.Log2(float64(len(wordlist())))
+	if opts.Number {
+		bits += math.Log2(float64(opts.Words * len(DigitChars)))
+	}
+	return bits
+}
+
+// randInt returns a uniform random integer in [0, n).
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random number: %w", err)
+	}
+	return int(v.Int64()), nil
+}
+
+// shuffle permutes b in place using the Fisher-Yates algorithm.
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return nil
+}
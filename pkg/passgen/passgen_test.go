@@ -0,0 +1,114 @@
+package passgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassword(t *testing.T) {
+	t.Run("uses all enabled classes", func(t *testing.T) {
+		for range 50 {
+			password, err := Password(DefaultOptions())
+			require.NoError(t, err)
+			assert.Len(t, password, 20)
+			assert.True(t, strings.ContainsAny(password, LowerChars))
+			assert.True(t, strings.ContainsAny(password, UpperChars))
+			assert.True(t, strings.ContainsAny(password, DigitChars))
+			assert.True(t, strings.ContainsAny(password, SymbolChars))
+		}
+	})
+
+	t.Run("respects disabled classes", func(t *testing.T) {
+		password, err := Password(Options{Length: 32, Digits: true})
+		require.NoError(t, err)
+		assert.Len(t, password, 32)
+		assert.Empty(t, strings.Trim(password, DigitChars))
+	})
+
+	t.Run("excludes characters", func(t *testing.T) {
+		opts := Options{Length: 64, Lower: true, Digits: true, ExcludeAmbiguous: true, Exclude: "abc"}
+		for range 20 {
+			password, err := Password(opts)
+			require.NoError(t, err)
+			assert.False(t, strings.ContainsAny(password, "abc0l1"))
+		}
+	})
+
+	t.Run("generates different passwords", func(t *testing.T) {
+		first, err := Password(DefaultOptions())
+		require.NoError(t, err)
+		second, err := Password(DefaultOptions())
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		_, err := Password(Options{Length: 2, Lower: true})
+		assert.ErrorIs(t, err, ErrInvalidLength)
+
+		_, err = Password(Options{Length: 10})
+		assert.ErrorIs(t, err, ErrNoCharacters)
+
+		_, err = Password(Options{Length: 10, Digits: true, Exclude: DigitChars})
+		assert.ErrorIs(t, err, ErrNoCharacters)
+
+		_, err = Password(Options{Length: 4, Lower: true, Upper: true, Digits: true, Symbols: true, Exclude: "x"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	assert.InDelta(t, 20*4.7004, PasswordEntropy(Options{Length: 20, Lower: true}), 0.01)
+	assert.InDelta(t, 10*3.3219, PasswordEntropy(Options{Length: 10, Digits: true}), 0.01)
+	assert.Zero(t, PasswordEntropy(Options{Length: 10}))
+}
+
+func TestPassphrase(t *testing.T) {
+	t.Run("uses wordlist", func(t *testing.T) {
+		passphrase, err := Passphrase(DefaultPassphraseOptions())
+		require.NoError(t, err)
+
+		words := strings.Split(passphrase, "-")
+		// Some EFF words contain a dash themselves.
+		assert.GreaterOrEqual(t, len(words), 6)
+		for _, w := range words {
+			assert.NotEmpty(t, w)
+		}
+	})
+
+	t.Run("capitalizes and appends number", func(t *testing.T) {
+		passphrase, err := Passphrase(PassphraseOptions{Words: 4, Separator: " ", Capitalize: true, Number: true})
+		require.NoError(t, err)
+
+		words := strings.Split(passphrase, " ")
+		require.Len(t, words, 4)
+		for _, w := range words {
+			assert.Equal(t, strings.ToUpper(w[:1]), w[:1])
+		}
+		assert.True(t, strings.ContainsAny(passphrase, DigitChars))
+	})
+
+	t.Run("rejects invalid word count", func(t *testing.T) {
+		_, err := Passphrase(PassphraseOptions{Words: 2})
+		assert.ErrorIs(t, err, ErrInvalidWords)
+	})
+}
+
+func TestWordlist(t *testing.T) {
+	list := wordlist()
+	assert.Len(t, list, 7776)
+
+	seen := make(map[string]bool, len(list))
+	for _, w := range list {
+		assert.False(t, seen[w], w)
+		seen[w] = true
+	}
+}
+
+func TestPassphraseEntropy(t *testing.T) {
+	assert.InDelta(t, 6*12.925, PassphraseEntropy(DefaultPassphraseOptions()), 0.01)
+	assert.Zero(t, PassphraseEntropy(PassphraseOptions{}))
+}
@@ -0,0 +1,643 @@
+package strength
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// matchKind identifies the pattern a match was found by.
+type matchKind int
+
+const (
+	kindBruteforce matchKind = iota
+	kindDictionary
+	kindSpatial
+	kindRepeat
+	kindSequence
+	kindDate
+)
+
+// dictKind identifies the dictionary of a dictionary match.
+type dictKind int
+
+const (
+	dictCommon dictKind = iota
+	dictEnglish
+	dictUser
+)
+
+// match is a pattern found in the password between rune indexes i and j inclusive.
+type match struct {
+	kind     matchKind
+	i, j     int
+	token    string
+	guesses  float64
+	dict     dictKind
+	l33t     bool
+	reversed bool
+}
+
+const (
+	// bruteforceCardinality is the assumed number of candidates per unmatched character.
+	bruteforceCardinality = 10
+	// minDictionaryLength is the shortest token looked up in dictionaries.
+	minDictionaryLength = 3
+	// minSubmatchGuesses are lower bounds for the guesses of a single match.
+	minSubmatchGuessesSingleChar = 10
+	minSubmatchGuessesMultiChar  = 50
+	// minYearSpace is the smallest distance from the reference year used for dates.
+	minYearSpace = 20
+)
+
+// omnimatch returns all matches found in the password by every matcher.
+func omnimatch(password []rune, user map[string]int) []*match {
+	var matches []*match
+	matches = append(matches, dictionaryMatches(password, user)...)
+	matches = append(matches, spatialMatches(password)...)
+	matches = append(matches, repeatMatches(password, user)...)
+	matches = append(matches, sequenceMatches(password)...)
+	matches = append(matches, dateMatches(password)...)
+	return matches
+}
+
+// mostGuessableSequence finds the sequence of non-overlapping matches and brute-force
+// segments that covers the password with the fewest guesses.
+// Returns the sequence and log10 of its guesses.
+func mostGuessableSequence(password []rune, matches []*match) ([]*match, float64) {
+	n := len(password)
+	byEnd := make([][]*match, n)
+	for _, m := range matches {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	type step struct {
+		start int
+		m     *match
+	}
+	inf := math.Inf(1)
+	// best[k][l] is log10 of the product of guesses covering password[:k] with l matches.
+	best := make([][]float64, n+1)
+	prev := make([][]step, n+1)
+	for k := range best {
+		best[k] = make([]float64, n+1)
+		prev[k] = make([]step, n+1)
+		for l := range best[k] {
+			best[k][l] = inf
+		}
+	}
+	best[0][0] = 0
+
+	for k := 1; k <= n; k++ {
+		for l := 1; l <= k; l++ {
+			for _, m := range byEnd[k-1] {
+				if v := best[m.i][l-1] + math.Log10(matchGuesses(m)); v < best[k][l] {
+					best[k][l] = v
+					prev[k][l] = step{start: m.i, m: m}
+				}
+			}
+			for i := 0; i < k; i++ {
+				if v := best[i][l-1] + bruteforceLog10(k-i); v < best[k][l] {
+					best[k][l] = v
+					prev[k][l] = step{start: i}
+				}
+			}
+		}
+	}
+
+	// The l! term accounts for the attacker not knowing the order of patterns.
+	bestL, bestLog := 0, inf
+	for l := 1; l <= n; l++ {
+		lg, _ := math.Lgamma(float64(l + 1))
+		if v := best[n][l] + lg/math.Ln10; v < bestLog {
+			bestL, bestLog = l, v
+		}
+	}
+
+	var seq []*match
+	for k, l := n, bestL; k > 0; l-- {
+		s := prev[k][l]
+		m := s.m
+		if m == nil {
+			m = &match{kind: kindBruteforce, i: s.start, j: k - 1, token: string(password[s.start:k])}
+		}
+		seq = append([]*match{m}, seq...)
+		k = s.start
+	}
+	return seq, bestLog
+}
+
+// matchGuesses returns the guesses of a match bounded below by the minimum submatch guesses.
+func matchGuesses(m *match) float64 {
+	minGuesses := float64(minSubmatchGuessesMultiChar)
+	if m.j == m.i {
+		minGuesses = minSubmatchGuessesSingleChar
+	}
+	return math.Max(m.guesses, minGuesses)
+}
+
+// bruteforceLog10 returns log10 of the guesses for n unmatched characters.
+func bruteforceLog10(n int) float64 {
+	minGuesses := float64(minSubmatchGuessesMultiChar + 1)
+	if n == 1 {
+		minGuesses = minSubmatchGuessesSingleChar + 1
+	}
+	return math.Max(float64(n), math.Log10(minGuesses))
+}
+
+// l33tTables map common character substitutions back to letters.
+// Characters like '1' are ambiguous, so several tables are tried.
+var l33tTables = []map[rune]rune{
+	{'4': 'a', '@': 'a', '8': 'b', '(': 'c', '{': 'c', '[': 'c', '<': 'c', '3': 'e', '6': 'g', '9': 'g',
+		'1': 'i', '!': 'i', '|': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '%': 'x', '2': 'z'},
+	{'4': 'a', '@': 'a', '3': 'e', '1': 'l', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't'},
+}
+
+// dictionaryMatches finds dictionary words, including reversed and l33t-speak variants.
+func dictionaryMatches(password []rune, user map[string]int) []*match {
+	d := builtin()
+	dicts := []struct {
+		kind  dictKind
+		ranks map[string]int
+	}{
+		{dictCommon, d.common},
+		{dictEnglish, d.english},
+		{dictUser, user},
+	}
+
+	// Runes are lowered one by one so that indexes keep matching the password.
+	n := len(password)
+	lower := make([]rune, n)
+	for i, r := range password {
+		lower[i] = unicode.ToLower(r)
+	}
+	var matches []*match
+
+	lookup := func(candidate []rune, reversed, l33t bool) {
+		for i := 0; i < n; i++ {
+			for j := i + minDictionaryLength - 1; j < n; j++ {
+				word := string(candidate[i : j+1])
+				for _, dict := range dicts {
+					rank, ok := dict.ranks[word]
+					if !ok {
+						continue
+					}
+					start, end := i, j
+					if reversed {
+						start, end = n-1-j, n-1-i
+					}
+					token := string(password[start : end+1])
+					if l33t && word == string(lower[start:end+1]) {
+						continue
+					}
+					m := &match{
+						kind:     kindDictionary,
+						i:        start,
+						j:        end,
+						token:    token,
+						dict:     dict.kind,
+						l33t:     l33t,
+						reversed: reversed,
+					}
+					m.guesses = float64(rank) * uppercaseVariations(token)
+					if l33t {
+						m.guesses *= l33tVariations(token, word)
+					}
+					if reversed {
+						m.guesses *= 2
+					}
+					matches = append(matches, m)
+				}
+			}
+		}
+	}
+
+	lookup(lower, false, false)
+	reversed := make([]rune, n)
+	for i, r := range lower {
+		reversed[n-1-i] = r
+	}
+	lookup(reversed, true, false)
+
+	for _, table := range l33tTables {
+		subbed := make([]rune, n)
+		changed := false
+		for i, r := range lower {
+			if s, ok := table[r]; ok {
+				subbed[i] = s
+				changed = true
+			} else {
+				subbed[i] = r
+			}
+		}
+		if changed {
+			lookup(subbed, false, true)
+		}
+	}
+	return matches
+}
+
+// uppercaseVariations returns the number of ways the letters of a word could be capitalized
+// to produce the token.
+func uppercaseVariations(token string) float64 {
+	var upper, lower int
+	for _, r := range token {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	runes := []rune(token)
+	first, last := runes[0], runes[len(runes)-1]
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(first) || unicode.IsUpper(last))) {
+		return 2
+	}
+	var variations float64
+	for i := 1; i <= min(upper, lower); i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+// l33tVariations returns the number of ways substitutions could have been applied to the word.
+func l33tVariations(token, word string) float64 {
+	tokenRunes, wordRunes := []rune(token), []rune(word)
+	// subs maps each substituted character to the letter it stands for.
+	subs := make(map[rune]rune)
+	for i, r := range wordRunes {
+		if c := unicode.ToLower(tokenRunes[i]); c != r {
+			subs[c] = r
+		}
+	}
+	variations := 1.0
+	for sub, letter := range subs {
+		var s, u int
+		for _, r := range tokenRunes {
+			switch unicode.ToLower(r) {
+			case sub:
+				s++
+			case letter:
+				u++
+			}
+		}
+		if u == 0 {
+			variations *= 2
+			continue
+		}
+		var v float64
+		for i := 1; i <= min(s, u); i++ {
+			v += binomial(s+u, i)
+		}
+		variations *= v
+	}
+	return variations
+}
+
+// keyboard is the US QWERTY layout as unshifted and shifted rows.
+// Rows are slanted: key (r, c) touches (r-1, c) and (r-1, c+1) above and (r+1, c-1) and (r+1, c) below.
+var keyboard = [][2]string{
+	{"1234567890-=", "!@#$%^&*()_+"},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+	{"asdfghjkl;'", "ASDFGHJKL:\""},
+	{"zxcvbnm,./", "ZXCVBNM<>?"},
+}
+
+// keyPosition is the location of a character on the keyboard.
+type keyPosition struct {
+	row, col int
+	shifted  bool
+}
+
+var keyPositions = func() map[rune]keyPosition {
+	pos := make(map[rune]keyPosition)
+	for row, keys := range keyboard {
+		for col, r := range []rune(keys[0]) {
+			pos[r] = keyPosition{row: row, col: col}
+		}
+		for col, r := range []rune(keys[1]) {
+			pos[r] = keyPosition{row: row, col: col, shifted: true}
+		}
+	}
+	return pos
+}()
+
+// keyboardStartingPositions and keyboardAverageDegree describe the keyboard graph.
+const (
+	keyboardStartingPositions = 47
+	keyboardAverageDegree     = 4.6
+)
+
+// direction returns the direction from key a to an adjacent key b or -1 if they are not adjacent.
+func direction(a, b keyPosition) int {
+	dr, dc := b.row-a.row, b.col-a.col
+	switch {
+	case dr == 0 && dc == -1:
+		return 0
+	case dr == 0 && dc == 1:
+		return 1
+	case dr == -1 && dc == 0:
+		return 2
+	case dr == -1 && dc == 1:
+		return 3
+	case dr == 1 && dc == -1:
+		return 4
+	case dr == 1 && dc == 0:
+		return 5
+	default:
+		return -1
+	}
+}
+
+// spatialMatches finds runs of at least three adjacent keys.
+func spatialMatches(password []rune) []*match {
+	var matches []*match
+	n := len(password)
+	for i := 0; i < n-2; {
+		j, turns, shifted, lastDir := i, 0, 0, -1
+		first, ok := keyPositions[password[i]]
+		if !ok {
+			i++
+			continue
+		}
+		if first.shifted {
+			shifted++
+		}
+		cur := first
+		for j+1 < n {
+			next, ok := keyPositions[password[j+1]]
+			if !ok {
+				break
+			}
+			dir := direction(cur, next)
+			if dir < 0 {
+				break
+			}
+			if dir != lastDir {
+				turns++
+				lastDir = dir
+			}
+			if next.shifted {
+				shifted++
+			}
+			cur = next
+			j++
+		}
+		if j-i+1 >= 3 {
+			matches = append(matches, &match{
+				kind:    kindSpatial,
+				i:       i,
+				j:       j,
+				token:   string(password[i : j+1]),
+				guesses: spatialGuesses(j-i+1, turns, shifted),
+			})
+			i = j
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// spatialGuesses estimates the guesses for a keyboard pattern of the given length,
+// number of turns and shifted characters.
+func spatialGuesses(length, turns, shifted int) float64 {
+	var guesses float64
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= min(turns, i-1); j++ {
+			guesses += binomial(i-1, j-1) * keyboardStartingPositions * math.Pow(keyboardAverageDegree, float64(j))
+		}
+	}
+	if shifted > 0 {
+		unshifted := length - shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			var v float64
+			for i := 1; i <= min(shifted, unshifted); i++ {
+				v += binomial(shifted+unshifted, i)
+			}
+			guesses *= v
+		}
+	}
+	return guesses
+}
+
+// repeatMatches finds repeated characters and repeated blocks like "abcabc".
+// At every position the shortest repeating block is used and scanning resumes after the match.
+func repeatMatches(password []rune, user map[string]int) []*match {
+	var matches []*match
+	blockGuesses := make(map[string]float64)
+	n := len(password)
+	for i := 0; i < n; {
+		var found *match
+		for size := 1; i+2*size <= n && found == nil; size++ {
+			block := string(password[i : i+size])
+			reps := 1
+			for i+(reps+1)*size <= n && string(password[i+reps*size:i+(reps+1)*size]) == block {
+				reps++
+			}
+			if reps < 2 || (size == 1 && reps < 3) {
+				continue
+			}
+			base, ok := blockGuesses[block]
+			if !ok {
+				if size == 1 {
+					base = minSubmatchGuessesSingleChar + 1
+				} else {
+					runes := []rune(block)
+					_, lg := mostGuessableSequence(runes, omnimatch(runes, user))
+					base = math.Pow(10, lg)
+				}
+				blockGuesses[block] = base
+			}
+			end := i + reps*size - 1
+			found = &match{
+				kind:    kindRepeat,
+				i:       i,
+				j:       end,
+				token:   string(password[i : end+1]),
+				guesses: base * float64(reps),
+			}
+		}
+		if found == nil {
+			i++
+			continue
+		}
+		matches = append(matches, found)
+		i = found.j + 1
+	}
+	return matches
+}
+
+// sequenceMatches finds runs of at least three characters with a constant small step, like "abc" or "9753".
+func sequenceMatches(password []rune) []*match {
+	const maxDelta = 5
+	var matches []*match
+	n := len(password)
+	for i := 0; i < n-2; {
+		delta := int(password[i+1]) - int(password[i])
+		if delta == 0 || delta < -maxDelta || delta > maxDelta {
+			i++
+			continue
+		}
+		j := i + 1
+		for j+1 < n && int(password[j+1])-int(password[j]) == delta {
+			j++
+		}
+		if j-i+1 < 3 {
+			i++
+			continue
+		}
+
+		token := string(password[i : j+1])
+		first := password[i]
+		var base float64
+		switch {
+		case strings.ContainsRune("aAzZ019", first):
+			base = 4
+		case unicode.IsDigit(first):
+			base = 10
+		default:
+			base = 26
+		}
+		if delta < 0 {
+			base *= 2
+		}
+		matches = append(matches, &match{
+			kind:    kindSequence,
+			i:       i,
+			j:       j,
+			token:   token,
+			guesses: base * float64(j-i+1),
+		})
+		i = j
+	}
+	return matches
+}
+
+// dateMatches finds years and dates with or without separators.
+func dateMatches(password []rune) []*match {
+	var matches []*match
+	n := len(password)
+	for i := 0; i < n; i++ {
+		for j := i + 3; j < n && j-i < 10; j++ {
+			token := string(password[i : j+1])
+			year, ok := parseDate(token)
+			if !ok {
+				continue
+			}
+			space := math.Max(math.Abs(float64(year-time.Now().Year())), minYearSpace)
+			guesses := space
+			if len(token) > 4 {
+				guesses *= 365
+				if strings.ContainsFunc(token, func(r rune) bool { return !unicode.IsDigit(r) }) {
+					guesses *= 4
+				}
+			}
+			matches = append(matches, &match{kind: kindDate, i: i, j: j, token: token, guesses: guesses})
+		}
+	}
+	return matches
+}
+
+// parseDate recognizes a four digit year or a day, month and year in any common order,
+// optionally separated by one of " /\_.-". Returns the year.
+func parseDate(token string) (int, bool) {
+	if len(token) == 4 && isDigits(token) {
+		y := atoi(token)
+		return y, y >= 1900 && y <= 2099
+	}
+
+	var parts []string
+	if isDigits(token) {
+		switch len(token) {
+		case 6:
+			parts = []string{token[:2], token[2:4], token[4:]}
+		case 8:
+			for _, split := range [][3]int{{2, 4, 8}, {4, 6, 8}} {
+				p := []string{token[:split[0]], token[split[0]:split[1]], token[split[1]:split[2]]}
+				if y, ok := validDate(p); ok {
+					return y, true
+				}
+			}
+			return 0, false
+		default:
+			return 0, false
+		}
+	} else {
+		sep := strings.IndexAny(token, " /\\_.-")
+		if sep <= 0 {
+			return 0, false
+		}
+		parts = strings.Split(token, token[sep:sep+1])
+		if len(parts) != 3 {
+			return 0, false
+		}
+		for _, p := range parts {
+			if p == "" || len(p) > 4 || !isDigits(p) {
+				return 0, false
+			}
+		}
+	}
+	return validDate(parts)
+}
+
+// validDate checks the orders year-month-day, day-month-year and month-day-year.
+func validDate(parts []string) (int, bool) {
+	orders := [][3]int{{0, 1, 2}, {2, 1, 0}, {2, 0, 1}}
+	for _, o := range orders {
+		y, m, d := parts[o[0]], parts[o[1]], parts[o[2]]
+		if len(m) > 2 || len(d) > 2 || (len(y) != 2 && len(y) != 4) {
+			continue
+		}
+		year, month, day := atoi(y), atoi(m), atoi(d)
+		if len(y) == 2 {
+			if year > 50 {
+				year += 1900
+			} else {
+				year += 2000
+			}
+		}
+		if year >= 1900 && year <= 2099 && month >= 1 && month <= 12 && day >= 1 && day <= 31 {
+			return year, true
+		}
+	}
+	return 0, false
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// atoi converts a string of ASCII digits to an integer.
+func atoi(s string) int {
+	var v int
+	for _, r := range s {
+		v = v*10 + int(r-'0')
+	}
+	return v
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r *= float64(n-k+i) / float64(i)
+	}
+	return r
+}
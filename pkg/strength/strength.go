@@ -0,0 +1,218 @@
+// Package strength estimates password strength in the style of zxcvbn.
+//
+// A password is split into the most guessable sequence of patterns such as common
+// passwords, dictionary words, keyboard rows, sequences, repeats and dates. The number
+// of guesses needed to find that sequence is converted into a score from 0 to 4.
+package strength
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Pro100x3mal/gophkeeper/pkg/passgen"
+)
+
+// Scores returned by Estimate.
+const (
+	// ScoreTooGuessable means the password falls to an online attack with throttling (< 10^3 guesses).
+	ScoreTooGuessable = 0
+	// ScoreVeryGuessable means the password falls to an unthrottled online attack (< 10^6 guesses).
+	ScoreVeryGuessable = 1
+	// ScoreSomewhatGuessable means the password resists online attacks (< 10^8 guesses).
+	ScoreSomewhatGuessable = 2
+	// ScoreSafelyUnguessable means the password resists offline attacks on slow hashes (< 10^10 guesses).
+	ScoreSafelyUnguessable = 3
+	// ScoreVeryUnguessable means the password resists offline attacks on fast hashes.
+	ScoreVeryUnguessable = 4
+)
+
+// MinAcceptableScore is the lowest score that is not reported as weak.
+const MinAcceptableScore = ScoreSafelyUnguessable
+
+// maxAnalyzedLength limits pattern matching; the remaining characters are counted as brute force.
+const maxAnalyzedLength = 100
+
+// Result describes the estimated strength of a password.
+type Result struct {
+	// Score is the strength on a scale from 0 (too guessable) to 4 (very unguessable).
+	Score int
+	// Guesses is the estimated number of guesses needed to find the password.
+	Guesses float64
+	// Entropy is the base-2 logarithm of Guesses in bits.
+	Entropy float64
+	// Warning explains the main weakness of the password (empty for strong passwords).
+	Warning string
+	// Suggestions contains hints for choosing a stronger password.
+	Suggestions []string
+}
+
+// Weak reports whether the score is below MinAcceptableScore.
+func (r Result) Weak() bool {
+	return r.Score < MinAcceptableScore
+}
+
+//go:embed common.txt
+var rawCommon string
+
+// dictionaries holds the ranked built-in dictionaries.
+type dictionaries struct {
+	common  map[string]int
+	english map[string]int
+}
+
+// englishRank is the rank assigned to every dictionary word.
+// The embedded wordlist is not ordered by frequency, so all words share one rank.
+const englishRank = 3000
+
+var builtin = sync.OnceValue(func() dictionaries {
+	d := dictionaries{common: make(map[string]int), english: make(map[string]int)}
+	for i, w := range strings.Fields(rawCommon) {
+		if _, ok := d.common[w]; !ok {
+			d.common[w] = i + 1
+		}
+	}
+	for _, w := range passgen.Words() {
+		d.english[w] = englishRank
+	}
+	return d
+})
+
+// IsCommon reports whether the password is on the built-in list of common passwords.
+// The comparison is case-insensitive.
+func IsCommon(password string) bool {
+	_, ok := builtin().common[strings.ToLower(password)]
+	return ok
+}
+
+// Estimate estimates the strength of a password.
+// userInputs are values related to the password owner, e.g. the username or
+// item title; passwords built from them are penalized.
+func Estimate(password string, userInputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Result{
+			Score:       ScoreTooGuessable,
+			Guesses:     1,
+			Warning:     "Password is empty",
+			Suggestions: []string{"Use a few words, avoid common phrases"},
+		}
+	}
+
+	analyzed, rest := runes, 0
+	if len(runes) > maxAnalyzedLength {
+		analyzed, rest = runes[:maxAnalyzedLength], len(runes)-maxAnalyzedLength
+	}
+
+	seq, log10Guesses := mostGuessableSequence(analyzed, omnimatch(analyzed, userDictionary(userInputs)))
+	log10Guesses += float64(rest) * math.Log10(bruteforceCardinality)
+
+	res := Result{
+		Score:   score(log10Guesses),
+		Guesses: math.Pow(10, log10Guesses),
+		Entropy: log10Guesses * math.Log2(10),
+	}
+	res.Warning, res.Suggestions = feedback(res.Score, seq)
+	return res
+}
+
+// score converts the number of guesses into a score.
+func score(log10Guesses float64) int {
+	const delta = 5
+	guesses := math.Pow(10, log10Guesses)
+	switch {
+	case guesses < 1e3+delta:
+		return ScoreTooGuessable
+	case guesses < 1e6+delta:
+		return ScoreVeryGuessable
+	case guesses < 1e8+delta:
+		return ScoreSomewhatGuessable
+	case guesses < 1e10+delta:
+		return ScoreSafelyUnguessable
+	default:
+		return ScoreVeryUnguessable
+	}
+}
+
+// userDictionary builds a ranked dictionary from user inputs and their alphanumeric parts.
+func userDictionary(inputs []string) map[string]int {
+	dict := make(map[string]int)
+	rank := 1
+	add := func(w string) {
+		if utf8.RuneCountInString(w) < minDictionaryLength {
+			return
+		}
+		if _, ok := dict[w]; !ok {
+			dict[w] = rank
+			rank++
+		}
+	}
+	for _, input := range inputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		add(input)
+		for _, part := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(part)
+		}
+	}
+	return dict
+}
+
+// feedback returns a warning and suggestions for the weakest part of the password.
+func feedback(score int, seq []*match) (string, []string) {
+	if score >= MinAcceptableScore {
+		return "", nil
+	}
+
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	var longest *match
+	for _, m := range seq {
+		if longest == nil || m.j-m.i > longest.j-longest.i {
+			longest = m
+		}
+	}
+	if longest == nil {
+		return "", suggestions
+	}
+
+	var warning string
+	switch longest.kind {
+	case kindDictionary:
+		switch {
+		case longest.dict == dictCommon && len(seq) == 1 && !longest.l33t && !longest.reversed:
+			warning = "This is a very common password"
+		case longest.dict == dictCommon:
+			warning = "This is similar to a commonly used password"
+		case longest.dict == dictUser:
+			warning = "Passwords based on your username or item title are easy to guess"
+		case len(seq) == 1:
+			warning = "A word by itself is easy to guess"
+		}
+		if longest.token != strings.ToLower(longest.token) {
+			suggestions = append(suggestions, "Capitalization doesn't help very much")
+		}
+		if longest.reversed {
+			suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+		}
+		if longest.l33t {
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		}
+	case kindSpatial:
+		warning = "Keyboard patterns like qwerty are easy to guess"
+		suggestions = append(suggestions, "Use a longer keyboard pattern with more turns")
+	case kindRepeat:
+		warning = `Repeats like "aaa" or "abcabc" are easy to guess`
+		suggestions = append(suggestions, "Avoid repeated words and characters")
+	case kindSequence:
+		warning = "Sequences like abc or 6543 are easy to guess"
+		suggestions = append(suggestions, "Avoid sequences")
+	case kindDate:
+		warning = "Dates and years are often easy to guess"
+		suggestions = append(suggestions, "Avoid dates and years that are associated with you")
+	}
+	return warning, suggestions
+}
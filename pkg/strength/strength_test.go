@@ -0,0 +1,130 @@
+package strength
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Pro100x3mal/gophkeeper/pkg/passgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		inputs   []string
+		maxScore int
+		warning  string
+	}{
+		{
+			name:     "common password",
+			password: "password",
+			maxScore: ScoreTooGuessable,
+			warning:  "This is a very common password",
+		},
+		{
+			name:     "l33t common password",
+			password: "p@ssw0rd",
+			maxScore: ScoreTooGuessable,
+			warning:  "This is similar to a commonly used password",
+		},
+		{
+			name:     "reversed common password",
+			password: "drowssap",
+			maxScore: ScoreTooGuessable,
+			warning:  "This is similar to a commonly used password",
+		},
+		{
+			name:     "keyboard pattern",
+			password: "zxcvbnm,./",
+			maxScore: ScoreVeryGuessable,
+			warning:  "Keyboard patterns like qwerty are easy to guess",
+		},
+		{
+			name:     "sequence",
+			password: "lmnopqrs",
+			maxScore: ScoreTooGuessable,
+			warning:  "Sequences like abc or 6543 are easy to guess",
+		},
+		{
+			name:     "repeat",
+			password: "xyzqxyzqxyzq",
+			maxScore: ScoreVeryGuessable,
+			warning:  `Repeats like "aaa" or "abcabc" are easy to guess`,
+		},
+		{
+			name:     "date",
+			password: "14.07.1989",
+			maxScore: ScoreVeryGuessable,
+			warning:  "Dates and years are often easy to guess",
+		},
+		{
+			name:     "user input",
+			password: "jsmith2024",
+			inputs:   []string{"jsmith"},
+			maxScore: ScoreVeryGuessable,
+			warning:  "Passwords based on your username or item title are easy to guess",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Estimate(tt.password, tt.inputs...)
+			assert.LessOrEqual(t, res.Score, tt.maxScore)
+			assert.True(t, res.Weak())
+			assert.Equal(t, tt.warning, res.Warning)
+			assert.NotEmpty(t, res.Suggestions)
+		})
+	}
+}
+
+func TestEstimate_Strong(t *testing.T) {
+	password, err := passgen.Password(passgen.DefaultOptions())
+	require.NoError(t, err)
+	res := Estimate(password)
+	assert.Equal(t, ScoreVeryUnguessable, res.Score)
+	assert.False(t, res.Weak())
+	assert.Empty(t, res.Warning)
+	assert.Empty(t, res.Suggestions)
+
+	passphrase, err := passgen.Passphrase(passgen.DefaultPassphraseOptions())
+	require.NoError(t, err)
+	assert.Equal(t, ScoreVeryUnguessable, Estimate(passphrase).Score)
+}
+
+func TestEstimate_UserInputsLowerScore(t *testing.T) {
+	password := "harborview"
+	without := Estimate(password)
+	with := Estimate(password, "Harbor View")
+	assert.Less(t, with.Guesses, without.Guesses)
+}
+
+func TestEstimate_Empty(t *testing.T) {
+	res := Estimate("")
+	assert.Equal(t, ScoreTooGuessable, res.Score)
+	assert.Equal(t, "Password is empty", res.Warning)
+}
+
+func TestEstimate_Long(t *testing.T) {
+	res := Estimate(strings.Repeat("a", 500))
+	assert.Equal(t, ScoreVeryUnguessable, res.Score)
+
+	res = Estimate(strings.Repeat("İı", 60))
+	assert.NotZero(t, res.Guesses)
+}
+
+func TestIsCommon(t *testing.T) {
+	assert.True(t, IsCommon("password"))
+	assert.True(t, IsCommon("QWERTY"))
+	assert.True(t, IsCommon("123456"))
+	assert.False(t, IsCommon("correct-horse-battery-staple"))
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, ScoreTooGuessable, score(2))
+	assert.Equal(t, ScoreVeryGuessable, score(5))
+	assert.Equal(t, ScoreSomewhatGuessable, score(7))
+	assert.Equal(t, ScoreSafelyUnguessable, score(9))
+	assert.Equal(t, ScoreVeryUnguessable, score(11))
+}